@@ -41,6 +41,10 @@ const (
 // AppSec is disabled or the given context is incorrect.
 // Note that passing the raw bytes of the HTTP request body is not expected and would
 // result in inaccurate attack detection.
+// JSON, URL-encoded form, multipart form and XML request bodies can instead be
+// automatically parsed and monitored before the request handler runs by setting the
+// environment variable DD_APPSEC_BODY_PARSING_ENABLED to true (see also
+// DD_APPSEC_BODY_PARSING_SIZE_LIMIT).
 // This function always returns nil when appsec is disabled.
 func MonitorParsedHTTPBody(ctx context.Context, body any) error {
 	if !appsec.Enabled() {
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sarama
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
+)
+
+// startConsumeOperation starts the AppSec monitoring of the given consumed message, whose consume span is given.
+// It returns nil when AppSec is disabled.
+func startConsumeOperation(span *tracer.Span, msg *sarama.ConsumerMessage) *messagesec.ConsumeOperation {
+	if !instr.AppSecEnabled() {
+		return nil
+	}
+	headers := make(map[string][]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		if h == nil {
+			continue
+		}
+		headers[string(h.Key)] = append(headers[string(h.Key)], string(h.Value))
+	}
+	_, op := messagesec.StartConsumeOperation(context.Background(), span, messageKey(msg), messagesec.ConsumeOperationArgs{
+		System:  messagesec.SystemKafka,
+		Topic:   msg.Topic,
+		Headers: headers,
+		Key:     string(msg.Key),
+		Value:   messagesec.DecodeValue(msg.Value),
+	})
+	return op
+}
+
+func messageKey(msg *sarama.ConsumerMessage) messagesec.MessageKey {
+	return messagesec.MessageKey{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+	}
+}
+
+// ContextWithMessage returns a copy of ctx holding the AppSec monitoring of the given message consumed by a wrapped
+// consumer, so that the AppSec protections, such as the RASP ones, apply to its processing until the next message is
+// consumed. ctx is returned as is when AppSec is disabled or the message is not being consumed.
+func ContextWithMessage(ctx context.Context, msg *sarama.ConsumerMessage) context.Context {
+	return messagesec.ContextWithMessage(ctx, messageKey(msg))
+}
@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sarama
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+type chanDispatcher chan *sarama.ConsumerMessage
+
+func (d chanDispatcher) Messages() <-chan *sarama.ConsumerMessage {
+	return d
+}
+
+func TestAppSec(t *testing.T) {
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	cfg := new(config)
+	defaults(cfg)
+	msgs := make(chanDispatcher)
+	d := wrapDispatcher(msgs, cfg)
+	go d.Run()
+
+	go func() {
+		msgs <- &sarama.ConsumerMessage{
+			Topic:     "comments",
+			Partition: 1,
+			Offset:    42,
+			Key:       []byte("user-1"),
+			Value:     []byte(`{"comment":"<script>alert(1)</script>"}`),
+		}
+	}()
+
+	msg := <-d.Messages()
+	ctx := ContextWithMessage(context.Background(), msg)
+	_, found := dyngo.FindOperation[messagesec.ConsumeOperation](ctx)
+	require.True(t, found, "the consume operation should be found while the message is being processed")
+
+	// closing the dispatched messages finishes the consume span of the last message
+	close(msgs)
+	for range d.Messages() {
+	}
+
+	spans := mt.FinishedSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, float64(1), spans[0].Tag("_dd.appsec.enabled"))
+	require.Contains(t, spans[0].Tag("_dd.appsec.json"), "crs-941-110")
+
+	_, found = dyngo.FindOperation[messagesec.ConsumeOperation](ContextWithMessage(context.Background(), msg))
+	require.False(t, found, "the consume operation should not be found once the message was processed")
+}
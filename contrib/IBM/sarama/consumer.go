@@ -70,6 +70,23 @@ func WrapConsumer(c sarama.Consumer, opts ...Option) sarama.Consumer {
 	}
 }
 
+// TrackConsumerSchema tracks the usage of the given schema, such as an Avro or Protobuf schema of a schema registry,
+// to deserialize the value of msg, when Data Streams Monitoring is enabled by opts or by the environment.
+func TrackConsumerSchema(msg *sarama.ConsumerMessage, schema options.Schema, opts ...Option) {
+	if msg == nil {
+		return
+	}
+	cfg := new(config)
+	defaults(cfg)
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	if !cfg.dataStreamsEnabled {
+		return
+	}
+	tracer.TrackDataStreamsSchema(msg.Topic, options.SchemaOperationDeserialization, schema)
+}
+
 func setConsumeCheckpoint(enabled bool, groupID string, msg *sarama.ConsumerMessage) {
 	if !enabled || msg == nil {
 		return
@@ -92,6 +109,17 @@ func setConsumeCheckpoint(enabled bool, groupID string, msg *sarama.ConsumerMess
 	}
 }
 
+// trackHighWatermarkOffset tracks the high watermark offset of the partition of msg, when d is a
+// sarama.PartitionConsumer or a sarama.ConsumerGroupClaim, which both provide it.
+func trackHighWatermarkOffset(enabled bool, d dispatcher, msg *sarama.ConsumerMessage) {
+	if !enabled || msg == nil {
+		return
+	}
+	if pc, ok := d.(interface{ HighWaterMarkOffset() int64 }); ok {
+		tracer.TrackKafkaHighWatermarkOffset("", msg.Topic, msg.Partition, pc.HighWaterMarkOffset())
+	}
+}
+
 func getConsumerMsgSize(msg *sarama.ConsumerMessage) (size int64) {
 	for _, header := range msg.Headers {
 		size += int64(len(header.Key) + len(header.Value))
@@ -106,3 +106,23 @@ func TestWrapConsumer(t *testing.T) {
 		assertDSMConsumerPathway(t, topic, "", msg2, false)
 	}
 }
+
+type highWatermarkDispatcher struct {
+	dispatcher
+	highWatermark int64
+}
+
+func (d highWatermarkDispatcher) HighWaterMarkOffset() int64 {
+	return d.highWatermark
+}
+
+func TestTrackHighWatermarkOffset(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	trackHighWatermarkOffset(true, highWatermarkDispatcher{highWatermark: 42}, &sarama.ConsumerMessage{Topic: "topic", Partition: 2, Offset: 40})
+	assert.Contains(t, mt.SentDSMBacklogs(), mocktracer.DSMBacklog{
+		Tags:  []string{"partition:2", "topic:topic", "type:kafka_high_watermark"},
+		Value: 42,
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
 )
 
 type dispatcher interface {
@@ -40,7 +41,10 @@ func (w *wrappedDispatcher) Messages() <-chan *sarama.ConsumerMessage {
 
 func (w *wrappedDispatcher) Run() {
 	msgs := w.d.Messages()
-	var prev *tracer.Span
+	var (
+		prev   *tracer.Span
+		prevOp *messagesec.ConsumeOperation
+	)
 
 	for msg := range msgs {
 		// create the next span from the message
@@ -71,18 +75,26 @@ func (w *wrappedDispatcher) Run() {
 		next := tracer.StartSpan(w.cfg.consumerSpanName, opts...)
 		// reinject the span context so consumers can pick it up
 		tracer.Inject(next.Context(), carrier)
+		nextOp := startConsumeOperation(next, msg)
 		setConsumeCheckpoint(w.cfg.dataStreamsEnabled, w.cfg.groupID, msg)
+		trackHighWatermarkOffset(w.cfg.dataStreamsEnabled, w.d, msg)
 		w.messages <- msg
 
 		// if the next message was received, finish the previous span
-		if prev != nil {
-			prev.Finish()
-		}
-		prev = next
+		finishConsume(prev, prevOp)
+		prev, prevOp = next, nextOp
 	}
 	// finish any remaining span
-	if prev != nil {
-		prev.Finish()
-	}
+	finishConsume(prev, prevOp)
 	close(w.messages)
 }
+
+// finishConsume finishes the given consume span and its AppSec operation, if any.
+func finishConsume(span *tracer.Span, op *messagesec.ConsumeOperation) {
+	if op != nil {
+		op.Finish()
+	}
+	if span != nil {
+		span.Finish()
+	}
+}
@@ -239,6 +239,23 @@ func setProduceCheckpoint(enabled bool, msg *sarama.ProducerMessage, version sar
 	datastreams.InjectToBase64Carrier(ctx, carrier)
 }
 
+// TrackProducerSchema tracks the usage of the given schema, such as an Avro or Protobuf schema of a schema registry,
+// to serialize the value of msg, when Data Streams Monitoring is enabled by opts or by the environment.
+func TrackProducerSchema(msg *sarama.ProducerMessage, schema options.Schema, opts ...Option) {
+	if msg == nil {
+		return
+	}
+	cfg := new(config)
+	defaults(cfg)
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	if !cfg.dataStreamsEnabled {
+		return
+	}
+	tracer.TrackDataStreamsSchema(msg.Topic, options.SchemaOperationSerialization, schema)
+}
+
 func getProducerMsgSize(msg *sarama.ProducerMessage) (size int64) {
 	for _, header := range msg.Headers {
 		size += int64(len(header.Key) + len(header.Value))
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 )
@@ -193,3 +194,24 @@ func TestWrapAsyncProducer(t *testing.T) {
 		}
 	})
 }
+
+func TestTrackSchema(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	schema := options.Schema{ID: "1", Name: "User", Type: "avro"}
+	// Schemas are not tracked when Data Streams Monitoring is disabled
+	TrackProducerSchema(&sarama.ProducerMessage{Topic: "topic"}, schema)
+	TrackConsumerSchema(&sarama.ConsumerMessage{Topic: "topic"}, schema)
+	assert.Empty(t, mt.SentDSMSchemas())
+
+	for i := 0; i < 3; i++ {
+		TrackProducerSchema(&sarama.ProducerMessage{Topic: "topic"}, schema, WithDataStreams())
+	}
+	TrackConsumerSchema(&sarama.ConsumerMessage{Topic: "topic"}, schema, WithDataStreams())
+
+	assert.ElementsMatch(t, []mocktracer.DSMSchemaUsage{
+		{Topic: "topic", Operation: "serialization", ID: "1", Name: "User", Type: "avro", Weight: 1},
+		{Topic: "topic", Operation: "deserialization", ID: "1", Name: "User", Type: "avro", Weight: 1},
+	}, mt.SentDSMSchemas())
+}
@@ -86,6 +86,9 @@ func WrapPartitionConsumer(pc sarama.PartitionConsumer, opts ...Option) sarama.P
 			// reinject the span context so consumers can pick it up
 			tracer.Inject(next.Context(), carrier)
 			setConsumeCheckpoint(cfg.dataStreamsEnabled, cfg.groupID, msg)
+			if cfg.dataStreamsEnabled {
+				tracer.TrackKafkaHighWatermarkOffset("", msg.Topic, msg.Partition, pc.HighWaterMarkOffset())
+			}
 
 			wrapped.messages <- msg
 
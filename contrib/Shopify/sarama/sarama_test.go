@@ -113,6 +113,14 @@ func TestConsumer(t *testing.T) {
 		assert.NotEqual(t, expected.GetHash(), 0)
 		assert.Equal(t, expected.GetHash(), p.GetHash())
 	}
+
+	var highWatermarkTracked bool
+	for _, b := range mt.SentDSMBacklogs() {
+		if assert.ObjectsAreEqual([]string{"partition:0", "topic:test-topic", "type:kafka_high_watermark"}, b.Tags) {
+			highWatermarkTracked = true
+		}
+	}
+	assert.True(t, highWatermarkTracked, "the high watermark offset should be tracked")
 }
 
 func TestSyncProducer(t *testing.T) {
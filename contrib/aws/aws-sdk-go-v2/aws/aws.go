@@ -26,6 +26,7 @@ import (
 
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
 	eventBridgeTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/eventbridge"
+	kinesisTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/kinesis"
 	sfnTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/sfn"
 	snsTracer "github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/sns"
 	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal/spanpointers"
@@ -118,9 +119,33 @@ func (mw *traceMiddleware) startTraceMiddleware(stack *middleware.Stack) error {
 			spanctx = spanpointers.SetDynamoDbParamsOnContext(spanctx, in.Parameters)
 		}
 
+		// Set Data Streams checkpoints of the produced messages
+		if mw.cfg.dataStreamsEnabled {
+			switch serviceID {
+			case "SQS":
+				sqsTracer.EnrichDataStreamsOperation(ctx, in, operation)
+			case "SNS":
+				snsTracer.EnrichDataStreamsOperation(ctx, in, operation)
+			case "EventBridge":
+				eventBridgeTracer.EnrichDataStreamsOperation(ctx, in, operation)
+			case "Kinesis":
+				kinesisTracer.EnrichDataStreamsOperation(ctx, in, operation)
+			}
+		}
+
 		// Handle initialize and continue through the middleware chain.
 		out, metadata, err = next.HandleInitialize(spanctx, in)
 
+		// Set Data Streams checkpoints of the consumed messages
+		if err == nil && mw.cfg.dataStreamsEnabled {
+			switch serviceID {
+			case "SQS":
+				sqsTracer.HandleDataStreamsResult(in, out, operation)
+			case "Kinesis":
+				kinesisTracer.HandleDataStreamsResult(in, out, operation)
+			}
+		}
+
 		return out, metadata, err
 	}), middleware.After)
 }
@@ -15,8 +15,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
@@ -320,6 +322,49 @@ func TestAppendMiddlewareSqsSendMessage(t *testing.T) {
 	assert.NotEmpty(t, traceContext["x-datadog-parent-id"])
 }
 
+func TestAppendMiddlewareSqsSendMessageWithDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	server := mockAWS(200)
+	defer server.Close()
+
+	resolver := aws.EndpointResolverFunc(func(_, _ string) (aws.Endpoint, error) {
+		return aws.Endpoint{
+			PartitionID:   "aws",
+			URL:           server.URL,
+			SigningRegion: "eu-west-1",
+		}, nil
+	})
+
+	awsCfg := aws.Config{
+		Region:           "eu-west-1",
+		Credentials:      aws.AnonymousCredentials{},
+		EndpointResolver: resolver,
+	}
+
+	AppendMiddleware(&awsCfg, WithDataStreams())
+
+	sqsClient := sqs.NewFromConfig(awsCfg)
+	sendMessageInput := &sqs.SendMessageInput{
+		MessageBody: aws.String("test message"),
+		QueueUrl:    aws.String("https://sqs.us-west-2.amazonaws.com/123456789012/MyQueueName"),
+	}
+	_, err := sqsClient.SendMessage(context.Background(), sendMessageInput)
+	require.NoError(t, err)
+
+	require.Contains(t, sendMessageInput.MessageAttributes, "_datadog")
+	carrier := tracer.TextMapCarrier{}
+	require.NoError(t, json.Unmarshal([]byte(*sendMessageInput.MessageAttributes["_datadog"].StringValue), &carrier))
+	assert.NotEmpty(t, carrier["x-datadog-trace-id"])
+
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), carrier))
+	require.True(t, ok, "pathway not found in the message attributes")
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:MyQueueName", "type:sqs")
+	want, _ := datastreams.PathwayFromContext(ctx)
+	assert.Equal(t, want.GetHash(), got.GetHash())
+}
+
 func TestAppendMiddlewareS3ListObjects(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -13,6 +13,9 @@ type config struct {
 	serviceName   string
 	analyticsRate float64
 	errCheck      func(err error) bool
+	// dataStreamsEnabled enables the Data Streams Monitoring checkpoints of the SQS, SNS, EventBridge and Kinesis
+	// operations.
+	dataStreamsEnabled bool
 }
 
 // Option describes options for the AWS integration.
@@ -29,6 +32,7 @@ func (fn OptionFn) apply(cfg *config) {
 
 func defaults(cfg *config) {
 	cfg.analyticsRate = instr.AnalyticsRate(false)
+	cfg.dataStreamsEnabled = instr.DataStreamsEnabled()
 }
 
 // WithService sets the given service name for the dialled connection.
@@ -71,3 +75,12 @@ func WithErrorCheck(fn func(err error) bool) OptionFn {
 		cfg.errCheck = fn
 	}
 }
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+// Produce and consume checkpoints are then set for the SQS, SNS, EventBridge and Kinesis messages, whose pathway is
+// propagated along with the trace context.
+func WithDataStreams() OptionFn {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
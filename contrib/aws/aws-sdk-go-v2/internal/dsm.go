@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package internal
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+// SetProduceCheckpoint sets a Data Streams produce checkpoint of a message sent to the given topic, whose parent
+// pathway is the one of ctx, and injects the resulting pathway into carrier.
+func SetProduceCheckpoint(ctx context.Context, carrier tracer.TextMapCarrier, payloadSize int64, typ, topic string) {
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(
+		ctx,
+		options.CheckpointParams{PayloadSize: payloadSize},
+		"direction:out", "topic:"+topic, "type:"+typ,
+	)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+// SetConsumeCheckpoint sets a Data Streams consume checkpoint of a message received from the given topic, whose
+// parent pathway is extracted from carrier, and injects the resulting pathway back into carrier.
+func SetConsumeCheckpoint(carrier tracer.TextMapCarrier, payloadSize int64, typ, topic string) {
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(
+		datastreams.ExtractFromBase64Carrier(context.Background(), carrier),
+		options.CheckpointParams{PayloadSize: payloadSize},
+		"direction:in", "topic:"+topic, "type:"+typ,
+	)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
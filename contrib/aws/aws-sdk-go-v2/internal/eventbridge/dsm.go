@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package eventbridge
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	dataStreamsType = "bus"
+	defaultBusName  = "default"
+)
+
+// EnrichDataStreamsOperation sets the Data Streams produce checkpoints of the events put by the operation and
+// propagates their pathway in the trace context of their detail, which must be injected beforehand by
+// EnrichOperation.
+func EnrichDataStreamsOperation(ctx context.Context, in middleware.InitializeInput, operation string) {
+	switch operation {
+	case "PutEvents":
+		params, ok := in.Parameters.(*eventbridge.PutEventsInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read PutEvents params")
+			return
+		}
+		for i := range params.Entries {
+			setProduceCheckpoint(ctx, &params.Entries[i])
+		}
+	}
+}
+
+func setProduceCheckpoint(ctx context.Context, entry *types.PutEventsRequestEntry) {
+	bus := defaultBusName
+	if entry.EventBusName != nil && *entry.EventBusName != "" {
+		bus = *entry.EventBusName
+	}
+	detail := "{}"
+	if entry.Detail != nil && *entry.Detail != "" {
+		detail = *entry.Detail
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(detail), &fields); err != nil || fields == nil {
+		instr.Logger().Debug("Unable to parse detail JSON. Not injecting data streams context into EventBridge payload.")
+		internal.SetProduceCheckpoint(ctx, tracer.TextMapCarrier{}, int64(len(detail)), dataStreamsType, bus)
+		return
+	}
+	carrier := tracer.TextMapCarrier{}
+	if raw, ok := fields[datadogKey]; ok {
+		if err := json.Unmarshal(raw, &carrier); err != nil {
+			carrier = tracer.TextMapCarrier{}
+		}
+	}
+	internal.SetProduceCheckpoint(ctx, carrier, int64(len(detail)), dataStreamsType, bus)
+
+	raw, err := json.Marshal(carrier)
+	if err != nil {
+		instr.Logger().Debug("Unable to marshal data streams context: %s", err)
+		return
+	}
+	fields[datadogKey] = raw
+	newDetail, err := json.Marshal(fields)
+	if err != nil {
+		instr.Logger().Debug("Unable to marshal detail: %s", err)
+		return
+	}
+	if len(newDetail) > maxSizeBytes {
+		instr.Logger().Debug("Payload size too large to pass data streams context")
+		return
+	}
+	entry.Detail = aws.String(string(newDetail))
+}
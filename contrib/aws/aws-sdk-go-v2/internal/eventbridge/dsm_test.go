@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package eventbridge
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	span := tracer.StartSpan("test-span")
+	in := middleware.InitializeInput{
+		Parameters: &eventbridge.PutEventsInput{
+			Entries: []types.PutEventsRequestEntry{
+				{Detail: aws.String(`{"foo":"bar"}`), EventBusName: aws.String("test-bus")},
+				{Detail: aws.String(`{"foo":"bar"}`)},
+			},
+		},
+	}
+	EnrichOperation(span, in, "PutEvents")
+	EnrichDataStreamsOperation(context.Background(), in, "PutEvents")
+
+	for i, bus := range []string{"test-bus", defaultBusName} {
+		entry := in.Parameters.(*eventbridge.PutEventsInput).Entries[i]
+		var detail struct {
+			Foo     string                `json:"foo"`
+			Datadog tracer.TextMapCarrier `json:"_datadog"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(*entry.Detail), &detail))
+		assert.Equal(t, "bar", detail.Foo)
+		assert.NotEmpty(t, detail.Datadog[startTimeKey])
+		_, err := tracer.Extract(detail.Datadog)
+		assert.NoError(t, err)
+
+		produced, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:"+bus, "type:bus")
+		want, _ := datastreams.PathwayFromContext(produced)
+		got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), detail.Datadog))
+		require.True(t, ok, "pathway not found in the event detail")
+		assert.Equal(t, want.GetHash(), got.GetHash())
+	}
+}
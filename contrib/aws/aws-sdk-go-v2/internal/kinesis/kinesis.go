@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kinesis
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	datadogKey      = "_datadog"
+	dataStreamsType = "kinesis"
+	maxSizeBytes    = 1024 * 1024 // 1 MB
+	// maxShardIterators is the maximum number of shard iterators whose stream is remembered.
+	maxShardIterators = 1024
+)
+
+var instr = internal.Instr
+
+// shardIterators maps the shard iterators returned by GetShardIterator and GetRecords to the name of their stream,
+// since consumers usually call GetRecords with the shard iterator only.
+var shardIterators = struct {
+	sync.Mutex
+	streams map[string]string
+}{streams: make(map[string]string)}
+
+// setIteratorStream remembers the stream of the given shard iterator. The iterators are short-lived, so when the
+// cache is full an arbitrary one is dropped.
+func setIteratorStream(iterator *string, stream string) {
+	if iterator == nil || stream == "" {
+		return
+	}
+	shardIterators.Lock()
+	defer shardIterators.Unlock()
+	if len(shardIterators.streams) >= maxShardIterators {
+		for k := range shardIterators.streams {
+			delete(shardIterators.streams, k)
+			break
+		}
+	}
+	shardIterators.streams[*iterator] = stream
+}
+
+// iteratorStream returns the stream of the given shard iterator and forgets it, as a shard iterator is only used once.
+func iteratorStream(iterator *string) string {
+	if iterator == nil {
+		return ""
+	}
+	shardIterators.Lock()
+	defer shardIterators.Unlock()
+	stream := shardIterators.streams[*iterator]
+	delete(shardIterators.streams, *iterator)
+	return stream
+}
+
+// EnrichDataStreamsOperation sets the Data Streams produce checkpoints of the records put by the operation. Their
+// pathway is propagated in the `_datadog` field of the records whose data is a JSON object, since Kinesis records
+// have no attributes.
+func EnrichDataStreamsOperation(ctx context.Context, in middleware.InitializeInput, operation string) {
+	switch operation {
+	case "PutRecord":
+		params, ok := in.Parameters.(*kinesis.PutRecordInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read PutRecord params")
+			return
+		}
+		params.Data = setProduceCheckpoint(ctx, streamName(params.StreamName, params.StreamARN), params.Data)
+	case "PutRecords":
+		params, ok := in.Parameters.(*kinesis.PutRecordsInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read PutRecords params")
+			return
+		}
+		stream := streamName(params.StreamName, params.StreamARN)
+		for i := range params.Records {
+			params.Records[i].Data = setProduceCheckpoint(ctx, stream, params.Records[i].Data)
+		}
+	}
+}
+
+// HandleDataStreamsResult sets the Data Streams consume checkpoints of the records returned by the operation, and
+// tracks how far behind the tip of the stream the consumer is. The checkpoints are skipped when the stream of the
+// records is unknown, i.e. GetRecords was called with a shard iterator not returned by an instrumented client.
+func HandleDataStreamsResult(in middleware.InitializeInput, out middleware.InitializeOutput, operation string) {
+	switch operation {
+	case "GetShardIterator":
+		params, ok := in.Parameters.(*kinesis.GetShardIteratorInput)
+		if !ok {
+			return
+		}
+		res, ok := out.Result.(*kinesis.GetShardIteratorOutput)
+		if !ok {
+			return
+		}
+		setIteratorStream(res.ShardIterator, streamName(params.StreamName, params.StreamARN))
+	case "GetRecords":
+		params, ok := in.Parameters.(*kinesis.GetRecordsInput)
+		if !ok {
+			return
+		}
+		res, ok := out.Result.(*kinesis.GetRecordsOutput)
+		if !ok {
+			return
+		}
+		stream := iteratorStream(params.ShardIterator)
+		if stream == "" {
+			stream = streamName(nil, params.StreamARN)
+		}
+		if stream == "" {
+			instr.Logger().Debug("Unable to find the stream of the GetRecords shard iterator")
+			return
+		}
+		setIteratorStream(res.NextShardIterator, stream)
+		for _, record := range res.Records {
+			internal.SetConsumeCheckpoint(extractCarrier(record.Data), int64(len(record.Data)), dataStreamsType, stream)
+		}
+		if res.MillisBehindLatest != nil {
+			tracer.TrackDataStreamsBacklog(*res.MillisBehindLatest, "stream:"+stream, "type:kinesis_millis_behind_latest")
+		}
+	}
+}
+
+func setProduceCheckpoint(ctx context.Context, stream string, data []byte) []byte {
+	carrier := tracer.TextMapCarrier{}
+	internal.SetProduceCheckpoint(ctx, carrier, int64(len(data)), dataStreamsType, stream)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		// Only JSON objects can carry the data streams context.
+		return data
+	}
+	raw, err := json.Marshal(carrier)
+	if err != nil {
+		instr.Logger().Debug("Unable to marshal data streams context: %s", err)
+		return data
+	}
+	fields[datadogKey] = raw
+	newData, err := json.Marshal(fields)
+	if err != nil {
+		instr.Logger().Debug("Unable to marshal record data: %s", err)
+		return data
+	}
+	if len(newData) > maxSizeBytes {
+		instr.Logger().Debug("Record size too large to pass data streams context")
+		return data
+	}
+	return newData
+}
+
+func extractCarrier(data []byte) tracer.TextMapCarrier {
+	var record struct {
+		Datadog tracer.TextMapCarrier `json:"_datadog"`
+	}
+	if err := json.Unmarshal(data, &record); err != nil || record.Datadog == nil {
+		return tracer.TextMapCarrier{}
+	}
+	return record.Datadog
+}
+
+func streamName(name, arn *string) string {
+	if name != nil {
+		return *name
+	}
+	if arn != nil {
+		parts := strings.Split(*arn, "/")
+		return parts[len(parts)-1]
+	}
+	return ""
+}
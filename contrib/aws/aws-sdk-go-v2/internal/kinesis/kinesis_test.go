@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package kinesis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	produced, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:test-stream", "type:kinesis")
+	consumed, _ := tracer.SetDataStreamsCheckpoint(produced, "direction:in", "topic:test-stream", "type:kinesis")
+
+	in := middleware.InitializeInput{
+		Parameters: &kinesis.PutRecordsInput{
+			StreamName: aws.String("test-stream"),
+			Records: []types.PutRecordsRequestEntry{
+				{Data: []byte(`{"foo":"bar"}`), PartitionKey: aws.String("1")},
+				{Data: []byte(`not json`), PartitionKey: aws.String("2")},
+			},
+		},
+	}
+	EnrichDataStreamsOperation(context.Background(), in, "PutRecords")
+
+	records := in.Parameters.(*kinesis.PutRecordsInput).Records
+	assert.Equal(t, []byte(`not json`), records[1].Data)
+	carrier := extractCarrier(records[0].Data)
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), carrier))
+	require.True(t, ok, "pathway not found in the record")
+	want, _ := datastreams.PathwayFromContext(produced)
+	assert.Equal(t, want.GetHash(), got.GetHash())
+
+	// The consume checkpoint is the child of the produce checkpoint.
+	consumedCtx, _ := tracer.SetDataStreamsCheckpoint(datastreams.ExtractFromBase64Carrier(context.Background(), carrier), "direction:in", "topic:test-stream", "type:kinesis")
+	got, _ = datastreams.PathwayFromContext(consumedCtx)
+	want, _ = datastreams.PathwayFromContext(consumed)
+	assert.Equal(t, want.GetHash(), got.GetHash())
+
+	HandleDataStreamsResult(
+		middleware.InitializeInput{
+			Parameters: &kinesis.GetRecordsInput{StreamARN: aws.String("arn:aws:kinesis:us-east-1:123456789012:stream/test-stream")},
+		},
+		middleware.InitializeOutput{
+			Result: &kinesis.GetRecordsOutput{Records: []types.Record{{Data: records[0].Data}}, MillisBehindLatest: aws.Int64(1500)},
+		},
+		"GetRecords",
+	)
+	assert.Contains(t, mt.SentDSMBacklogs(), mocktracer.DSMBacklog{
+		Tags:  []string{"stream:test-stream", "type:kinesis_millis_behind_latest"},
+		Value: 1500,
+	})
+}
+
+func TestShardIteratorStream(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	getRecords := func(iterator string, next *string, behind int64) {
+		HandleDataStreamsResult(
+			middleware.InitializeInput{Parameters: &kinesis.GetRecordsInput{ShardIterator: aws.String(iterator)}},
+			middleware.InitializeOutput{Result: &kinesis.GetRecordsOutput{NextShardIterator: next, MillisBehindLatest: aws.Int64(behind)}},
+			"GetRecords",
+		)
+	}
+
+	HandleDataStreamsResult(
+		middleware.InitializeInput{Parameters: &kinesis.GetShardIteratorInput{StreamName: aws.String("test-stream")}},
+		middleware.InitializeOutput{Result: &kinesis.GetShardIteratorOutput{ShardIterator: aws.String("iterator-1")}},
+		"GetShardIterator",
+	)
+	getRecords("iterator-1", aws.String("iterator-2"), 100)
+	assert.Equal(t, []mocktracer.DSMBacklog{
+		{Tags: []string{"stream:test-stream", "type:kinesis_millis_behind_latest"}, Value: 100},
+	}, mt.SentDSMBacklogs())
+
+	// the stream of the next shard iterator is known as well
+	getRecords("iterator-2", nil, 200)
+	backlogs := []mocktracer.DSMBacklog{
+		{Tags: []string{"stream:test-stream", "type:kinesis_millis_behind_latest"}, Value: 100},
+		{Tags: []string{"stream:test-stream", "type:kinesis_millis_behind_latest"}, Value: 200},
+	}
+	assert.Equal(t, backlogs, mt.SentDSMBacklogs())
+
+	// the stream of an unknown shard iterator is not guessed
+	getRecords("unknown-iterator", nil, 300)
+	assert.Equal(t, backlogs, mt.SentDSMBacklogs())
+	assert.Empty(t, shardIterators.streams)
+}
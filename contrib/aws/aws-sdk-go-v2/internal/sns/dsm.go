@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sns
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const dataStreamsType = "sns"
+
+// EnrichDataStreamsOperation sets the Data Streams produce checkpoints of the messages published by the operation
+// and propagates their pathway in the trace context message attribute, which must be injected beforehand by
+// EnrichOperation.
+func EnrichDataStreamsOperation(ctx context.Context, in middleware.InitializeInput, operation string) {
+	switch operation {
+	case "Publish":
+		params, ok := in.Parameters.(*sns.PublishInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read PublishInput params")
+			return
+		}
+		if params.MessageAttributes == nil {
+			params.MessageAttributes = make(map[string]types.MessageAttributeValue)
+		}
+		topicArn := params.TopicArn
+		if topicArn == nil {
+			topicArn = params.TargetArn
+		}
+		setProduceCheckpoint(ctx, topicName(topicArn), params.Message, params.MessageAttributes)
+	case "PublishBatch":
+		params, ok := in.Parameters.(*sns.PublishBatchInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read PublishBatch params")
+			return
+		}
+		topic := topicName(params.TopicArn)
+		for i := range params.PublishBatchRequestEntries {
+			entry := &params.PublishBatchRequestEntries[i]
+			if entry.MessageAttributes == nil {
+				entry.MessageAttributes = make(map[string]types.MessageAttributeValue)
+			}
+			setProduceCheckpoint(ctx, topic, entry.Message, entry.MessageAttributes)
+		}
+	}
+}
+
+func setProduceCheckpoint(ctx context.Context, topic string, message *string, messageAttributes map[string]types.MessageAttributeValue) {
+	attr, ok := messageAttributes[datadogKey]
+	if !ok && len(messageAttributes) >= maxMessageAttributes {
+		instr.Logger().Info("Cannot inject data streams context: message already has maximum allowed attributes")
+		return
+	}
+	carrier := tracer.TextMapCarrier{}
+	if attr.BinaryValue != nil {
+		if err := json.Unmarshal(attr.BinaryValue, &carrier); err != nil {
+			instr.Logger().Debug("Unable to decode trace context: %s", err.Error())
+			carrier = tracer.TextMapCarrier{}
+		}
+	}
+	internal.SetProduceCheckpoint(ctx, carrier, payloadSize(message, messageAttributes), dataStreamsType, topic)
+
+	jsonBytes, err := json.Marshal(carrier)
+	if err != nil {
+		instr.Logger().Debug("Unable to inject data streams context: %s", err.Error())
+		return
+	}
+	// Use Binary for the same reason as getTraceContext.
+	messageAttributes[datadogKey] = types.MessageAttributeValue{
+		DataType:    aws.String("Binary"),
+		BinaryValue: jsonBytes,
+	}
+}
+
+func payloadSize(message *string, messageAttributes map[string]types.MessageAttributeValue) (size int64) {
+	if message != nil {
+		size += int64(len(*message))
+	}
+	for k, v := range messageAttributes {
+		size += int64(len(k) + len(v.BinaryValue))
+		if v.StringValue != nil {
+			size += int64(len(*v.StringValue))
+		}
+	}
+	return size
+}
+
+func topicName(arn *string) string {
+	if arn == nil {
+		return ""
+	}
+	parts := strings.Split(*arn, ":")
+	return parts[len(parts)-1]
+}
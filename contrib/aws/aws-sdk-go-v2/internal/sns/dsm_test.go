@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sns
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	produced, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:test-topic", "type:sns")
+	want, _ := datastreams.PathwayFromContext(produced)
+
+	span := tracer.StartSpan("test-span")
+	in := middleware.InitializeInput{
+		Parameters: &sns.PublishBatchInput{
+			TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:test-topic"),
+			PublishBatchRequestEntries: []types.PublishBatchRequestEntry{
+				{Id: aws.String("1"), Message: aws.String("test message 1")},
+				{Id: aws.String("2"), Message: aws.String("test message 2")},
+			},
+		},
+	}
+	EnrichOperation(span, in, "PublishBatch")
+	EnrichDataStreamsOperation(context.Background(), in, "PublishBatch")
+
+	for _, entry := range in.Parameters.(*sns.PublishBatchInput).PublishBatchRequestEntries {
+		attr := entry.MessageAttributes[datadogKey]
+		assert.Equal(t, "Binary", *attr.DataType)
+
+		carrier := tracer.TextMapCarrier{}
+		require.NoError(t, json.Unmarshal(attr.BinaryValue, &carrier))
+		_, err := tracer.Extract(carrier)
+		assert.NoError(t, err)
+		got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), carrier))
+		require.True(t, ok, "pathway not found in the message attributes")
+		assert.Equal(t, want.GetHash(), got.GetHash())
+	}
+}
@@ -0,0 +1,232 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sqs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/contrib/aws/aws-sdk-go-v2/v2/internal"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const dataStreamsType = "sqs"
+
+// EnrichDataStreamsOperation sets the Data Streams produce checkpoints of the messages sent by the operation and
+// propagates their pathway in the trace context message attribute, which must be injected beforehand by
+// EnrichOperation. It also requests the trace context message attribute of the received messages so that their
+// consume checkpoints can be set by HandleDataStreamsResult.
+func EnrichDataStreamsOperation(ctx context.Context, in middleware.InitializeInput, operation string) {
+	switch operation {
+	case "SendMessage":
+		params, ok := in.Parameters.(*sqs.SendMessageInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read SendMessage params")
+			return
+		}
+		if params.MessageAttributes == nil {
+			params.MessageAttributes = make(map[string]types.MessageAttributeValue)
+		}
+		setProduceCheckpoint(ctx, queueName(params.QueueUrl), params.MessageBody, params.MessageAttributes)
+	case "SendMessageBatch":
+		params, ok := in.Parameters.(*sqs.SendMessageBatchInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read SendMessageBatch params")
+			return
+		}
+		queue := queueName(params.QueueUrl)
+		for i := range params.Entries {
+			if params.Entries[i].MessageAttributes == nil {
+				params.Entries[i].MessageAttributes = make(map[string]types.MessageAttributeValue)
+			}
+			setProduceCheckpoint(ctx, queue, params.Entries[i].MessageBody, params.Entries[i].MessageAttributes)
+		}
+	case "ReceiveMessage":
+		params, ok := in.Parameters.(*sqs.ReceiveMessageInput)
+		if !ok {
+			instr.Logger().Debug("Unable to read ReceiveMessage params")
+			return
+		}
+		if !slices.Contains(params.MessageAttributeNames, datadogKey) && !slices.Contains(params.MessageAttributeNames, "All") && !slices.Contains(params.MessageAttributeNames, ".*") {
+			params.MessageAttributeNames = append(slices.Clip(params.MessageAttributeNames), datadogKey)
+		}
+	}
+}
+
+// HandleDataStreamsResult sets the Data Streams consume checkpoints of the messages received by the operation, and
+// tracks the approximate number of messages of the queue when the operation returns it.
+func HandleDataStreamsResult(in middleware.InitializeInput, out middleware.InitializeOutput, operation string) {
+	switch operation {
+	case "ReceiveMessage":
+		params, ok := in.Parameters.(*sqs.ReceiveMessageInput)
+		if !ok {
+			return
+		}
+		res, ok := out.Result.(*sqs.ReceiveMessageOutput)
+		if !ok {
+			return
+		}
+		queue := queueName(params.QueueUrl)
+		for i := range res.Messages {
+			setConsumeCheckpoint(queue, &res.Messages[i])
+		}
+	case "GetQueueAttributes":
+		params, ok := in.Parameters.(*sqs.GetQueueAttributesInput)
+		if !ok {
+			return
+		}
+		res, ok := out.Result.(*sqs.GetQueueAttributesOutput)
+		if !ok {
+			return
+		}
+		v, ok := res.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)]
+		if !ok {
+			return
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			instr.Logger().Debug("Unable to parse the approximate number of messages: %s", err.Error())
+			return
+		}
+		tracer.TrackDataStreamsBacklog(n, "queue:"+queueName(params.QueueUrl), "type:sqs_approximate_messages")
+	}
+}
+
+func setProduceCheckpoint(ctx context.Context, queue string, body *string, messageAttributes map[string]types.MessageAttributeValue) {
+	attr, ok := messageAttributes[datadogKey]
+	if !ok && len(messageAttributes) >= maxMessageAttributes {
+		instr.Logger().Info("Cannot inject data streams context: message already has maximum allowed attributes")
+		return
+	}
+	carrier := carrierFromAttribute(attr)
+	internal.SetProduceCheckpoint(ctx, carrier, payloadSize(body, messageAttributes), dataStreamsType, queue)
+	if err := injectCarrier(carrier, messageAttributes); err != nil {
+		instr.Logger().Debug("Unable to inject data streams context: %s", err.Error())
+	}
+}
+
+func setConsumeCheckpoint(queue string, msg *types.Message) {
+	carrier, ok := carrierFromMessage(msg)
+	if !ok {
+		carrier = tracer.TextMapCarrier{}
+	}
+	internal.SetConsumeCheckpoint(carrier, payloadSize(msg.Body, msg.MessageAttributes), dataStreamsType, queue)
+	if msg.MessageAttributes == nil {
+		msg.MessageAttributes = make(map[string]types.MessageAttributeValue)
+	}
+	// Propagate the resulting pathway in the received message, like the
+	// other message queue integrations do with their message headers.
+	if err := injectCarrier(carrier, msg.MessageAttributes); err != nil {
+		instr.Logger().Debug("Unable to inject data streams context: %s", err.Error())
+	}
+}
+
+// carrierFromMessage returns the trace context carrier of a received message,
+// which is either in its message attributes, or in the message attributes of
+// the SNS notification or in the detail of the EventBridge event it wraps.
+func carrierFromMessage(msg *types.Message) (tracer.TextMapCarrier, bool) {
+	if attr, ok := msg.MessageAttributes[datadogKey]; ok {
+		return carrierFromAttribute(attr), true
+	}
+	if msg.Body == nil || !strings.HasPrefix(*msg.Body, "{") {
+		return nil, false
+	}
+	var envelope struct {
+		MessageAttributes map[string]struct {
+			Type  string
+			Value string
+		}
+		Detail json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal([]byte(*msg.Body), &envelope); err != nil {
+		return nil, false
+	}
+	if attr, ok := envelope.MessageAttributes[datadogKey]; ok {
+		value := []byte(attr.Value)
+		if attr.Type == "Binary" {
+			decoded, err := base64.StdEncoding.DecodeString(attr.Value)
+			if err != nil {
+				return nil, false
+			}
+			value = decoded
+		}
+		var carrier tracer.TextMapCarrier
+		if err := json.Unmarshal(value, &carrier); err != nil {
+			return nil, false
+		}
+		return carrier, true
+	}
+	if len(envelope.Detail) > 0 {
+		var detail struct {
+			Datadog tracer.TextMapCarrier `json:"_datadog"`
+		}
+		if err := json.Unmarshal(envelope.Detail, &detail); err == nil && detail.Datadog != nil {
+			return detail.Datadog, true
+		}
+	}
+	return nil, false
+}
+
+// carrierFromAttribute decodes the trace context message attribute, or
+// returns an empty carrier when it is missing or invalid.
+func carrierFromAttribute(attr types.MessageAttributeValue) tracer.TextMapCarrier {
+	var data []byte
+	switch {
+	case attr.StringValue != nil:
+		data = []byte(*attr.StringValue)
+	case attr.BinaryValue != nil:
+		data = attr.BinaryValue
+	default:
+		return tracer.TextMapCarrier{}
+	}
+	carrier := tracer.TextMapCarrier{}
+	if err := json.Unmarshal(data, &carrier); err != nil {
+		instr.Logger().Debug("Unable to decode trace context: %s", err.Error())
+		return tracer.TextMapCarrier{}
+	}
+	return carrier
+}
+
+func injectCarrier(carrier tracer.TextMapCarrier, messageAttributes map[string]types.MessageAttributeValue) error {
+	jsonBytes, err := json.Marshal(carrier)
+	if err != nil {
+		return err
+	}
+	messageAttributes[datadogKey] = types.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(string(jsonBytes)),
+	}
+	return nil
+}
+
+func payloadSize(body *string, messageAttributes map[string]types.MessageAttributeValue) (size int64) {
+	if body != nil {
+		size += int64(len(*body))
+	}
+	for k, v := range messageAttributes {
+		size += int64(len(k) + len(v.BinaryValue))
+		if v.StringValue != nil {
+			size += int64(len(*v.StringValue))
+		}
+	}
+	return size
+}
+
+func queueName(queueURL *string) string {
+	if queueURL == nil {
+		return ""
+	}
+	parts := strings.Split(*queueURL, "/")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package sqs
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testQueueURL = "https://sqs.us-east-1.amazonaws.com/1234567890/test-queue"
+
+func TestDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	produced, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:test-queue", "type:sqs")
+	consumed, _ := tracer.SetDataStreamsCheckpoint(produced, "direction:in", "topic:test-queue", "type:sqs")
+
+	t.Run("SendMessage", func(t *testing.T) {
+		span := tracer.StartSpan("test-span")
+		in := middleware.InitializeInput{
+			Parameters: &sqs.SendMessageInput{
+				MessageBody: aws.String("test message"),
+				QueueUrl:    aws.String(testQueueURL),
+			},
+		}
+		EnrichOperation(span, in, "SendMessage")
+		EnrichDataStreamsOperation(context.Background(), in, "SendMessage")
+
+		params := in.Parameters.(*sqs.SendMessageInput)
+		carrier := carrierFromAttribute(params.MessageAttributes[datadogKey])
+		assertPathway(t, produced, carrier)
+		_, err := tracer.Extract(carrier)
+		assert.NoError(t, err)
+	})
+
+	t.Run("SendMessageBatch", func(t *testing.T) {
+		span := tracer.StartSpan("test-span")
+		in := middleware.InitializeInput{
+			Parameters: &sqs.SendMessageBatchInput{
+				QueueUrl: aws.String(testQueueURL),
+				Entries: []types.SendMessageBatchRequestEntry{
+					{Id: aws.String("1"), MessageBody: aws.String("test message 1")},
+					{Id: aws.String("2"), MessageBody: aws.String("test message 2")},
+				},
+			},
+		}
+		EnrichOperation(span, in, "SendMessageBatch")
+		EnrichDataStreamsOperation(context.Background(), in, "SendMessageBatch")
+
+		for _, entry := range in.Parameters.(*sqs.SendMessageBatchInput).Entries {
+			assertPathway(t, produced, carrierFromAttribute(entry.MessageAttributes[datadogKey]))
+		}
+	})
+
+	t.Run("ReceiveMessage", func(t *testing.T) {
+		params := &sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String(testQueueURL),
+			MessageAttributeNames: []string{"custom"},
+		}
+		in := middleware.InitializeInput{Parameters: params}
+		EnrichDataStreamsOperation(context.Background(), in, "ReceiveMessage")
+		assert.Equal(t, []string{"custom", datadogKey}, params.MessageAttributeNames)
+
+		carrier := tracer.TextMapCarrier{}
+		datastreams.InjectToBase64Carrier(produced, carrier)
+		snsCarrier, err := json.Marshal(carrier)
+		require.NoError(t, err)
+		snsNotification := fmt.Sprintf(`{"Type":"Notification","Message":"test","MessageAttributes":{"_datadog":{"Type":"Binary","Value":%q}}}`, base64.StdEncoding.EncodeToString(snsCarrier))
+		eventBridgeEvent := fmt.Sprintf(`{"detail-type":"test","detail":{"foo":"bar","_datadog":%s}}`, snsCarrier)
+
+		attr := types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(string(snsCarrier))}
+		out := middleware.InitializeOutput{
+			Result: &sqs.ReceiveMessageOutput{
+				Messages: []types.Message{
+					{Body: aws.String("sqs"), MessageAttributes: map[string]types.MessageAttributeValue{datadogKey: attr}},
+					{Body: aws.String(snsNotification)},
+					{Body: aws.String(eventBridgeEvent)},
+				},
+			},
+		}
+		HandleDataStreamsResult(in, out, "ReceiveMessage")
+
+		for _, msg := range out.Result.(*sqs.ReceiveMessageOutput).Messages {
+			assertPathway(t, consumed, carrierFromAttribute(msg.MessageAttributes[datadogKey]))
+		}
+	})
+
+	t.Run("GetQueueAttributes", func(t *testing.T) {
+		in := middleware.InitializeInput{
+			Parameters: &sqs.GetQueueAttributesInput{QueueUrl: aws.String(testQueueURL)},
+		}
+		out := middleware.InitializeOutput{
+			Result: &sqs.GetQueueAttributesOutput{
+				Attributes: map[string]string{string(types.QueueAttributeNameApproximateNumberOfMessages): "42"},
+			},
+		}
+		HandleDataStreamsResult(in, out, "GetQueueAttributes")
+
+		assert.Contains(t, mt.SentDSMBacklogs(), mocktracer.DSMBacklog{
+			Tags:  []string{"queue:test-queue", "type:sqs_approximate_messages"},
+			Value: 42,
+		})
+	})
+}
+
+func assertPathway(t *testing.T, want context.Context, carrier tracer.TextMapCarrier) {
+	t.Helper()
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), carrier))
+	require.True(t, ok, "pathway not found in the message attributes")
+	wantPathway, _ := datastreams.PathwayFromContext(want)
+	assert.NotEqual(t, uint64(0), wantPathway.GetHash())
+	assert.Equal(t, wantPathway.GetHash(), got.GetHash())
+}
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package pubsub
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+func TestAppSec(t *testing.T) {
+	testutils.StartAppSec(t)
+	if !instrumentation.Load(instrumentation.PackageGCPPubsub).AppSecEnabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+	ctx, cancel, mt, topic, sub := setup(t)
+
+	_, err := Publish(ctx, topic, &pubsub.Message{Data: []byte(`{"comment":"<script>alert(1)</script>"}`), OrderingKey: "xxx"}).Get(ctx)
+	require.NoError(t, err)
+
+	var found bool
+	err = sub.Receive(ctx, WrapReceiveHandler(sub, func(ctx context.Context, msg *pubsub.Message) {
+		_, found = dyngo.FindOperation[messagesec.ConsumeOperation](ctx)
+		msg.Ack()
+		cancel()
+	}))
+	require.NoError(t, err)
+	require.True(t, found, "the consume operation should be found in the receive handler context")
+
+	var receiveSpan *mocktracer.Span
+	for _, s := range mt.FinishedSpans() {
+		if s.OperationName() == "pubsub.receive" {
+			receiveSpan = s
+		}
+	}
+	require.NotNil(t, receiveSpan)
+	require.Equal(t, float64(1), receiveSpan.Tag("_dd.appsec.enabled"))
+	require.Contains(t, receiveSpan.Tag("_dd.appsec.json"), "crs-941-110")
+}
@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
+)
+
+// startConsumeOperation starts the AppSec monitoring of the given received message, whose receive span is given,
+// and returns the context holding it, which is passed to the receive handler so that the AppSec protections, such as
+// the RASP ones, apply to the message processing. It returns a nil operation when AppSec is disabled.
+func startConsumeOperation(ctx context.Context, span *tracer.Span, s Subscription, msg *Message) (context.Context, *messagesec.ConsumeOperation) {
+	if !instr.AppSecEnabled() {
+		return ctx, nil
+	}
+	headers := make(map[string][]string, len(msg.Attributes))
+	for k, v := range msg.Attributes {
+		headers[k] = []string{v}
+	}
+	return messagesec.StartConsumeOperation(ctx, span, messagesec.MessageKey{}, messagesec.ConsumeOperationArgs{
+		System:  messagesec.SystemGCPPubsub,
+		Topic:   s.String(),
+		Headers: headers,
+		Key:     msg.OrderingKey,
+		Value:   messagesec.DecodeValue(msg.Data),
+	})
+}
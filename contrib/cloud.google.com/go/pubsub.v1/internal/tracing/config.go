@@ -18,6 +18,8 @@ type config struct {
 	publishSpanName string
 	receiveSpanName string
 	measured        bool
+	// dataStreamsEnabled enables the Data Streams Monitoring checkpoints of the published and received messages.
+	dataStreamsEnabled bool
 }
 
 // Option describes options for the Pub/Sub integration.
@@ -27,10 +29,11 @@ type Option interface {
 
 func defaultConfig() *config {
 	return &config{
-		serviceName:     instr.ServiceName(instrumentation.ComponentConsumer, nil),
-		publishSpanName: instr.OperationName(instrumentation.ComponentProducer, nil),
-		receiveSpanName: instr.OperationName(instrumentation.ComponentConsumer, nil),
-		measured:        false,
+		serviceName:        instr.ServiceName(instrumentation.ComponentConsumer, nil),
+		publishSpanName:    instr.OperationName(instrumentation.ComponentProducer, nil),
+		receiveSpanName:    instr.OperationName(instrumentation.ComponentConsumer, nil),
+		measured:           false,
+		dataStreamsEnabled: instr.DataStreamsEnabled(),
 	}
 }
 
@@ -54,3 +57,10 @@ func WithMeasured() OptionFn {
 		cfg.measured = true
 	}
 }
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+func WithDataStreams() OptionFn {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
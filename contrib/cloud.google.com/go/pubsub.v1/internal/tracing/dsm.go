@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const dataStreamsType = "type:google-pubsub"
+
+// setPublishCheckpoint sets the Data Streams checkpoint of a published message, whose parent pathway is the one of
+// ctx, and propagates the resulting pathway as an attribute of the message.
+func setPublishCheckpoint(ctx context.Context, topic Topic, msg *Message) {
+	edges := []string{"direction:out", "topic:" + resourceID(topic.String()), dataStreamsType}
+	carrier := tracer.TextMapCarrier(msg.Attributes)
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: getMsgSize(msg)}, edges...)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, carrier)
+}
+
+// setReceiveCheckpoint sets the Data Streams checkpoint of a received message, whose parent pathway is extracted
+// from the attributes of the message, and returns a copy of ctx holding the resulting pathway so that it is the
+// parent pathway of the messages published while processing it.
+func setReceiveCheckpoint(ctx context.Context, s Subscription, msg *Message) context.Context {
+	edges := []string{"direction:in", "subscription:" + resourceID(s.String()), dataStreamsType}
+	carrier := tracer.TextMapCarrier(msg.Attributes)
+	ctx, _ = tracer.SetDataStreamsCheckpointWithParams(
+		datastreams.ExtractFromBase64Carrier(ctx, carrier),
+		options.CheckpointParams{PayloadSize: getMsgSize(msg)},
+		edges...,
+	)
+	return ctx
+}
+
+func getMsgSize(msg *Message) (size int64) {
+	for k, v := range msg.Attributes {
+		size += int64(len(k) + len(v))
+	}
+	return size + int64(len(msg.Data)+len(msg.OrderingKey))
+}
+
+// resourceID returns the ID of a topic or subscription from its fully qualified name,
+// e.g. "topic" for "projects/project/topics/topic".
+func resourceID(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
@@ -72,6 +72,9 @@ func TracePublish(ctx context.Context, topic Topic, msg *Message, opts ...Option
 	if err := tracer.Inject(span.Context(), tracer.TextMapCarrier(msg.Attributes)); err != nil {
 		instr.Logger().Debug("contrib/cloud.google.com/go/pubsub.v1/trace: failed injecting tracing attributes: %v", err)
 	}
+	if cfg.dataStreamsEnabled {
+		setPublishCheckpoint(ctx, topic, msg)
+	}
 	span.SetTag("num_attributes", len(msg.Attributes))
 
 	var once sync.Once
@@ -119,6 +122,15 @@ func TraceReceiveFunc(s Subscription, opts ...Option) func(ctx context.Context,
 		if msg.DeliveryAttempt != nil {
 			span.SetTag("delivery_attempt", *msg.DeliveryAttempt)
 		}
-		return ctx, func() { span.Finish() }
+		if cfg.dataStreamsEnabled {
+			ctx = setReceiveCheckpoint(ctx, s, msg)
+		}
+		ctx, op := startConsumeOperation(ctx, span, s, msg)
+		return ctx, func() {
+			if op != nil {
+				op.Finish()
+			}
+			span.Finish()
+		}
 	}
 }
@@ -22,3 +22,10 @@ func WithService(serviceName string) Option {
 func WithMeasured() Option {
 	return tracing.WithMeasured()
 }
+
+// WithDataStreams enables the Data Streams monitoring product features: https://www.datadoghq.com/product/data-streams-monitoring/
+// Publish and WrapReceiveHandler then set Data Streams checkpoints, and the pathway is propagated as attributes
+// attached to the published messages.
+func WithDataStreams() Option {
+	return tracing.WithDataStreams()
+}
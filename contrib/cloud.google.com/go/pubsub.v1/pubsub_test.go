@@ -13,6 +13,7 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -255,6 +256,36 @@ func TestPropagationNoPublisherSpan(t *testing.T) {
 	assert.Equal("cloud.google.com/go/pubsub.v1", spans[0].Integration())
 }
 
+func TestDataStreams(t *testing.T) {
+	ctx, cancel, _, topic, sub := setup(t)
+
+	msg := &pubsub.Message{Data: []byte("hello"), OrderingKey: "xxx"}
+	_, err := Publish(ctx, topic, msg, WithDataStreams()).Get(ctx)
+	require.NoError(t, err)
+
+	produced, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "topic:topic", "type:google-pubsub")
+	want, _ := datastreams.PathwayFromContext(produced)
+	got, ok := datastreams.PathwayFromContext(datastreams.ExtractFromBase64Carrier(context.Background(), tracer.TextMapCarrier(msg.Attributes)))
+	require.True(t, ok, "pathway not found in the message attributes")
+	assert.Equal(t, want.GetHash(), got.GetHash())
+
+	var called bool
+	err = sub.Receive(ctx, WrapReceiveHandler(sub, func(ctx context.Context, msg *pubsub.Message) {
+		consumed, _ := tracer.SetDataStreamsCheckpoint(produced, "direction:in", "subscription:subscription", "type:google-pubsub")
+		want, _ := datastreams.PathwayFromContext(consumed)
+		got, ok := datastreams.PathwayFromContext(ctx)
+		assert.True(t, ok, "pathway not found in the receive handler context")
+		if ok {
+			assert.Equal(t, want.GetHash(), got.GetHash())
+		}
+		msg.Ack()
+		called = true
+		cancel()
+	}, WithDataStreams()))
+	require.NoError(t, err)
+	require.True(t, called, "callback not called")
+}
+
 func filterTags(m map[string]interface{}) map[string]interface{} {
 	delete(m, "_dd.p.tid")
 	delete(m, "_dd.profiling.enabled")
@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracing
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
+)
+
+// StartConsumeOperation starts the AppSec monitoring of the given consumed message, whose consume span is given.
+// It returns nil when AppSec is disabled. The returned operation must be finished before the consume span.
+func (tr *KafkaTracer) StartConsumeOperation(span *tracer.Span, msg Message) *messagesec.ConsumeOperation {
+	if !instr.AppSecEnabled() {
+		return nil
+	}
+	headers := make(map[string][]string, len(msg.GetHeaders()))
+	for _, h := range msg.GetHeaders() {
+		headers[h.GetKey()] = append(headers[h.GetKey()], string(h.GetValue()))
+	}
+	_, op := messagesec.StartConsumeOperation(tr.ctx, span, MessageKey(msg), messagesec.ConsumeOperationArgs{
+		System:  messagesec.SystemKafka,
+		Topic:   msg.GetTopicPartition().GetTopic(),
+		Headers: headers,
+		Key:     string(msg.GetKey()),
+		Value:   messagesec.DecodeValue(msg.GetValue()),
+	})
+	return op
+}
+
+// FinishPrevSpan finishes the consume span of the previously consumed message, along with its AppSec operation, if
+// any.
+func (tr *KafkaTracer) FinishPrevSpan() {
+	if tr.PrevOp != nil {
+		tr.PrevOp.Finish()
+		tr.PrevOp = nil
+	}
+	if tr.PrevSpan != nil {
+		tr.PrevSpan.Finish()
+		tr.PrevSpan = nil
+	}
+}
+
+// MessageKey returns the key identifying the given consumed message.
+func MessageKey(msg Message) messagesec.MessageKey {
+	tp := msg.GetTopicPartition()
+	return messagesec.MessageKey{
+		Topic:     tp.GetTopic(),
+		Partition: tp.GetPartition(),
+		Offset:    tp.GetOffset(),
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
 )
 
 func WrapConsumeEventsChannel[E any, TE Event](tr *KafkaTracer, in chan E, consumer Consumer, translateFn func(E) TE) chan E {
@@ -23,11 +24,15 @@ func WrapConsumeEventsChannel[E any, TE Event](tr *KafkaTracer, in chan E, consu
 		defer close(out)
 		for evt := range in {
 			tEvt := translateFn(evt)
-			var next *tracer.Span
+			var (
+				next   *tracer.Span
+				nextOp *messagesec.ConsumeOperation
+			)
 
 			// only trace messages
 			if msg, ok := tEvt.KafkaMessage(); ok {
 				next = tr.StartConsumeSpan(msg)
+				nextOp = tr.StartConsumeOperation(next, msg)
 				tr.SetConsumeCheckpoint(msg)
 			} else if offset, ok := tEvt.KafkaOffsetsCommitted(); ok {
 				tr.TrackCommitOffsets(offset.GetOffsets(), offset.GetError())
@@ -36,16 +41,11 @@ func WrapConsumeEventsChannel[E any, TE Event](tr *KafkaTracer, in chan E, consu
 
 			out <- evt
 
-			if tr.PrevSpan != nil {
-				tr.PrevSpan.Finish()
-			}
-			tr.PrevSpan = next
+			tr.FinishPrevSpan()
+			tr.PrevSpan, tr.PrevOp = next, nextOp
 		}
 		// finish any remaining span
-		if tr.PrevSpan != nil {
-			tr.PrevSpan.Finish()
-			tr.PrevSpan = nil
-		}
+		tr.FinishPrevSpan()
 	}()
 	return out
 }
@@ -86,3 +86,11 @@ func getMsgSize(msg Message) (size int64) {
 	}
 	return size + int64(len(msg.GetValue())+len(msg.GetKey()))
 }
+
+// TrackSchema tracks the usage of a schema to serialize or deserialize a message value of the given topic.
+func (tr *KafkaTracer) TrackSchema(topic string, operation options.SchemaOperation, schema options.Schema) {
+	if !tr.dsmEnabled {
+		return
+	}
+	tracer.TrackDataStreamsSchema(topic, operation, schema)
+}
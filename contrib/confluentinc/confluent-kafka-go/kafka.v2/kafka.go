@@ -7,13 +7,16 @@
 package kafka // import "github.com/DataDog/dd-trace-go/contrib/confluentinc/confluent-kafka-go/kafka.v2/v2"
 
 import (
+	"context"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 
 	tracing "github.com/DataDog/dd-trace-go/v2/contrib/confluentinc/confluent-kafka-go"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
 )
 
 const (
@@ -77,9 +80,8 @@ func (c *Consumer) Close() error {
 	// we only close the previous span if consuming via the events channel is
 	// not enabled, because otherwise there would be a data race from the
 	// consuming goroutine.
-	if c.events == nil && c.tracer.PrevSpan != nil {
-		c.tracer.PrevSpan.Finish()
-		c.tracer.PrevSpan = nil
+	if c.events == nil {
+		c.tracer.FinishPrevSpan()
 	}
 	return err
 }
@@ -93,15 +95,13 @@ func (c *Consumer) Events() chan kafka.Event {
 // Poll polls the consumer for messages or events. msg will be
 // traced.
 func (c *Consumer) Poll(timeoutMS int) (event kafka.Event) {
-	if c.tracer.PrevSpan != nil {
-		c.tracer.PrevSpan.Finish()
-		c.tracer.PrevSpan = nil
-	}
+	c.tracer.FinishPrevSpan()
 	evt := c.Consumer.Poll(timeoutMS)
 	if msg, ok := evt.(*kafka.Message); ok {
 		tMsg := wrapMessage(msg)
 		c.tracer.SetConsumeCheckpoint(tMsg)
 		c.tracer.PrevSpan = c.tracer.StartConsumeSpan(tMsg)
+		c.tracer.PrevOp = c.tracer.StartConsumeOperation(c.tracer.PrevSpan, tMsg)
 	} else if offset, ok := evt.(kafka.OffsetsCommitted); ok {
 		tOffsets := wrapTopicPartitions(offset.Offsets)
 		c.tracer.TrackCommitOffsets(tOffsets, offset.Error)
@@ -110,19 +110,21 @@ func (c *Consumer) Poll(timeoutMS int) (event kafka.Event) {
 	return evt
 }
 
-// ReadMessage polls the consumer for a message. msg will be traced.
+// ReadMessage polls the consumer for a message. msg will be traced. When Data Streams Monitoring is enabled, the high
+// watermark of the partition of msg is tracked as well. As kafka.Consumer.ReadMessage ignores the OffsetsCommitted
+// events, the commit offsets of the consumer groups relying on auto-commit are only tracked when consuming with Poll
+// or the Events channel.
 func (c *Consumer) ReadMessage(timeout time.Duration) (*kafka.Message, error) {
-	if c.tracer.PrevSpan != nil {
-		c.tracer.PrevSpan.Finish()
-		c.tracer.PrevSpan = nil
-	}
+	c.tracer.FinishPrevSpan()
 	msg, err := c.Consumer.ReadMessage(timeout)
 	if err != nil {
 		return nil, err
 	}
 	tMsg := wrapMessage(msg)
 	c.tracer.SetConsumeCheckpoint(tMsg)
+	c.tracer.TrackHighWatermarkOffset(wrapTopicPartitions([]kafka.TopicPartition{msg.TopicPartition}), c.Consumer)
 	c.tracer.PrevSpan = c.tracer.StartConsumeSpan(tMsg)
+	c.tracer.PrevOp = c.tracer.StartConsumeOperation(c.tracer.PrevSpan, tMsg)
 	return msg, nil
 }
 
@@ -153,6 +155,25 @@ func (c *Consumer) CommitOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicP
 	return tps, err
 }
 
+// ContextWithMessage returns a copy of ctx holding the AppSec monitoring of the given message consumed by a Consumer,
+// so that the AppSec protections, such as the RASP ones, apply to its processing until the next message is consumed.
+// ctx is returned as is when AppSec is disabled or the message is not being consumed.
+func ContextWithMessage(ctx context.Context, msg *kafka.Message) context.Context {
+	if msg == nil {
+		return ctx
+	}
+	return messagesec.ContextWithMessage(ctx, tracing.MessageKey(wrapMessage(msg)))
+}
+
+// TrackSchema tracks the usage of the given schema, such as an Avro or Protobuf schema of a schema registry, to
+// deserialize the value of msg, when Data Streams Monitoring is enabled.
+func (c *Consumer) TrackSchema(msg *kafka.Message, schema options.Schema) {
+	if msg == nil || msg.TopicPartition.Topic == nil {
+		return
+	}
+	c.tracer.TrackSchema(*msg.TopicPartition.Topic, options.SchemaOperationDeserialization, schema)
+}
+
 // A Producer wraps a kafka.Producer.
 type Producer struct {
 	*kafka.Producer
@@ -189,6 +210,15 @@ func (p *Producer) Close() {
 	p.Producer.Close()
 }
 
+// TrackSchema tracks the usage of the given schema, such as an Avro or Protobuf schema of a schema registry, to
+// serialize the value of msg, when Data Streams Monitoring is enabled.
+func (p *Producer) TrackSchema(msg *kafka.Message, schema options.Schema) {
+	if msg == nil || msg.TopicPartition.Topic == nil {
+		return
+	}
+	p.tracer.TrackSchema(*msg.TopicPartition.Topic, options.SchemaOperationSerialization, schema)
+}
+
 // Produce calls the underlying Producer.Produce and traces the request.
 func (p *Producer) Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
 	tMsg := wrapMessage(msg)
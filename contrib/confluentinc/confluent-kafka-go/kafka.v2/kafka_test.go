@@ -104,6 +104,25 @@ func TestConsumerChannel(t *testing.T) {
 	}
 }
 
+func TestReadMessageTimeout(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	c, err := NewConsumer(&kafka.ConfigMap{
+		"group.id":           testGroupID,
+		"socket.timeout.ms":  10,
+		"session.timeout.ms": 10,
+	}, WithDataStreams())
+	require.NoError(t, err)
+	defer c.Close()
+
+	msg, err := c.ReadMessage(10 * time.Millisecond)
+	assert.Nil(t, msg)
+	var kErr kafka.Error
+	require.ErrorAs(t, err, &kErr)
+	assert.Equal(t, kafka.ErrTimedOut, kErr.Code())
+}
+
 func TestConsumerFunctional(t *testing.T) {
 	for _, tt := range []struct {
 		name   string
@@ -13,11 +13,13 @@ import (
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
 	"github.com/DataDog/dd-trace-go/v2/internal"
 )
 
 type KafkaTracer struct {
 	PrevSpan            *tracer.Span
+	PrevOp              *messagesec.ConsumeOperation
 	ctx                 context.Context
 	consumerServiceName string
 	producerServiceName string
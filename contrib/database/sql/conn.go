@@ -54,10 +54,11 @@ type TracedConn struct {
 	*traceParams
 }
 
-// checkQuerySafety runs ASM RASP SQLi checks on the query to verify if it can safely be run.
+// checkQuerySecurity runs ASM RASP SQLi checks on the query to verify if it can safely be run,
+// and lets IAST report the query when it holds tainted request data.
 // If it's unsafe to run, an *events.BlockingSecurityEvent is returned
 func checkQuerySecurity(ctx context.Context, query, driver string) error {
-	if !instr.AppSecEnabled() {
+	if !instr.AppSecRASPEnabled() && !instr.AppSecIASTEnabled() {
 		return nil
 	}
 	return sqlsec.ProtectSQLOperation(ctx, query, driver)
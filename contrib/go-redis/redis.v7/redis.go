@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
 
 	"github.com/go-redis/redis/v7"
 )
@@ -108,6 +109,10 @@ func additionalTagOptions(client redis.UniversalClient) []tracer.StartSpanOption
 }
 
 func (ddh *datadogHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	// go-redis does not call AfterProcess when the command is blocked, so no span is started for it
+	if err := protectCommands(ctx, cmd); err != nil {
+		return ctx, err
+	}
 	raw := cmd.String()
 	parts := strings.Split(raw, " ")
 	length := len(parts) - 1
@@ -143,6 +148,9 @@ func (ddh *datadogHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error
 }
 
 func (ddh *datadogHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	if err := protectCommands(ctx, cmds...); err != nil {
+		return ctx, err
+	}
 	raw := commandsToString(cmds)
 	parts := strings.Split(raw, " ")
 	length := len(parts) - 1
@@ -181,6 +189,21 @@ func (ddh *datadogHook) AfterProcessPipeline(ctx context.Context, cmds []redis.C
 	return nil
 }
 
+// protectCommands monitors the arguments of the given commands with AppSec to
+// protect against NoSQL injections, and returns an error when they must be
+// blocked.
+func protectCommands(ctx context.Context, cmds ...redis.Cmder) error {
+	if !instr.AppSecRASPEnabled() {
+		return nil
+	}
+	for _, cmd := range cmds {
+		if err := nosqlsec.ProtectRedisCommand(ctx, cmd.Args()...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // commandsToString returns a string representation of a slice of redis Commands, separated by newlines.
 func commandsToString(cmds []redis.Cmder) string {
 	var b bytes.Buffer
@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -129,7 +130,8 @@ func (ddh *datadogHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (con
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, p.config.analyticsRate))
 	}
 	_, ctx = tracer.StartSpanFromContext(ctx, p.config.spanName, opts...)
-	return ctx, nil
+	// The span is finished by AfterProcess, which go-redis calls even when the command is blocked
+	return ctx, protectCommands(ctx, cmd)
 }
 
 func (ddh *datadogHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
@@ -168,7 +170,7 @@ func (ddh *datadogHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.
 		opts = append(opts, tracer.Tag(ext.EventSampleRate, p.config.analyticsRate))
 	}
 	_, ctx = tracer.StartSpanFromContext(ctx, p.config.spanName, opts...)
-	return ctx, nil
+	return ctx, protectCommands(ctx, cmds...)
 }
 
 func (ddh *datadogHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
@@ -185,6 +187,21 @@ func (ddh *datadogHook) AfterProcessPipeline(ctx context.Context, cmds []redis.C
 	return nil
 }
 
+// protectCommands monitors the arguments of the given commands with AppSec to
+// protect against NoSQL injections, and returns an error when they must be
+// blocked.
+func protectCommands(ctx context.Context, cmds ...redis.Cmder) error {
+	if !instr.AppSecRASPEnabled() {
+		return nil
+	}
+	for _, cmd := range cmds {
+		if err := nosqlsec.ProtectRedisCommand(ctx, cmd.Args()...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // commandsToString returns a string representation of a slice of redis Commands, separated by newlines.
 func commandsToString(cmds []redis.Cmder) string {
 	var b bytes.Buffer
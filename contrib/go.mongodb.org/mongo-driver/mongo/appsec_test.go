@@ -0,0 +1,150 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mongo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec"
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
+	httptrace "github.com/DataDog/dd-trace-go/v2/instrumentation/httptracemock"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+func TestAppSec(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../../internal/appsec/testdata/rasp.json")
+	testutils.StartAppSec(t)
+	if !instr.AppSecRASPEnabled() {
+		t.Skip("RASP needs to be enabled for this test")
+	}
+
+	// The queries are not expected to reach the server when they are blocked
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://127.0.0.1:1").
+		SetServerSelectionTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	defer client.Disconnect(context.Background())
+	coll := WrapCollection(client.Database("test").Collection("users"))
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.NoError(t, appsec.MonitorParsedHTTPBody(r.Context(), body))
+
+		var err error
+		if r.URL.Query().Get("pipeline") == "true" {
+			_, err = coll.Aggregate(r.Context(), bson.A{bson.D{{Key: "$match", Value: bson.D{{Key: "user", Value: body["user"]}}}}})
+		} else {
+			err = coll.FindOne(r.Context(), bson.D{{Key: "user", Value: body["user"]}}).Err()
+		}
+		if r.URL.Query().Get("block") == "true" {
+			require.ErrorIs(t, err, &events.BlockingSecurityEvent{})
+			return
+		}
+		require.NotErrorIs(t, err, &events.BlockingSecurityEvent{})
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name     string
+		body     string
+		pipeline bool
+		block    bool
+	}{
+		{
+			name: "no-block",
+			body: `{"user":"bob"}`,
+		},
+		{
+			name:  "operator-injection",
+			body:  `{"user":{"$ne":null}}`,
+			block: true,
+		},
+		{
+			name:     "operator-injection/pipeline",
+			body:     `{"user":{"$ne":null}}`,
+			pipeline: true,
+			block:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			query := url.Values{
+				"pipeline": {strconv.FormatBool(tc.pipeline)},
+				"block":    {strconv.FormatBool(tc.block)},
+			}
+			res, err := srv.Client().Post(srv.URL+"?"+query.Encode(), "application/json", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			var webSpan *mocktracer.Span
+			for _, s := range mt.FinishedSpans() {
+				if s.OperationName() == "http.request" {
+					webSpan = s
+				}
+			}
+			require.NotNil(t, webSpan)
+
+			if !tc.block {
+				require.Equal(t, http.StatusNoContent, res.StatusCode)
+				require.NotContains(t, webSpan.Tags(), "_dd.appsec.json")
+				return
+			}
+			require.Equal(t, http.StatusForbidden, res.StatusCode)
+			require.Contains(t, webSpan.Tag("_dd.appsec.json"), "rasp-943-100")
+		})
+	}
+}
+
+func TestAppSecDefaultRules(t *testing.T) {
+	testutils.StartAppSec(t)
+	if !instr.AppSecRASPEnabled() {
+		t.Skip("RASP needs to be enabled for this test")
+	}
+
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://127.0.0.1:1").
+		SetServerSelectionTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	defer client.Disconnect(context.Background())
+	coll := WrapCollection(client.Database("test").Collection("users"))
+
+	// No default rule monitors NoSQL queries
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		require.False(t, nosqlsec.IsMonitored(r.Context()))
+		err := coll.FindOne(r.Context(), bson.D{{Key: "user", Value: bson.D{{Key: "$ne", Value: nil}}}}).Err()
+		require.NotErrorIs(t, err, &events.BlockingSecurityEvent{})
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	res, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+}
@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
+)
+
+// Collection is a mongo.Collection whose queries are monitored by AppSec to
+// protect against NoSQL injections. Queries to block are not sent to the
+// server and return an error matching events.BlockingSecurityEvent.
+type Collection struct {
+	*mongo.Collection
+}
+
+// WrapCollection returns the given collection with its queries protected by
+// AppSec.
+func WrapCollection(coll *mongo.Collection) *Collection {
+	return &Collection{Collection: coll}
+}
+
+// Find wraps mongo.Collection.Find.
+func (coll *Collection) Find(ctx context.Context, filter any, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	if err := coll.protectQuery(ctx, "find", "filter", filter); err != nil {
+		return nil, err
+	}
+	return coll.Collection.Find(ctx, filter, opts...)
+}
+
+// FindOne wraps mongo.Collection.FindOne.
+func (coll *Collection) FindOne(ctx context.Context, filter any, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	if err := coll.protectQuery(ctx, "find", "filter", filter); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return coll.Collection.FindOne(ctx, filter, opts...)
+}
+
+// FindOneAndDelete wraps mongo.Collection.FindOneAndDelete.
+func (coll *Collection) FindOneAndDelete(ctx context.Context, filter any, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
+	if err := coll.protectQuery(ctx, "findAndModify", "query", filter); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return coll.Collection.FindOneAndDelete(ctx, filter, opts...)
+}
+
+// FindOneAndReplace wraps mongo.Collection.FindOneAndReplace.
+func (coll *Collection) FindOneAndReplace(ctx context.Context, filter, replacement any, opts ...*options.FindOneAndReplaceOptions) *mongo.SingleResult {
+	if err := coll.protectQuery(ctx, "findAndModify", "query", filter); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return coll.Collection.FindOneAndReplace(ctx, filter, replacement, opts...)
+}
+
+// FindOneAndUpdate wraps mongo.Collection.FindOneAndUpdate.
+func (coll *Collection) FindOneAndUpdate(ctx context.Context, filter, update any, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	if err := coll.protectQuery(ctx, "findAndModify", "query", filter); err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	return coll.Collection.FindOneAndUpdate(ctx, filter, update, opts...)
+}
+
+// CountDocuments wraps mongo.Collection.CountDocuments.
+func (coll *Collection) CountDocuments(ctx context.Context, filter any, opts ...*options.CountOptions) (int64, error) {
+	if err := coll.protectQuery(ctx, "count", "query", filter); err != nil {
+		return 0, err
+	}
+	return coll.Collection.CountDocuments(ctx, filter, opts...)
+}
+
+// Distinct wraps mongo.Collection.Distinct.
+func (coll *Collection) Distinct(ctx context.Context, fieldName string, filter any, opts ...*options.DistinctOptions) ([]any, error) {
+	if err := coll.protectQuery(ctx, "distinct", "query", filter); err != nil {
+		return nil, err
+	}
+	return coll.Collection.Distinct(ctx, fieldName, filter, opts...)
+}
+
+// DeleteOne wraps mongo.Collection.DeleteOne.
+func (coll *Collection) DeleteOne(ctx context.Context, filter any, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := coll.protectQuery(ctx, "delete", "q", filter); err != nil {
+		return nil, err
+	}
+	return coll.Collection.DeleteOne(ctx, filter, opts...)
+}
+
+// DeleteMany wraps mongo.Collection.DeleteMany.
+func (coll *Collection) DeleteMany(ctx context.Context, filter any, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	if err := coll.protectQuery(ctx, "delete", "q", filter); err != nil {
+		return nil, err
+	}
+	return coll.Collection.DeleteMany(ctx, filter, opts...)
+}
+
+// UpdateOne wraps mongo.Collection.UpdateOne.
+func (coll *Collection) UpdateOne(ctx context.Context, filter, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := coll.protectQuery(ctx, "update", "q", filter); err != nil {
+		return nil, err
+	}
+	return coll.Collection.UpdateOne(ctx, filter, update, opts...)
+}
+
+// UpdateMany wraps mongo.Collection.UpdateMany.
+func (coll *Collection) UpdateMany(ctx context.Context, filter, update any, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	if err := coll.protectQuery(ctx, "update", "q", filter); err != nil {
+		return nil, err
+	}
+	return coll.Collection.UpdateMany(ctx, filter, update, opts...)
+}
+
+// ReplaceOne wraps mongo.Collection.ReplaceOne.
+func (coll *Collection) ReplaceOne(ctx context.Context, filter, replacement any, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
+	if err := coll.protectQuery(ctx, "update", "q", filter); err != nil {
+		return nil, err
+	}
+	return coll.Collection.ReplaceOne(ctx, filter, replacement, opts...)
+}
+
+// Aggregate wraps mongo.Collection.Aggregate.
+func (coll *Collection) Aggregate(ctx context.Context, pipeline any, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	if err := coll.protectQuery(ctx, "aggregate", "pipeline", pipeline); err != nil {
+		return nil, err
+	}
+	return coll.Collection.Aggregate(ctx, pipeline, opts...)
+}
+
+// protectQuery monitors the query of the given command with AppSec, and
+// returns an error when it must be blocked. The query is monitored in a
+// document shaped like the command sent to the server, decoded as maps so that
+// its keys, such as query operators, can be matched.
+func (coll *Collection) protectQuery(ctx context.Context, command, field string, query any) error {
+	if !instr.AppSecRASPEnabled() || !nosqlsec.IsMonitored(ctx) {
+		return nil
+	}
+	raw, err := bson.Marshal(bson.D{
+		{Key: command, Value: coll.Name()},
+		{Key: field, Value: query},
+	})
+	if err != nil {
+		// The driver fails the same way when sending the query
+		instr.Logger().Debug("contrib/go.mongodb.org/mongo-driver/mongo: could not encode the %s query: %v", command, err)
+		return nil
+	}
+	dec, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(raw))
+	if err != nil {
+		instr.Logger().Debug("contrib/go.mongodb.org/mongo-driver/mongo: could not decode the %s query: %v", command, err)
+		return nil
+	}
+	dec.DefaultDocumentM()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		instr.Logger().Debug("contrib/go.mongodb.org/mongo-driver/mongo: could not decode the %s query: %v", command, err)
+		return nil
+	}
+	return nosqlsec.ProtectNoSQLOperation(ctx, nosqlsec.SystemMongoDB, doc)
+}
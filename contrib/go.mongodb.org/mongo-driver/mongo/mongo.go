@@ -7,6 +7,8 @@
 // It support v0.2.0 of github.com/mongodb/mongo-go-driver
 //
 // `NewMonitor` will return an event.CommandMonitor which is used to trace requests.
+// `WrapCollection` will return a Collection whose queries are protected by AppSec
+// against NoSQL injections.
 package mongo
 
 import (
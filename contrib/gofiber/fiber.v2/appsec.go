@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package fiber
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/httpsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/fasthttpsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/trace"
+)
+
+// withAppSec executes the next handlers monitored by AppSec. The next handlers
+// are not executed when the request is blocked, and their response is replaced
+// when the request gets blocked while they run.
+func withAppSec(c *fiber.Ctx, span trace.TagSetter) error {
+	fctx := c.Context()
+	// The path parameters are only known here when the middleware is attached to
+	// the route. Otherwise, they are monitored once c.Next() resolved the route.
+	pathParams := c.AllParams()
+	op, blockAtomic, ctx := httpsec.StartOperation(c.UserContext(), httpsec.HandlerOperationArgs{
+		Framework:   "github.com/gofiber/fiber/v2",
+		Method:      c.Method(),
+		RequestURI:  c.OriginalURL(),
+		Host:        string(fctx.Host()),
+		RemoteAddr:  fctx.RemoteAddr().String(),
+		Headers:     fasthttpsec.MakeHeaders(&fctx.Request.Header),
+		Cookies:     fasthttpsec.MakeCookies(&fctx.Request.Header),
+		QueryParams: fasthttpsec.MakeQueryParams(fctx.QueryArgs()),
+		PathParams:  pathParams,
+	}, span)
+	// Handlers may either use the user context or the fasthttp request context
+	c.SetUserContext(ctx)
+	defer fasthttpsec.RegisterOperation(fctx, op)()
+
+	if blockAtomic.Load() == nil {
+		op.MonitorRequestBody(string(fctx.Request.Header.ContentType()), fctx.PostBody())
+	}
+	var err error
+	if blockAtomic.Load() == nil {
+		err = c.Next()
+		if len(pathParams) == 0 {
+			op.MonitorPathParams(c.AllParams())
+		}
+	}
+
+	op.Finish(httpsec.HandlerOperationRes{
+		Headers:    fasthttpsec.MakeHeaders(&fctx.Response.Header),
+		StatusCode: c.Response().StatusCode(),
+		Body:       op.ParseResponseBody(string(fctx.Response.Header.ContentType()), fctx.Response.Body()),
+	})
+
+	if blockPtr := blockAtomic.Load(); blockPtr != nil && blockPtr.Handler != nil {
+		fctx.Response.Reset()
+		fasthttpadaptor.NewFastHTTPHandler(blockPtr.Handler)(fctx)
+		// The response is written: the blocking error must not reach the fiber error handler
+		var blockErr *events.BlockingSecurityEvent
+		if errors.As(err, &blockErr) {
+			err = nil
+		}
+	}
+	return err
+}
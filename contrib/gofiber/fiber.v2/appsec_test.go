@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package fiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+func newAppSecRouter() *fiber.App {
+	router := fiber.New()
+	router.Use(Middleware())
+	router.All("/lfi/*", func(c *fiber.Ctx) error {
+		return c.SendString("Hello World!\n")
+	})
+	router.All("/user", func(c *fiber.Ctx) error {
+		if err := appsec.SetUser(c.UserContext(), c.Get("test-usr")); err != nil {
+			return err
+		}
+		return c.SendString("Hello World!\n")
+	})
+	router.All("/body", func(c *fiber.Ctx) error {
+		if err := appsec.MonitorParsedHTTPBody(c.Context(), string(c.Body())); err != nil {
+			return err
+		}
+		return c.SendString("Hello World!\n")
+	})
+	router.All("/", func(c *fiber.Ctx) error {
+		return c.SendString("Hello World!\n")
+	})
+	return router
+}
+
+func TestAppSec(t *testing.T) {
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("appsec disabled")
+	}
+	router := newAppSecRouter()
+
+	t.Run("request-uri", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		// Send an LFI attack (according to appsec rule id crs-930-110)
+		req := httptest.NewRequest("POST", "/lfi/../../../secret.txt", nil)
+		res, err := router.Test(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		finished := mt.FinishedSpans()
+		require.Len(t, finished, 1)
+		event, _ := finished[0].Tag("_dd.appsec.json").(string)
+		require.Contains(t, event, "server.request.uri.raw")
+		require.Contains(t, event, "crs-930-110")
+	})
+
+	t.Run("SDK-body", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		req := httptest.NewRequest("POST", "/body", strings.NewReader("$globals"))
+		res, err := router.Test(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		finished := mt.FinishedSpans()
+		require.Len(t, finished, 1)
+		event, _ := finished[0].Tag("_dd.appsec.json").(string)
+		require.Contains(t, event, "server.request.body")
+	})
+}
+
+func TestBlocking(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/blocking.json")
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+	router := newAppSecRouter()
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		headers map[string]string
+		status  int
+	}{
+		{
+			name:   "no-block",
+			path:   "/",
+			status: 200,
+		},
+		{
+			name:    "ip/block",
+			path:    "/",
+			headers: map[string]string{"x-forwarded-for": "1.2.3.4"},
+			status:  403,
+		},
+		{
+			name:    "ip/no-block",
+			path:    "/",
+			headers: map[string]string{"x-forwarded-for": "1.2.3.5"},
+			status:  200,
+		},
+		{
+			name:    "user/block",
+			path:    "/user",
+			headers: map[string]string{"test-usr": "blocked-user-1"},
+			status:  403,
+		},
+		{
+			name:    "user/no-block",
+			path:    "/user",
+			headers: map[string]string{"test-usr": "legit-user"},
+			status:  200,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			req := httptest.NewRequest("POST", tc.path, nil)
+			req.Header.Set("Accept", "text/html")
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			res, err := router.Test(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			b, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.status, res.StatusCode)
+			if tc.status == 200 {
+				require.Equal(t, "Hello World!\n", string(b))
+				return
+			}
+			require.Equal(t, "text/html", res.Header.Get("Content-Type"))
+			require.Contains(t, string(b), "You've been blocked")
+
+			finished := mt.FinishedSpans()
+			require.Len(t, finished, 1)
+			require.Equal(t, "true", finished[0].Tag("appsec.blocked"))
+			require.Equal(t, "403", finished[0].Tag("http.status_code"))
+		})
+	}
+}
+
+func TestPathParams(t *testing.T) {
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("appsec disabled")
+	}
+	hello := func(c *fiber.Ctx) error {
+		return c.SendString("Hello World!\n")
+	}
+	for _, tc := range []struct {
+		name  string
+		setup func(router *fiber.App)
+	}{
+		{
+			// the route is only resolved once the middleware calls the next handlers
+			name: "global-middleware",
+			setup: func(router *fiber.App) {
+				router.Use(Middleware())
+				router.Get("/files/:file", hello)
+			},
+		},
+		{
+			name: "route-middleware",
+			setup: func(router *fiber.App) {
+				router.Get("/files/:file", Middleware(), hello)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := fiber.New()
+			tc.setup(router)
+
+			mt := mocktracer.Start()
+			defer mt.Stop()
+			// Send a security scanner attack via the path parameters (according to appsec rule id crs-913-120)
+			req := httptest.NewRequest("GET", "/files/appscan_fingerprint", nil)
+			res, err := router.Test(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			finished := mt.FinishedSpans()
+			require.Len(t, finished, 1)
+			event, _ := finished[0].Tag("_dd.appsec.json").(string)
+			require.Contains(t, event, "server.request.path_params")
+			require.Contains(t, event, "crs-913-120")
+		})
+	}
+}
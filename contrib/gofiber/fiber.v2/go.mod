@@ -6,6 +6,7 @@ require (
 	github.com/DataDog/dd-trace-go/v2 v2.1.0-dev
 	github.com/gofiber/fiber/v2 v2.52.5
 	github.com/stretchr/testify v1.10.0
+	github.com/valyala/fasthttp v1.51.0
 )
 
 require (
@@ -60,7 +61,6 @@ require (
 	github.com/tklauser/go-sysconf v0.3.14 // indirect
 	github.com/tklauser/numcpus v0.8.0 // indirect
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	github.com/valyala/fasthttp v1.51.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
 	github.com/yusufpapurcu/wmi v1.2.4 // indirect
 	go.opentelemetry.io/collector/component v0.120.0 // indirect
@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package redigo
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	httptrace "github.com/DataDog/dd-trace-go/v2/instrumentation/httptracemock"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+func TestAppSec(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/rasp.json")
+	testutils.StartAppSec(t)
+	if !instr.AppSecRASPEnabled() {
+		t.Skip("RASP needs to be enabled for this test")
+	}
+
+	// The commands are not expected to reach the server when they are blocked
+	conn, err := Dial("tcp", "127.0.0.1:6379", redis.DialNetDial(func(string, string) (net.Conn, error) {
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		_, err := conn.Do("EVAL", r.URL.Query().Get("script"), 0, r.Context())
+		if r.URL.Query().Get("block") == "true" {
+			require.ErrorIs(t, err, &events.BlockingSecurityEvent{})
+			return
+		}
+		require.NotErrorIs(t, err, &events.BlockingSecurityEvent{})
+		w.WriteHeader(http.StatusNoContent)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name   string
+		script string
+		block  bool
+	}{
+		{
+			name:   "no-block",
+			script: "return redis.call('get', 'key')",
+		},
+		{
+			name:   "lua-injection",
+			script: "return redis.call('flushall')",
+			block:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			query := url.Values{
+				"script": {tc.script},
+				"block":  {strconv.FormatBool(tc.block)},
+			}
+			res, err := srv.Client().Get(srv.URL + "?" + query.Encode())
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			var webSpan *mocktracer.Span
+			for _, s := range mt.FinishedSpans() {
+				if s.OperationName() == "http.request" {
+					webSpan = s
+				}
+			}
+			require.NotNil(t, webSpan)
+
+			if !tc.block {
+				require.Equal(t, http.StatusNoContent, res.StatusCode)
+				return
+			}
+			require.Equal(t, http.StatusForbidden, res.StatusCode)
+			require.Contains(t, webSpan.Tag("_dd.appsec.json"), "rasp-943-110")
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
 
 	"github.com/gomodule/redigo/redis"
 )
@@ -179,6 +180,10 @@ func withSpan(ctx context.Context, do func(commandName string, args ...interface
 		}
 	}
 
+	if err := protectCommand(ctx, commandName, args...); err != nil {
+		return nil, err
+	}
+
 	span := newChildSpan(ctx, p)
 	defer func() {
 		span.Finish(tracer.WithError(err))
@@ -214,6 +219,15 @@ func withSpan(ctx context.Context, do func(commandName string, args ...interface
 	return do(commandName, args...)
 }
 
+// protectCommand monitors the given command with AppSec to protect against
+// NoSQL injections, and returns an error when it must be blocked.
+func protectCommand(ctx context.Context, commandName string, args ...interface{}) error {
+	if commandName == "" || !instr.AppSecRASPEnabled() {
+		return nil
+	}
+	return nosqlsec.ProtectRedisCommand(ctx, append([]interface{}{commandName}, args...)...)
+}
+
 // Do wraps redis.Conn.Do. It sends a command to the Redis server and returns the received reply.
 // In the process it emits a span containing key information about the command sent.
 // When passed a context.Context as the final argument, Do will ensure that any span created
@@ -77,6 +77,9 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 	}
 	instr.Logger().Debug("contrib/google.golang.org/grpc: Configuring StreamClientInterceptor: %#v", cfg)
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if cfg.dataStreamsEnabled {
+			ctx = setClientCheckpoint(ctx, nil)
+		}
 		var methodKind string
 		if desc != nil {
 			switch {
@@ -148,6 +151,9 @@ func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
 	}
 	instr.Logger().Debug("contrib/google.golang.org/grpc: Configuring UnaryClientInterceptor: %#v", cfg)
 	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if cfg.dataStreamsEnabled {
+			ctx = setClientCheckpoint(ctx, req)
+		}
 		if _, ok := cfg.untracedMethods[method]; ok {
 			return invoker(ctx, method, req, reply, cc, opts...)
 		}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package grpc
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/contrib/google.golang.org/grpc/v2/internal/grpcutil"
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const dataStreamsType = "type:grpc"
+
+// setClientCheckpoint sets the Data Streams checkpoint of an outgoing call, whose parent pathway is the one of ctx,
+// and returns a copy of ctx whose outgoing metadata propagates the resulting pathway.
+func setClientCheckpoint(ctx context.Context, req interface{}) context.Context {
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: getMsgSize(req)}, "direction:out", dataStreamsType)
+	if !ok {
+		return ctx
+	}
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		// we have to copy the metadata because its not safe to modify
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	datastreams.InjectToBase64Carrier(ctx, grpcutil.MDCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// setServerCheckpoint sets the Data Streams checkpoint of an incoming call, whose parent pathway is the one
+// propagated in the incoming metadata merged with the one of ctx, if any, and returns a copy of ctx holding the
+// resulting pathway.
+func setServerCheckpoint(ctx context.Context, req interface{}) context.Context {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = datastreams.MergeContexts(ctx, datastreams.ExtractFromBase64Carrier(ctx, grpcutil.MDCarrier(md)))
+	}
+	ctx, _ = tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: getMsgSize(req)}, "direction:in", dataStreamsType)
+	return ctx
+}
+
+// getMsgSize returns the size of the protobuf message m, or 0 when m is not a protobuf message, e.g. for streams.
+func getMsgSize(m interface{}) int64 {
+	if msg, ok := m.(proto.Message); ok {
+		return int64(proto.Size(msg))
+	}
+	return 0
+}
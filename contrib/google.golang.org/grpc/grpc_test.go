@@ -17,7 +17,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DataDog/dd-trace-go/contrib/google.golang.org/grpc/v2/internal/grpcutil"
 	"github.com/DataDog/dd-trace-go/instrumentation/testutils/grpc/v2/fixturepb"
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
@@ -1097,3 +1100,23 @@ func TestIssue2050(t *testing.T) {
 		return
 	}
 }
+
+func TestDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	rig, err := newRig(true, WithDataStreams())
+	require.NoError(t, err, "error setting up rig")
+	defer func() { assert.NoError(t, rig.Close()) }()
+
+	req := &fixturepb.FixtureRequest{Name: "pass"}
+	_, err = rig.client.Ping(context.Background(), req)
+	require.NoError(t, err)
+
+	md := rig.fixtureServer.LastRequestMetadata.Load().(metadata.MD)
+	got, err := datastreams.InspectBase64Carrier(grpcutil.MDCarrier(md))
+	require.NoError(t, err, "pathway not found in the metadata")
+	ctx, _ := tracer.SetDataStreamsCheckpointWithParams(context.Background(), options.CheckpointParams{PayloadSize: getMsgSize(req)}, "direction:out", "type:grpc")
+	want, _ := datastreams.PathwayFromContext(ctx)
+	assert.Equal(t, want.GetHash(), got.Hash)
+}
@@ -66,6 +66,7 @@ type config struct {
 	withErrorDetailTags bool
 	spanOpts            []tracer.StartSpanOption
 	tags                map[string]interface{}
+	dataStreamsEnabled  bool
 }
 
 func defaults(cfg *config) {
@@ -221,3 +222,12 @@ func WithSpanOptions(opts ...tracer.StartSpanOption) OptionFn {
 		cfg.spanOpts = append(cfg.spanOpts, opts...)
 	}
 }
+
+// WithDataStreams enables the Data Streams Monitoring checkpoints of the calls, with the edge tag type:grpc, so that
+// the pathways are propagated in the gRPC metadata across the services connected synchronously. It must be set on
+// both the client and the server interceptors, and Data Streams Monitoring must be enabled on the tracer.
+func WithDataStreams() OptionFn {
+	return func(cfg *config) {
+		cfg.dataStreamsEnabled = true
+	}
+}
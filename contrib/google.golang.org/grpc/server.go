@@ -84,6 +84,9 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 	instr.Logger().Debug("contrib/google.golang.org/grpc: Configuring StreamServerInterceptor: %#v", cfg)
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
 		ctx := ss.Context()
+		if cfg.dataStreamsEnabled {
+			ctx = setServerCheckpoint(ctx, nil)
+		}
 		// if we've enabled call tracing, create a span
 		_, um := cfg.untracedMethods[info.FullMethod]
 		if cfg.traceStreamCalls && !um {
@@ -131,6 +134,9 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 	}
 	instr.Logger().Debug("contrib/google.golang.org/grpc: Configuring UnaryServerInterceptor: %#v", cfg)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if cfg.dataStreamsEnabled {
+			ctx = setServerCheckpoint(ctx, req)
+		}
 		_, um := cfg.untracedMethods[info.FullMethod]
 		if um {
 			return handler(ctx, req)
@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package websocket
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+)
+
+type config struct {
+	serviceName  string
+	spanName     string
+	messageSpans bool
+}
+
+// Option describes options for the WebSocket integration.
+type Option interface {
+	apply(*config)
+}
+
+// OptionFn represents options applicable to WrapUpgrader.
+type OptionFn func(*config)
+
+func (fn OptionFn) apply(cfg *config) {
+	fn(cfg)
+}
+
+func newConfig(opts ...Option) *config {
+	cfg := new(config)
+	defaults(cfg)
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+	return cfg
+}
+
+func defaults(cfg *config) {
+	cfg.serviceName = instr.ServiceName(instrumentation.ComponentServer, nil)
+	cfg.spanName = instr.OperationName(instrumentation.ComponentServer, nil)
+}
+
+// WithService sets the given service name for the traced connections.
+func WithService(name string) OptionFn {
+	return func(cfg *config) {
+		cfg.serviceName = name
+	}
+}
+
+// WithMessageSpans enables or disables the creation of a span per message
+// sent or received by the traced connections. It is disabled by default, so
+// that only the connection lifecycle is traced.
+func WithMessageSpans(enabled bool) OptionFn {
+	return func(cfg *config) {
+		cfg.messageSpans = enabled
+	}
+}
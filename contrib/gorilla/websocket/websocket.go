@@ -0,0 +1,255 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package websocket provides functions to trace the gorilla/websocket package (https://github.com/gorilla/websocket).
+//
+// The connections upgraded by a wrapped Upgrader are traced from their upgrade
+// to their closing, and their inbound messages are monitored by AppSec until
+// the connection is closed, including after the return of the handler of their
+// upgrade request. The security events of the messages are reported in the
+// connection span. The connection is closed with the policy violation status
+// when AppSec blocks one of its messages.
+package websocket // import "github.com/DataDog/dd-trace-go/contrib/gorilla/websocket/v2"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/websocketsec"
+)
+
+const (
+	receiveSpanName = "websocket.receive"
+	sendSpanName    = "websocket.send"
+
+	// closeTimeout is the maximum duration of the sending of the close message of a blocked connection.
+	closeTimeout = time.Second
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageGorillaWebsocket)
+}
+
+// Upgrader wraps a websocket.Upgrader so that the upgraded connections are traced.
+type Upgrader struct {
+	*websocket.Upgrader
+	cfg *config
+}
+
+// WrapUpgrader wraps a websocket.Upgrader so that the upgraded connections are traced.
+func WrapUpgrader(u *websocket.Upgrader, opts ...Option) *Upgrader {
+	cfg := newConfig(opts...)
+	instr.Logger().Debug("contrib/gorilla/websocket: Wrapping Upgrader: %#v", cfg)
+	return &Upgrader{Upgrader: u, cfg: cfg}
+}
+
+// Upgrade calls the underlying Upgrader.Upgrade and returns the traced connection. The request context is used as the
+// context of the connection, whose span is started as a child of the request span and finished by Conn.Close.
+func (u *Upgrader) Upgrade(w http.ResponseWriter, r *http.Request, responseHeader http.Header) (*Conn, error) {
+	conn, err := u.Upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		return nil, err
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(u.cfg.serviceName),
+		tracer.ResourceName(r.URL.Path),
+		tracer.Tag(ext.Component, instrumentation.PackageGorillaWebsocket),
+		tracer.Tag(ext.SpanKind, ext.SpanKindServer),
+		tracer.Tag(ext.HTTPURL, r.URL.String()),
+	}
+	if p := conn.Subprotocol(); p != "" {
+		opts = append(opts, tracer.Tag("websocket.subprotocol", p))
+	}
+	span, ctx := tracer.StartSpanFromContext(r.Context(), u.cfg.spanName, opts...)
+	c := &Conn{Conn: conn, span: span, cfg: u.cfg}
+	if instr.AppSecEnabled() {
+		ctx, c.op = websocketsec.StartConnectionOperation(ctx, span)
+	}
+	c.ctx = ctx
+	return c, nil
+}
+
+// Conn wraps a websocket.Conn so that it is traced and its inbound messages are monitored by AppSec.
+// Conn.Close must be called to finish the connection span.
+type Conn struct {
+	*websocket.Conn
+	ctx       context.Context
+	span      *tracer.Span
+	op        *websocketsec.ConnectionOperation
+	cfg       *config
+	closeOnce sync.Once
+}
+
+// ReadMessage reads the next message of the connection, as websocket.Conn.ReadMessage does. When AppSec blocks the
+// message, the connection is closed and an *events.BlockingSecurityEvent is returned.
+func (c *Conn) ReadMessage() (messageType int, p []byte, err error) {
+	messageType, p, err = c.Conn.ReadMessage()
+	if err != nil {
+		c.readError(err)
+		return messageType, p, err
+	}
+	if err := c.received(messageType, p); err != nil {
+		return messageType, nil, err
+	}
+	return messageType, p, nil
+}
+
+// NextReader returns the reader of the next message of the connection, as websocket.Conn.NextReader does. When AppSec
+// is enabled, the message is read entirely before being returned, so that it can be inspected. When AppSec blocks
+// the message, the connection is closed and an *events.BlockingSecurityEvent is returned.
+func (c *Conn) NextReader() (messageType int, r io.Reader, err error) {
+	messageType, r, err = c.Conn.NextReader()
+	if err != nil {
+		c.readError(err)
+		return messageType, r, err
+	}
+	if !instr.AppSecEnabled() {
+		return messageType, r, c.received(messageType, nil)
+	}
+	p, err := io.ReadAll(r)
+	if err != nil {
+		c.readError(err)
+		return messageType, nil, err
+	}
+	if err := c.received(messageType, p); err != nil {
+		return messageType, nil, err
+	}
+	return messageType, bytes.NewReader(p), nil
+}
+
+// ReadJSON reads the next JSON-encoded message of the connection and stores it in the value pointed to by v, as
+// websocket.Conn.ReadJSON does. When AppSec blocks the message, the connection is closed and an
+// *events.BlockingSecurityEvent is returned.
+func (c *Conn) ReadJSON(v any) error {
+	_, r, err := c.NextReader()
+	if err != nil {
+		return err
+	}
+	err = json.NewDecoder(r).Decode(v)
+	if err == io.EOF {
+		// One value is expected in the message.
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// WriteMessage writes a message to the connection, as websocket.Conn.WriteMessage does.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	span := c.startMessageSpan(sendSpanName, messageType, len(data))
+	err := c.Conn.WriteMessage(messageType, data)
+	finishMessageSpan(span, err)
+	return err
+}
+
+// WriteJSON writes the JSON encoding of v as a message to the connection, as websocket.Conn.WriteJSON does.
+func (c *Conn) WriteJSON(v any) error {
+	span := c.startMessageSpan(sendSpanName, websocket.TextMessage, -1)
+	err := c.Conn.WriteJSON(v)
+	finishMessageSpan(span, err)
+	return err
+}
+
+// Close closes the underlying connection and finishes the connection span.
+func (c *Conn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() {
+		if c.op != nil {
+			c.op.Finish()
+		}
+		c.span.Finish()
+	})
+	return err
+}
+
+// received traces the received message of the given type and monitors its payload with AppSec, when enabled. It
+// returns the *events.BlockingSecurityEvent of the message when blocked, after closing the connection.
+func (c *Conn) received(messageType int, p []byte) error {
+	size := len(p)
+	if p == nil {
+		size = -1
+	}
+	span := c.startMessageSpan(receiveSpanName, messageType, size)
+	if !instr.AppSecEnabled() || (messageType != websocket.TextMessage && messageType != websocket.BinaryMessage) {
+		finishMessageSpan(span, nil)
+		return nil
+	}
+	err := websocketsec.ProtectMessage(c.ctx, p)
+	finishMessageSpan(span, err)
+	if err != nil {
+		c.block()
+	}
+	return err
+}
+
+// block closes the connection with the policy violation status.
+func (c *Conn) block() {
+	msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "blocked")
+	if err := c.Conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(closeTimeout)); err != nil {
+		instr.Logger().Debug("contrib/gorilla/websocket: failed to send the close message of the blocked connection: %v", err)
+	}
+	c.Close()
+}
+
+// readError reports the given read error in the connection span, unless it is the normal closing of the connection.
+func (c *Conn) readError(err error) {
+	if _, ok := err.(*websocket.CloseError); ok && !websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		return
+	}
+	c.span.SetTag(ext.Error, err)
+}
+
+// startMessageSpan starts the span of a message of the given type and size, unless message spans are disabled. The
+// size is not tagged when negative.
+func (c *Conn) startMessageSpan(name string, messageType int, size int) *tracer.Span {
+	if !c.cfg.messageSpans {
+		return nil
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(c.cfg.serviceName),
+		tracer.Tag(ext.Component, instrumentation.PackageGorillaWebsocket),
+		tracer.Tag("websocket.message.type", messageTypeName(messageType)),
+	}
+	if size >= 0 {
+		opts = append(opts, tracer.Tag("websocket.message.length", size))
+	}
+	span, _ := tracer.StartSpanFromContext(c.ctx, name, opts...)
+	return span
+}
+
+func finishMessageSpan(span *tracer.Span, err error) {
+	if span == nil {
+		return
+	}
+	span.Finish(tracer.WithError(err))
+}
+
+func messageTypeName(messageType int) string {
+	switch messageType {
+	case websocket.TextMessage:
+		return "text"
+	case websocket.BinaryMessage:
+		return "binary"
+	case websocket.CloseMessage:
+		return "close"
+	case websocket.PingMessage:
+		return "ping"
+	case websocket.PongMessage:
+		return "pong"
+	default:
+		return "unknown"
+	}
+}
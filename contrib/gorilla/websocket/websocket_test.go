@@ -0,0 +1,186 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httptrace "github.com/DataDog/dd-trace-go/contrib/net/http/v2"
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+// startEchoServer starts a traced HTTP server echoing the messages of the WebSocket connections upgraded by /ws. The
+// errors returned by the connection reads are sent to readErrs. When async is true, the messages are read in a
+// goroutine once the upgrade request handler has returned.
+func startEchoServer(t *testing.T, readErrs chan<- error, async bool, opts ...Option) string {
+	upgrader := WrapUpgrader(&websocket.Upgrader{}, opts...)
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		if async {
+			go echo(conn, readErrs)
+			return
+		}
+		echo(conn, readErrs)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+}
+
+func echo(conn *Conn, readErrs chan<- error) {
+	defer conn.Close()
+	for {
+		messageType, p, err := conn.ReadMessage()
+		if err != nil {
+			readErrs <- err
+			return
+		}
+		if err := conn.WriteMessage(messageType, p); err != nil {
+			return
+		}
+	}
+}
+
+func TestConnection(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	readErrs := make(chan error, 1)
+	url := startEchoServer(t, readErrs, false, WithService("chat"), WithMessageSpans(true))
+
+	client, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	require.NoError(t, client.WriteMessage(websocket.TextMessage, []byte("hello")))
+	_, p, err := client.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(p))
+	require.NoError(t, client.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+	require.True(t, websocket.IsCloseError(<-readErrs, websocket.CloseNormalClosure))
+	client.Close()
+
+	require.Eventually(t, func() bool { return len(mt.FinishedSpans()) == 4 }, time.Second, 10*time.Millisecond)
+	spans := make(map[string]*mocktracer.Span)
+	for _, s := range mt.FinishedSpans() {
+		spans[s.OperationName()] = s
+	}
+	require.Contains(t, spans, "http.request")
+	require.Contains(t, spans, "websocket.connection")
+	require.Contains(t, spans, "websocket.receive")
+	require.Contains(t, spans, "websocket.send")
+
+	conn := spans["websocket.connection"]
+	assert.Equal(t, spans["http.request"].SpanID(), conn.ParentID())
+	assert.Equal(t, "chat", conn.Tag(ext.ServiceName))
+	assert.Equal(t, "/ws", conn.Tag(ext.ResourceName))
+	assert.Equal(t, "gorilla/websocket", conn.Tag(ext.Component))
+	assert.Equal(t, ext.SpanKindServer, conn.Tag(ext.SpanKind))
+	assert.Nil(t, conn.Tag(ext.ErrorMsg))
+
+	for _, name := range []string{"websocket.receive", "websocket.send"} {
+		s := spans[name]
+		assert.Equal(t, conn.SpanID(), s.ParentID())
+		assert.Equal(t, "text", s.Tag("websocket.message.type"))
+		assert.Equal(t, float64(5), s.Tag("websocket.message.length"))
+	}
+}
+
+func TestAppSec(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/blocking.json")
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+
+	for _, tc := range []struct {
+		name    string
+		message string
+		block   bool
+		ruleID  string
+	}{
+		{
+			name:    "no-attack",
+			message: `{"comment":"hello"}`,
+		},
+		{
+			name:    "monitoring",
+			message: `{"comment":"<script>alert(1)</script>"}`,
+			ruleID:  "crs-941-110",
+		},
+		{
+			name:    "blocking",
+			message: `{"name":"$globals"}`,
+			block:   true,
+			ruleID:  "crs-933-130-block",
+		},
+	} {
+		for _, async := range []bool{false, true} {
+			name := tc.name
+			if async {
+				// The messages are read once the upgrade request handler has returned
+				name += "/async"
+			}
+			t.Run(name, func(t *testing.T) {
+				mt := mocktracer.Start()
+				defer mt.Stop()
+
+				readErrs := make(chan error, 1)
+				url := startEchoServer(t, readErrs, async)
+
+				client, _, err := websocket.DefaultDialer.Dial(url, nil)
+				require.NoError(t, err)
+				defer client.Close()
+				require.NoError(t, client.WriteMessage(websocket.TextMessage, []byte(tc.message)))
+
+				_, p, err := client.ReadMessage()
+				if tc.block {
+					require.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "unexpected error %v", err)
+					require.ErrorIs(t, <-readErrs, &events.BlockingSecurityEvent{})
+				} else {
+					require.NoError(t, err)
+					require.Equal(t, tc.message, string(p))
+					require.NoError(t, client.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+					<-readErrs
+				}
+
+				spans := make(map[string]*mocktracer.Span)
+				require.Eventually(t, func() bool {
+					for _, s := range mt.FinishedSpans() {
+						spans[s.OperationName()] = s
+					}
+					return len(spans) == 2
+				}, time.Second, 10*time.Millisecond)
+
+				// The security events of the messages are reported in the connection span, which is still
+				// running when the upgrade request handler returns before the messages are read.
+				require.Nil(t, spans["http.request"].Tag("_dd.appsec.json"))
+				connSpan := spans["websocket.connection"]
+				if tc.ruleID == "" {
+					require.Nil(t, connSpan.Tag("_dd.appsec.json"))
+					return
+				}
+				require.Contains(t, connSpan.Tag("_dd.appsec.json"), tc.ruleID)
+				if tc.block {
+					require.Equal(t, "true", connSpan.Tag("appsec.blocked"))
+				}
+			})
+		}
+	}
+}
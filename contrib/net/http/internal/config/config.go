@@ -40,11 +40,12 @@ func (o HandlerOptionFn) apply(cfg *Config) {
 }
 
 type CommonConfig struct {
-	AnalyticsRate float64
-	IgnoreRequest func(*http.Request) bool
-	ServiceName   string
-	ResourceNamer func(*http.Request) string
-	SpanOpts      []tracer.StartSpanOption
+	AnalyticsRate      float64
+	IgnoreRequest      func(*http.Request) bool
+	ServiceName        string
+	ResourceNamer      func(*http.Request) string
+	SpanOpts           []tracer.StartSpanOption
+	DataStreamsEnabled bool
 }
 
 type Config struct {
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package wrap
+
+import (
+	"net/http"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+const dataStreamsType = "type:http"
+
+// setClientCheckpoint sets the Data Streams checkpoint of the outgoing request req, whose parent pathway is the one
+// of its context, and propagates the resulting pathway in its headers. req must be a copy of the caller's request.
+func setClientCheckpoint(req *http.Request) {
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(req.Context(), options.CheckpointParams{PayloadSize: getRequestSize(req)}, "direction:out", dataStreamsType)
+	if !ok {
+		return
+	}
+	datastreams.InjectToBase64Carrier(ctx, tracer.HTTPHeadersCarrier(req.Header))
+}
+
+// setServerCheckpoint sets the Data Streams checkpoint of the incoming request r, whose parent pathway is the one
+// propagated in its headers merged with the one of its context, if any, and returns a copy of r whose context holds
+// the resulting pathway.
+func setServerCheckpoint(r *http.Request) *http.Request {
+	ctx := r.Context()
+	ctx = datastreams.MergeContexts(ctx, datastreams.ExtractFromBase64Carrier(ctx, tracer.HTTPHeadersCarrier(r.Header)))
+	ctx, ok := tracer.SetDataStreamsCheckpointWithParams(ctx, options.CheckpointParams{PayloadSize: getRequestSize(r)}, "direction:in", dataStreamsType)
+	if !ok {
+		return r
+	}
+	return r.WithContext(ctx)
+}
+
+// getRequestSize returns the size of the body of r, or 0 when it is unknown.
+func getRequestSize(r *http.Request) int64 {
+	if r.ContentLength < 0 {
+		return 0
+	}
+	return r.ContentLength
+}
@@ -33,6 +33,9 @@ func Handler(h http.Handler, service, resource string, opts ...internal.Option)
 			h.ServeHTTP(w, req)
 			return
 		}
+		if cfg.DataStreamsEnabled {
+			req = setServerCheckpoint(req)
+		}
 		resc := resource
 		if r := cfg.ResourceNamer(req); r != "" {
 			resc = r
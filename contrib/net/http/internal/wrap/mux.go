@@ -45,6 +45,9 @@ func (mux *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		mux.ServeMux.ServeHTTP(w, r)
 		return
 	}
+	if mux.cfg.DataStreamsEnabled {
+		r = setServerCheckpoint(r)
+	}
 	// get the resource associated to this request
 	_, pttrn := mux.Handler(r)
 	route := pattern.Route(pttrn)
@@ -76,6 +76,9 @@ func ObserveRoundTrip(cfg *config.RoundTripperConfig, req *http.Request) (*http.
 	for k, v := range baggage.All(ctx) {
 		span.SetBaggageItem(k, v)
 	}
+	if cfg.DataStreamsEnabled {
+		setClientCheckpoint(req)
+	}
 	if cfg.Propagation {
 		// inject the span context into the http request copy
 		err := tracer.Inject(span.Context(), tracer.HTTPHeadersCarrier(req.Header))
@@ -86,8 +89,9 @@ func ObserveRoundTrip(cfg *config.RoundTripperConfig, req *http.Request) (*http.
 	}
 
 	// if RASP is enabled, check whether the request is supposed to be blocked.
-	if config.Instrumentation.AppSecRASPEnabled() {
-		if err := httpsec.ProtectRoundTrip(ctx, req.URL.String()); err != nil {
+	// IAST also monitors the request URL for user-controlled data.
+	if config.Instrumentation.AppSecRASPEnabled() || config.Instrumentation.AppSecIASTEnabled() {
+		if err := httpsec.ProtectRoundTripURL(ctx, req.URL); err != nil {
 			span.Finish() // Finish the span as we're blocking the request...
 			return nil, nil, err
 		}
@@ -98,6 +98,17 @@ func WithResourceNamer(namer func(req *http.Request) string) OptionFn {
 	return internal.WithResourceNamer(namer)
 }
 
+// WithDataStreams enables the Data Streams Monitoring checkpoints of the requests,
+// with the edge tag type:http, so that the pathways are propagated in the HTTP
+// headers across the services connected synchronously. It must be set on both
+// the client and the server, and Data Streams Monitoring must be enabled on the
+// tracer.
+func WithDataStreams() OptionFn {
+	return func(cfg *internal.CommonConfig) {
+		cfg.DataStreamsEnabled = true
+	}
+}
+
 // NoDebugStack prevents stack traces from being attached to spans finishing
 // with an error. This is useful in situations where errors are frequent and
 // performance is critical.
@@ -24,6 +24,7 @@ import (
 
 	internal "github.com/DataDog/dd-trace-go/contrib/net/http/v2/internal/config"
 	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/datastreams"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/baggage"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
@@ -702,6 +703,37 @@ func TestRoundTripperPropagation(t *testing.T) {
 	defer resp.Body.Close()
 }
 
+func TestRoundTripperDataStreams(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	produced, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "type:http")
+	consumed, _ := tracer.SetDataStreamsCheckpoint(produced, "direction:in", "type:http")
+
+	mux := NewServeMux(WithDataStreams())
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		got, err := datastreams.InspectBase64Carrier(tracer.HTTPHeadersCarrier(r.Header))
+		require.NoError(t, err, "pathway not found in the headers")
+		want, _ := datastreams.PathwayFromContext(produced)
+		assert.Equal(t, want.GetHash(), got.Hash)
+
+		// the pathway of the handler is the child of the pathway of the client
+		gotPathway, ok := datastreams.PathwayFromContext(r.Context())
+		require.True(t, ok, "pathway not found in the request context")
+		want, _ = datastreams.PathwayFromContext(consumed)
+		assert.Equal(t, want.GetHash(), gotPathway.GetHash())
+		w.Write([]byte("Hello World"))
+	})
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	client := WrapClient(&http.Client{}, WithDataStreams())
+	resp, err := client.Get(s.URL + "/hello/world")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
 type emptyRoundTripper struct{}
 
 func (rt *emptyRoundTripper) RoundTrip(_ *http.Request) (*http.Response, error) {
@@ -0,0 +1,39 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package websocket_test
+
+import (
+	"net/http"
+
+	httptrace "github.com/DataDog/dd-trace-go/contrib/net/http/v2"
+	websockettrace "github.com/DataDog/dd-trace-go/contrib/nhooyr.io/websocket/v2"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+)
+
+func Example() {
+	tracer.Start()
+	defer tracer.Stop()
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websockettrace.Accept(w, r, nil, websockettrace.WithMessageSpans(true))
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		for {
+			typ, p, err := conn.Read(r.Context())
+			if err != nil {
+				// The connection was closed, possibly by AppSec
+				return
+			}
+			if err := conn.Write(r.Context(), typ, p); err != nil {
+				return
+			}
+		}
+	})
+	http.ListenAndServe(":8080", mux)
+}
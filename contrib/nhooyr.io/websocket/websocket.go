@@ -0,0 +1,220 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package websocket provides functions to trace the nhooyr.io/websocket package (https://github.com/nhooyr/websocket).
+//
+// The connections accepted by Accept are traced from their upgrade to their
+// closing, and their inbound messages are monitored by AppSec until the
+// connection is closed, including after the return of the handler of their
+// upgrade request. The security events of the messages are reported in the
+// connection span. The connection is closed with the policy violation status
+// when AppSec blocks one of its messages.
+package websocket // import "github.com/DataDog/dd-trace-go/contrib/nhooyr.io/websocket/v2"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"nhooyr.io/websocket"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/websocketsec"
+)
+
+const (
+	receiveSpanName = "websocket.receive"
+	sendSpanName    = "websocket.send"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageNhooyrWebsocket)
+}
+
+// Accept calls websocket.Accept and returns the traced connection. The request context is used as the context of the
+// connection, whose span is started as a child of the request span and finished once the connection is closed by
+// Conn.Close or Conn.CloseNow.
+func Accept(w http.ResponseWriter, r *http.Request, opts *websocket.AcceptOptions, traceOpts ...Option) (*Conn, error) {
+	conn, err := websocket.Accept(w, r, opts)
+	if err != nil {
+		return nil, err
+	}
+	cfg := newConfig(traceOpts...)
+	spanOpts := []tracer.StartSpanOption{
+		tracer.ServiceName(cfg.serviceName),
+		tracer.ResourceName(r.URL.Path),
+		tracer.Tag(ext.Component, instrumentation.PackageNhooyrWebsocket),
+		tracer.Tag(ext.SpanKind, ext.SpanKindServer),
+		tracer.Tag(ext.HTTPURL, r.URL.String()),
+	}
+	if p := conn.Subprotocol(); p != "" {
+		spanOpts = append(spanOpts, tracer.Tag("websocket.subprotocol", p))
+	}
+	span, ctx := tracer.StartSpanFromContext(r.Context(), cfg.spanName, spanOpts...)
+	c := &Conn{Conn: conn, span: span, cfg: cfg}
+	if instr.AppSecEnabled() {
+		ctx, c.op = websocketsec.StartConnectionOperation(ctx, span)
+	}
+	c.ctx = ctx
+	return c, nil
+}
+
+// Conn wraps a websocket.Conn so that it is traced and its inbound messages are monitored by AppSec.
+// Conn.Close or Conn.CloseNow must be called to finish the connection span.
+type Conn struct {
+	*websocket.Conn
+	ctx        context.Context
+	span       *tracer.Span
+	op         *websocketsec.ConnectionOperation
+	cfg        *config
+	finishOnce sync.Once
+}
+
+// Read reads the next message of the connection, as websocket.Conn.Read does. When AppSec blocks the message, the
+// connection is closed and an *events.BlockingSecurityEvent is returned.
+func (c *Conn) Read(ctx context.Context) (websocket.MessageType, []byte, error) {
+	typ, p, err := c.Conn.Read(ctx)
+	if err != nil {
+		c.readError(err)
+		return typ, p, err
+	}
+	if err := c.received(typ, p); err != nil {
+		return typ, nil, err
+	}
+	return typ, p, nil
+}
+
+// Reader returns the reader of the next message of the connection, as websocket.Conn.Reader does. When AppSec is
+// enabled, the message is read entirely before being returned, so that it can be inspected. When AppSec blocks the
+// message, the connection is closed and an *events.BlockingSecurityEvent is returned.
+func (c *Conn) Reader(ctx context.Context) (websocket.MessageType, io.Reader, error) {
+	typ, r, err := c.Conn.Reader(ctx)
+	if err != nil {
+		c.readError(err)
+		return typ, r, err
+	}
+	if !instr.AppSecEnabled() {
+		return typ, r, c.received(typ, nil)
+	}
+	p, err := io.ReadAll(r)
+	if err != nil {
+		c.readError(err)
+		return typ, nil, err
+	}
+	if err := c.received(typ, p); err != nil {
+		return typ, nil, err
+	}
+	return typ, bytes.NewReader(p), nil
+}
+
+// Write writes a message to the connection, as websocket.Conn.Write does.
+func (c *Conn) Write(ctx context.Context, typ websocket.MessageType, p []byte) error {
+	span := c.startMessageSpan(sendSpanName, typ, len(p))
+	err := c.Conn.Write(ctx, typ, p)
+	finishMessageSpan(span, err)
+	return err
+}
+
+// Close closes the connection with the given status code and reason, as websocket.Conn.Close does, and finishes the
+// connection span.
+func (c *Conn) Close(code websocket.StatusCode, reason string) error {
+	err := c.Conn.Close(code, reason)
+	c.finish()
+	return err
+}
+
+// CloseNow closes the connection without a close handshake, as websocket.Conn.CloseNow does, and finishes the
+// connection span.
+func (c *Conn) CloseNow() error {
+	err := c.Conn.CloseNow()
+	c.finish()
+	return err
+}
+
+func (c *Conn) finish() {
+	c.finishOnce.Do(func() {
+		if c.op != nil {
+			c.op.Finish()
+		}
+		c.span.Finish()
+	})
+}
+
+// received traces the received message of the given type and monitors its payload with AppSec, when enabled. It
+// returns the *events.BlockingSecurityEvent of the message when blocked, after closing the connection.
+func (c *Conn) received(typ websocket.MessageType, p []byte) error {
+	size := len(p)
+	if p == nil {
+		size = -1
+	}
+	span := c.startMessageSpan(receiveSpanName, typ, size)
+	if !instr.AppSecEnabled() {
+		finishMessageSpan(span, nil)
+		return nil
+	}
+	err := websocketsec.ProtectMessage(c.ctx, p)
+	finishMessageSpan(span, err)
+	if err != nil {
+		if err := c.Close(websocket.StatusPolicyViolation, "blocked"); err != nil {
+			instr.Logger().Debug("contrib/nhooyr.io/websocket: failed to close the blocked connection: %v", err)
+		}
+	}
+	return err
+}
+
+// readError reports the given read error in the connection span, unless it is the normal closing of the connection.
+func (c *Conn) readError(err error) {
+	switch websocket.CloseStatus(err) {
+	case websocket.StatusNormalClosure, websocket.StatusGoingAway:
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	c.span.SetTag(ext.Error, err)
+}
+
+// startMessageSpan starts the span of a message of the given type and size, unless message spans are disabled. The
+// size is not tagged when negative.
+func (c *Conn) startMessageSpan(name string, typ websocket.MessageType, size int) *tracer.Span {
+	if !c.cfg.messageSpans {
+		return nil
+	}
+	opts := []tracer.StartSpanOption{
+		tracer.ServiceName(c.cfg.serviceName),
+		tracer.Tag(ext.Component, instrumentation.PackageNhooyrWebsocket),
+		tracer.Tag("websocket.message.type", messageTypeName(typ)),
+	}
+	if size >= 0 {
+		opts = append(opts, tracer.Tag("websocket.message.length", size))
+	}
+	span, _ := tracer.StartSpanFromContext(c.ctx, name, opts...)
+	return span
+}
+
+func finishMessageSpan(span *tracer.Span, err error) {
+	if span == nil {
+		return
+	}
+	span.Finish(tracer.WithError(err))
+}
+
+func messageTypeName(typ websocket.MessageType) string {
+	switch typ {
+	case websocket.MessageText:
+		return "text"
+	case websocket.MessageBinary:
+		return "binary"
+	default:
+		return "unknown"
+	}
+}
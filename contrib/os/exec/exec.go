@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package exec provides integrations into the standard library's `os/exec`
+// package, allowing protection against command and shell injection attacks.
+package exec
+
+// These imports satisfy injected dependencies for Orchestrion auto instrumentation.
+import (
+	"context"
+	"os/exec"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/ossec"
+	"github.com/DataDog/dd-trace-go/v2/internal/orchestrion"
+)
+
+var instr *instrumentation.Instrumentation
+
+func init() {
+	instr = instrumentation.Load(instrumentation.PackageOSExec)
+}
+
+// Cmd wraps an [exec.Cmd] and runs ASM command injection and shell injection
+// rules before the command is started. If an exploit is detected and blocking
+// is enabled, the command is not started and the returned error is an
+// [events.BlockingSecurityEvent].
+type Cmd struct {
+	*exec.Cmd
+	ctx context.Context
+}
+
+// Command is like [exec.Command]. The returned Cmd is only protected if it was
+// created with CommandContext, or if Orchestrion is used.
+func Command(name string, arg ...string) *Cmd {
+	return &Cmd{Cmd: exec.Command(name, arg...), ctx: context.Background()}
+}
+
+// CommandContext is a [context.Context]-aware version of [exec.CommandContext],
+// that allows the use of ASM rules to protect against command injection and
+// shell injection attacks. The context is used both to kill the process, as
+// done by exec.CommandContext, and to find the request being served.
+func CommandContext(ctx context.Context, name string, arg ...string) *Cmd {
+	return &Cmd{Cmd: exec.CommandContext(ctx, name, arg...), ctx: ctx}
+}
+
+// Start is like [exec.Cmd.Start].
+func (c *Cmd) Start() (err error) {
+	if c.protect(&err) {
+		return
+	}
+	return c.Cmd.Start()
+}
+
+// Run is like [exec.Cmd.Run].
+func (c *Cmd) Run() (err error) {
+	if c.protect(&err) {
+		return
+	}
+	return c.Cmd.Run()
+}
+
+// Output is like [exec.Cmd.Output].
+func (c *Cmd) Output() (out []byte, err error) {
+	if c.protect(&err) {
+		return
+	}
+	return c.Cmd.Output()
+}
+
+// CombinedOutput is like [exec.Cmd.CombinedOutput].
+func (c *Cmd) CombinedOutput() (out []byte, err error) {
+	if c.protect(&err) {
+		return
+	}
+	return c.Cmd.CombinedOutput()
+}
+
+// protect runs the exec operation for c, monitored by RASP and IAST, and
+// returns whether the command must be blocked, in which case *err holds the
+// blocking error.
+//
+// When built with Orchestrion, the command is protected by the
+// (*exec.Cmd).Start aspect instead, so that it is only checked once.
+func (c *Cmd) protect(err *error) bool {
+	if orchestrion.Enabled() {
+		return false
+	}
+	parent, _ := dyngo.FromContext(c.ctx)
+	if parent == nil {
+		return false
+	}
+	op := &ossec.ExecOperation{
+		Operation: dyngo.NewOperation(parent),
+	}
+
+	var block bool
+	dyngo.OnData(op, func(*events.BlockingSecurityEvent) {
+		block = true
+	})
+
+	dyngo.StartOperation(op, ossec.ExecOperationArgs{
+		Path: c.Path,
+		Args: c.Args,
+	})
+	dyngo.FinishOperation(op, ossec.ExecOperationRes{
+		Err: err,
+	})
+	return block
+}
@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package exec_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	wrapexec "github.com/DataDog/dd-trace-go/v2/contrib/os/exec"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	httptrace "github.com/DataDog/dd-trace-go/v2/instrumentation/httptracemock"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandContext(t *testing.T) {
+	for _, rules := range []struct {
+		name  string
+		path  string
+		block bool
+	}{
+		// the bundled ruleset only reports the exploits
+		{name: "bundled"},
+		{name: "blocking", path: "../../../internal/appsec/testdata/rasp.json", block: true},
+	} {
+		t.Run(rules.name, func(t *testing.T) {
+			t.Setenv(config.EnvEnabled, "true")
+			if rules.path != "" {
+				t.Setenv("DD_APPSEC_RULES", rules.path)
+			}
+			testutils.StartAppSec(t)
+			if !appsec.RASPEnabled() {
+				t.Skip("RASP needs to be enabled for this test")
+			}
+
+			mux := httptrace.NewServeMux()
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				cmd := wrapexec.CommandContext(r.Context(), "sh", "-c", "ls "+r.URL.Query().Get("file"))
+				if err := cmd.Run(); err != nil {
+					assert.ErrorIs(t, err, &events.BlockingSecurityEvent{})
+					assert.Nil(t, cmd.Process)
+					return
+				}
+				w.WriteHeader(204)
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			for _, tc := range []struct {
+				name    string
+				file    string
+				exploit bool
+			}{
+				{
+					name: "no-injection",
+					file: "/tmp",
+				},
+				{
+					name:    "shell-injection",
+					file:    "/tmp; cat /etc/passwd",
+					exploit: true,
+				},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					mt := mocktracer.Start()
+					defer mt.Stop()
+
+					res, err := srv.Client().Get(srv.URL + "?" + url.Values{"file": {tc.file}}.Encode())
+					require.NoError(t, err)
+					defer res.Body.Close()
+
+					spans := mt.FinishedSpans()
+					require.Len(t, spans, 1)
+					if !tc.exploit {
+						require.Equal(t, 204, res.StatusCode)
+						require.Nil(t, spans[0].Tag("_dd.appsec.json"))
+						return
+					}
+					if rules.block {
+						require.Equal(t, 403, res.StatusCode)
+					} else {
+						require.Equal(t, 204, res.StatusCode)
+					}
+					event, _ := spans[0].Tag("_dd.appsec.json").(string)
+					// the command is only checked once
+					require.Equal(t, 1, strings.Count(event, "rasp-932-100"))
+				})
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	// Commands created without a request context are not protected.
+	out, err := wrapexec.Command("echo", "hello").Output()
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", string(out))
+}
@@ -26,6 +26,7 @@ func init() {
 
 // OpenFile is a [context.Context]-aware version of [os.OpenFile], that allows
 // the use of ASM rules to protect against Local File Inclusion (LFI) attacks.
+// The opened path is also reported by IAST when it holds tainted request data.
 func OpenFile(ctx context.Context, path string, flag int, perm os.FileMode) (file *os.File, err error) {
 	parent, _ := dyngo.FromContext(ctx)
 	if parent != nil {
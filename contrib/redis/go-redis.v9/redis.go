@@ -17,6 +17,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -132,6 +133,10 @@ func (ddh *datadogHook) DialHook(hook redis.DialHook) redis.DialHook {
 
 func (ddh *datadogHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
 	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := protectCommands(ctx, cmd); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
 		raw := cmd.String()
 		length := strings.Count(raw, " ")
 		p := ddh.params
@@ -163,6 +168,12 @@ func (ddh *datadogHook) ProcessHook(hook redis.ProcessHook) redis.ProcessHook {
 
 func (ddh *datadogHook) ProcessPipelineHook(hook redis.ProcessPipelineHook) redis.ProcessPipelineHook {
 	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := protectCommands(ctx, cmds...); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
 		p := ddh.params
 		startOpts := make([]tracer.StartSpanOption, 0, 3+1+len(ddh.additionalTags)+1) // 3 options below + redis.raw_command + ddh.additionalTags + analyticsRate
 		startOpts = append(startOpts,
@@ -191,6 +202,21 @@ func (ddh *datadogHook) ProcessPipelineHook(hook redis.ProcessPipelineHook) redi
 	}
 }
 
+// protectCommands monitors the arguments of the given commands with AppSec to
+// protect against NoSQL injections, and returns an error when they must be
+// blocked.
+func protectCommands(ctx context.Context, cmds ...redis.Cmder) error {
+	if !instr.AppSecRASPEnabled() {
+		return nil
+	}
+	for _, cmd := range cmds {
+		if err := nosqlsec.ProtectRedisCommand(ctx, cmd.Args()...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // commandsToString returns a string representation of a slice of redis Commands, separated by newlines.
 func commandsToString(cmds []redis.Cmder) string {
 	var b bytes.Buffer
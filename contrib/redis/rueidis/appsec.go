@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package rueidis
+
+import (
+	"context"
+
+	"github.com/redis/rueidis"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
+)
+
+// closed is the done channel of the blocked contexts.
+var closed = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// blockedContext is the context of commands blocked by AppSec. rueidis does
+// not send the commands of a done context, and returns the context error as
+// their result instead.
+type blockedContext struct {
+	context.Context
+	err error
+}
+
+func (blockedContext) Done() <-chan struct{} {
+	return closed
+}
+
+func (ctx blockedContext) Err() error {
+	return ctx.err
+}
+
+// protect monitors the arguments of the given commands with AppSec to protect
+// against NoSQL injections. When they must be blocked, the returned context is
+// done with the blocking error, so that rueidis returns it instead of sending
+// the commands.
+func protect(ctx context.Context, cmds ...rueidis.Completed) (context.Context, bool) {
+	if !instr.AppSecRASPEnabled() {
+		return ctx, false
+	}
+	for _, cmd := range cmds {
+		args := make([]any, 0, len(cmd.Commands()))
+		for _, arg := range cmd.Commands() {
+			args = append(args, arg)
+		}
+		if err := nosqlsec.ProtectRedisCommand(ctx, args...); err != nil {
+			return blockedContext{Context: ctx, err: err}, true
+		}
+	}
+	return ctx, false
+}
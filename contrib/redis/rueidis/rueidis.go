@@ -120,6 +120,7 @@ func (c *client) B() rueidis.Builder {
 }
 
 func (c *client) Do(ctx context.Context, cmd rueidis.Completed) rueidis.RedisResult {
+	ctx, _ = protect(ctx, cmd)
 	span, ctx := c.startSpan(ctx, processCommand(&cmd))
 	resp := c.client.Do(ctx, cmd)
 	setClientCacheTags(span, resp)
@@ -128,6 +129,7 @@ func (c *client) Do(ctx context.Context, cmd rueidis.Completed) rueidis.RedisRes
 }
 
 func (c *client) DoMulti(ctx context.Context, multi ...rueidis.Completed) []rueidis.RedisResult {
+	ctx, _ = protect(ctx, multi...)
 	span, ctx := c.startSpan(ctx, processCommandMulti(multi))
 	resp := c.client.DoMulti(ctx, multi...)
 	c.finishSpan(span, c.firstError(resp))
@@ -135,6 +137,7 @@ func (c *client) DoMulti(ctx context.Context, multi ...rueidis.Completed) []ruei
 }
 
 func (c *client) Receive(ctx context.Context, subscribe rueidis.Completed, fn func(msg rueidis.PubSubMessage)) error {
+	ctx, _ = protect(ctx, subscribe)
 	span, ctx := c.startSpan(ctx, processCommand(&subscribe))
 	err := c.client.Receive(ctx, subscribe, fn)
 	c.finishSpan(span, err)
@@ -146,21 +149,40 @@ func (c *client) Close() {
 }
 
 func (c *client) DoCache(ctx context.Context, cmd rueidis.Cacheable, ttl time.Duration) rueidis.RedisResult {
+	ctx, blocked := protect(ctx, rueidis.Completed(cmd))
 	span, ctx := c.startSpan(ctx, processCommand(&cmd))
-	resp := c.client.DoCache(ctx, cmd, ttl)
+	var resp rueidis.RedisResult
+	if blocked {
+		// Blocked commands are not sent as cacheable ones, so that their cached
+		// result is not returned instead of the blocking error.
+		resp = c.client.Do(ctx, rueidis.Completed(cmd))
+	} else {
+		resp = c.client.DoCache(ctx, cmd, ttl)
+	}
 	setClientCacheTags(span, resp)
 	c.finishSpan(span, resp.Error())
 	return resp
 }
 
 func (c *client) DoMultiCache(ctx context.Context, multi ...rueidis.CacheableTTL) []rueidis.RedisResult {
+	cmds := make([]rueidis.Completed, len(multi))
+	for i, cmd := range multi {
+		cmds[i] = rueidis.Completed(cmd.Cmd)
+	}
+	ctx, blocked := protect(ctx, cmds...)
 	span, ctx := c.startSpan(ctx, processCommandMultiCache(multi))
-	resp := c.client.DoMultiCache(ctx, multi...)
+	var resp []rueidis.RedisResult
+	if blocked {
+		resp = c.client.DoMulti(ctx, cmds...)
+	} else {
+		resp = c.client.DoMultiCache(ctx, multi...)
+	}
 	c.finishSpan(span, c.firstError(resp))
 	return resp
 }
 
 func (c *client) DoStream(ctx context.Context, cmd rueidis.Completed) rueidis.RedisResultStream {
+	ctx, _ = protect(ctx, cmd)
 	span, ctx := c.startSpan(ctx, processCommand(&cmd))
 	resp := c.client.DoStream(ctx, cmd)
 	c.finishSpan(span, resp.Error())
@@ -168,6 +190,7 @@ func (c *client) DoStream(ctx context.Context, cmd rueidis.Completed) rueidis.Re
 }
 
 func (c *client) DoMultiStream(ctx context.Context, multi ...rueidis.Completed) rueidis.MultiRedisResultStream {
+	ctx, _ = protect(ctx, multi...)
 	span, ctx := c.startSpan(ctx, processCommandMulti(multi))
 	resp := c.client.DoMultiStream(ctx, multi...)
 	c.finishSpan(span, resp.Error())
@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
+)
+
+// StartConsumeOperation starts the AppSec monitoring of the given consumed message, whose consume span is given.
+// It returns nil when AppSec is disabled. The returned operation must be finished before the consume span.
+func StartConsumeOperation(ctx context.Context, span *tracer.Span, msg Message) *messagesec.ConsumeOperation {
+	if !instr.AppSecEnabled() {
+		return nil
+	}
+	headers := make(map[string][]string, len(msg.GetHeaders()))
+	for _, h := range msg.GetHeaders() {
+		headers[h.GetKey()] = append(headers[h.GetKey()], string(h.GetValue()))
+	}
+	_, op := messagesec.StartConsumeOperation(ctx, span, MessageKey(msg.GetTopic(), msg.GetPartition(), msg.GetOffset()), messagesec.ConsumeOperationArgs{
+		System:  messagesec.SystemKafka,
+		Topic:   msg.GetTopic(),
+		Headers: headers,
+		Key:     string(msg.GetKey()),
+		Value:   messagesec.DecodeValue(msg.GetValue()),
+	})
+	return op
+}
+
+// MessageKey returns the key identifying the consumed message of the given topic, partition and offset.
+func MessageKey(topic string, partition int, offset int64) messagesec.MessageKey {
+	return messagesec.MessageKey{
+		Topic:     topic,
+		Partition: int32(partition),
+		Offset:    offset,
+	}
+}
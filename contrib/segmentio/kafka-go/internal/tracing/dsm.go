@@ -65,6 +65,28 @@ func (tr *Tracer) SetProduceDSMCheckpoint(msg Message, writer Writer) {
 	datastreams.InjectToBase64Carrier(ctx, carrier)
 }
 
+// TrackHighWatermarkOffset tracks the high watermark offset of the partition of a consumed message, to compute the
+// consumer lag.
+func (tr *Tracer) TrackHighWatermarkOffset(msg Message, highWatermark int64) {
+	if !tr.dataStreamsEnabled || msg == nil {
+		return
+	}
+	tracer.TrackKafkaHighWatermarkOffset("", msg.GetTopic(), int32(msg.GetPartition()), highWatermark)
+}
+
+// TrackProduceOffset tracks the offset of a message once it is produced.
+func (tr *Tracer) TrackProduceOffset(msg Message) {
+	if !tr.dataStreamsEnabled || msg == nil {
+		return
+	}
+	tracer.TrackKafkaProduceOffset(msg.GetTopic(), int32(msg.GetPartition()), msg.GetOffset())
+}
+
+// DataStreamsEnabled returns whether Data Streams Monitoring is enabled for the integration.
+func (tr *Tracer) DataStreamsEnabled() bool {
+	return tr.dataStreamsEnabled
+}
+
 func getProducerMsgSize(msg Message) (size int64) {
 	for _, header := range msg.GetHeaders() {
 		size += int64(len(header.GetKey()) + len(header.GetValue()))
@@ -84,3 +106,11 @@ func getConsumerMsgSize(msg Message) (size int64) {
 	}
 	return size + int64(len(msg.GetValue())+len(msg.GetKey()))
 }
+
+// TrackSchema tracks the usage of a schema to serialize or deserialize a message value of the given topic.
+func (tr *Tracer) TrackSchema(topic string, operation options.SchemaOperation, schema options.Schema) {
+	if !tr.dataStreamsEnabled {
+		return
+	}
+	tracer.TrackDataStreamsSchema(topic, operation, schema)
+}
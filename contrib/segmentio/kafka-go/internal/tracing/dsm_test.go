@@ -0,0 +1,41 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracing
+
+import (
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMessage struct {
+	topic     string
+	partition int
+	offset    int64
+}
+
+func (m *testMessage) GetValue() []byte     { return nil }
+func (m *testMessage) GetKey() []byte       { return nil }
+func (m *testMessage) GetHeaders() []Header { return nil }
+func (m *testMessage) SetHeaders([]Header)  {}
+func (m *testMessage) GetTopic() string     { return m.topic }
+func (m *testMessage) GetPartition() int    { return m.partition }
+func (m *testMessage) GetOffset() int64     { return m.offset }
+
+func TestTrackOffsets(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := NewTracer(KafkaConfig{ConsumerGroupID: "group"}, WithDataStreams())
+	tr.TrackProduceOffset(&testMessage{topic: "topic", partition: 1, offset: 10})
+	tr.TrackHighWatermarkOffset(&testMessage{topic: "topic", partition: 1, offset: 4}, 11)
+
+	backlogs := mt.SentDSMBacklogs()
+	assert.Contains(t, backlogs, mocktracer.DSMBacklog{Tags: []string{"partition:1", "topic:topic", "type:kafka_produce"}, Value: 10})
+	assert.Contains(t, backlogs, mocktracer.DSMBacklog{Tags: []string{"partition:1", "topic:topic", "type:kafka_high_watermark"}, Value: 11})
+}
@@ -10,8 +10,10 @@ import (
 	"strings"
 
 	"github.com/DataDog/dd-trace-go/contrib/segmentio/kafka-go/v2/internal/tracing"
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	_ "github.com/DataDog/dd-trace-go/v2/instrumentation" // Blank import to pass TestIntegrationEnabled test
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -21,6 +23,7 @@ type Reader struct {
 	*kafka.Reader
 	tracer *tracing.Tracer
 	prev   *tracer.Span
+	prevOp *messagesec.ConsumeOperation
 }
 
 // NewReader calls kafka.NewReader and wraps the resulting Consumer.
@@ -49,57 +52,83 @@ func WrapReader(c *kafka.Reader, opts ...Option) *Reader {
 // any remaining span.
 func (r *Reader) Close() error {
 	err := r.Reader.Close()
+	r.finishPrev()
+	return err
+}
+
+// finishPrev finishes the span, and its AppSec operation, of the previously consumed message, if any.
+func (r *Reader) finishPrev() {
+	if r.prevOp != nil {
+		r.prevOp.Finish()
+		r.prevOp = nil
+	}
 	if r.prev != nil {
 		r.prev.Finish()
 		r.prev = nil
 	}
-	return err
 }
 
 // ReadMessage polls the consumer for a message. Message will be traced.
 func (r *Reader) ReadMessage(ctx context.Context) (kafka.Message, error) {
-	if r.prev != nil {
-		r.prev.Finish()
-		r.prev = nil
-	}
+	r.finishPrev()
 	msg, err := r.Reader.ReadMessage(ctx)
 	if err != nil {
 		return kafka.Message{}, err
 	}
 	tMsg := wrapMessage(&msg)
 	r.prev = r.tracer.StartConsumeSpan(ctx, tMsg)
+	r.prevOp = tracing.StartConsumeOperation(ctx, r.prev, tMsg)
 	r.tracer.SetConsumeDSMCheckpoint(tMsg)
+	r.tracer.TrackHighWatermarkOffset(tMsg, msg.HighWaterMark)
 	return msg, nil
 }
 
 // FetchMessage reads and returns the next message from the reader. Message will be traced.
 func (r *Reader) FetchMessage(ctx context.Context) (kafka.Message, error) {
-	if r.prev != nil {
-		r.prev.Finish()
-		r.prev = nil
-	}
+	r.finishPrev()
 	msg, err := r.Reader.FetchMessage(ctx)
 	if err != nil {
 		return msg, err
 	}
 	tMsg := wrapMessage(&msg)
 	r.prev = r.tracer.StartConsumeSpan(ctx, tMsg)
+	r.prevOp = tracing.StartConsumeOperation(ctx, r.prev, tMsg)
 	r.tracer.SetConsumeDSMCheckpoint(tMsg)
+	r.tracer.TrackHighWatermarkOffset(tMsg, msg.HighWaterMark)
 	return msg, nil
 }
 
+// ContextWithMessage returns a copy of ctx holding the AppSec monitoring of the given message consumed by a Reader, so
+// that the AppSec protections, such as the RASP ones, apply to its processing until the next message is consumed.
+// ctx is returned as is when AppSec is disabled or the message is not being consumed.
+func ContextWithMessage(ctx context.Context, msg kafka.Message) context.Context {
+	return messagesec.ContextWithMessage(ctx, tracing.MessageKey(msg.Topic, msg.Partition, msg.Offset))
+}
+
+// TrackSchema tracks the usage of the given schema, such as an Avro or Protobuf schema of a schema registry, to
+// deserialize the value of msg, when Data Streams Monitoring is enabled.
+func (r *Reader) TrackSchema(msg kafka.Message, schema options.Schema) {
+	r.tracer.TrackSchema(msg.Topic, options.SchemaOperationDeserialization, schema)
+}
+
 // Writer wraps a kafka.Writer with tracing config data
 type KafkaWriter struct {
 	*kafka.Writer
 	tracer *tracing.Tracer
 }
 
-// NewWriter calls kafka.NewWriter and wraps the resulting Producer.
+// NewWriter calls kafka.NewWriter and wraps the resulting Producer. When Data Streams Monitoring is enabled, the
+// offsets of the produced messages are tracked by the Completion callback of the created kafka.Writer.
 func NewWriter(conf kafka.WriterConfig, opts ...Option) *KafkaWriter {
-	return WrapWriter(kafka.NewWriter(conf), opts...)
+	w := kafka.NewWriter(conf)
+	writer := WrapWriter(w, opts...)
+	w.Completion = writer.WrapCompletion(nil)
+	return writer
 }
 
-// WrapWriter wraps a kafka.Writer so requests are traced.
+// WrapWriter wraps a kafka.Writer so requests are traced. w is not modified: the offsets of the produced messages,
+// which are only known by the Completion callback of w, are tracked for Data Streams Monitoring when this callback is
+// wrapped with KafkaWriter.WrapCompletion.
 func WrapWriter(w *kafka.Writer, opts ...Option) *KafkaWriter {
 	writer := &KafkaWriter{
 		Writer: w,
@@ -113,6 +142,28 @@ func WrapWriter(w *kafka.Writer, opts ...Option) *KafkaWriter {
 	return writer
 }
 
+// WrapCompletion returns a Completion callback for the wrapped kafka.Writer which tracks the offsets of the produced
+// messages when Data Streams Monitoring is enabled, including for the synchronous writes, and then calls completion,
+// if not nil. completion is returned as is when Data Streams Monitoring is disabled.
+//
+//	writer := kafkatrace.WrapWriter(w, kafkatrace.WithDataStreams())
+//	w.Completion = writer.WrapCompletion(w.Completion)
+func (w *KafkaWriter) WrapCompletion(completion func(messages []kafka.Message, err error)) func(messages []kafka.Message, err error) {
+	if !w.tracer.DataStreamsEnabled() {
+		return completion
+	}
+	return func(messages []kafka.Message, err error) {
+		if err == nil {
+			for i := range messages {
+				w.tracer.TrackProduceOffset(wrapMessage(&messages[i]))
+			}
+		}
+		if completion != nil {
+			completion(messages, err)
+		}
+	}
+}
+
 // WriteMessages calls kafka-go.Writer.WriteMessages and traces the requests.
 func (w *KafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
 	// although there's only one call made to the SyncProducer, the messages are
@@ -130,3 +181,13 @@ func (w *KafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message)
 	}
 	return err
 }
+
+// TrackSchema tracks the usage of the given schema, such as an Avro or Protobuf schema of a schema registry, to
+// serialize the value of msg, when Data Streams Monitoring is enabled.
+func (w *KafkaWriter) TrackSchema(msg kafka.Message, schema options.Schema) {
+	topic := msg.Topic
+	if w.Writer.Topic != "" {
+		topic = w.Writer.Topic
+	}
+	w.tracer.TrackSchema(topic, options.SchemaOperationSerialization, schema)
+}
@@ -458,3 +458,26 @@ func BenchmarkWriterStartSpan(b *testing.B) {
 	}
 	benchSpan = result
 }
+
+func TestWrapCompletion(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	kw := &kafka.Writer{Addr: kafka.TCP("localhost:9092")}
+	w := WrapWriter(kw, WithDataStreams())
+	// the wrapped writer is not modified
+	assert.Nil(t, kw.Completion)
+
+	var completed []kafka.Message
+	completion := w.WrapCompletion(func(messages []kafka.Message, _ error) {
+		completed = append(completed, messages...)
+	})
+	messages := []kafka.Message{{Topic: "topic", Partition: 1, Offset: 10}}
+	completion(messages, nil)
+	assert.Equal(t, messages, completed)
+	assert.Contains(t, mt.SentDSMBacklogs(), mocktracer.DSMBacklog{Tags: []string{"partition:1", "topic:topic", "type:kafka_produce"}, Value: 10})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, WrapWriter(kw).WrapCompletion(nil))
+	})
+}
@@ -0,0 +1,51 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package fasthttp
+
+import (
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/httpsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/fasthttpsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/trace"
+)
+
+// withAppSec runs the given handler monitored by AppSec. The handler is not
+// called when the request is blocked, and its response is replaced when the
+// request gets blocked while it runs.
+func withAppSec(fctx *fasthttp.RequestCtx, span trace.TagSetter, h fasthttp.RequestHandler) {
+	op, blockAtomic, _ := httpsec.StartOperation(fctx, httpsec.HandlerOperationArgs{
+		Framework:   "github.com/valyala/fasthttp",
+		Method:      string(fctx.Method()),
+		RequestURI:  string(fctx.RequestURI()),
+		Host:        string(fctx.Host()),
+		RemoteAddr:  fctx.RemoteAddr().String(),
+		Headers:     fasthttpsec.MakeHeaders(&fctx.Request.Header),
+		Cookies:     fasthttpsec.MakeCookies(&fctx.Request.Header),
+		QueryParams: fasthttpsec.MakeQueryParams(fctx.QueryArgs()),
+	}, span)
+	// fasthttp handlers use their RequestCtx as context.Context
+	defer fasthttpsec.RegisterOperation(fctx, op)()
+
+	if blockAtomic.Load() == nil {
+		op.MonitorRequestBody(string(fctx.Request.Header.ContentType()), fctx.PostBody())
+	}
+	if blockAtomic.Load() == nil {
+		h(fctx)
+	}
+
+	op.Finish(httpsec.HandlerOperationRes{
+		Headers:    fasthttpsec.MakeHeaders(&fctx.Response.Header),
+		StatusCode: fctx.Response.StatusCode(),
+		Body:       op.ParseResponseBody(string(fctx.Response.Header.ContentType()), fctx.Response.Body()),
+	})
+
+	if blockPtr := blockAtomic.Load(); blockPtr != nil && blockPtr.Handler != nil {
+		fctx.Response.Reset()
+		fasthttpadaptor.NewFastHTTPHandler(blockPtr.Handler)(fctx)
+	}
+}
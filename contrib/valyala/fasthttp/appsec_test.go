@@ -0,0 +1,219 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package fasthttp
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+)
+
+// startAppSecServer starts a fasthttp server serving the given handler wrapped
+// with WrapHandler and returns its address.
+func startAppSecServer(t *testing.T, h fasthttp.RequestHandler) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fasthttp.Server{
+		Handler: WrapHandler(h),
+	}
+	go server.Serve(ln)
+	t.Cleanup(func() {
+		assert.NoError(t, server.Shutdown())
+	})
+	return "http://" + ln.Addr().String()
+}
+
+func appSecHandler(fctx *fasthttp.RequestCtx) {
+	switch string(fctx.Path()) {
+	case "/user":
+		if err := appsec.SetUser(fctx, string(fctx.Request.Header.Peek("test-usr"))); err != nil {
+			return
+		}
+	case "/body":
+		if err := appsec.MonitorParsedHTTPBody(fctx, string(fctx.PostBody())); err != nil {
+			return
+		}
+	}
+	fctx.SetStatusCode(200)
+	fctx.WriteString("Hello World!\n")
+}
+
+func TestAppSec(t *testing.T) {
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("appsec disabled")
+	}
+	addr := startAppSecServer(t, appSecHandler)
+
+	t.Run("request-uri", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		// Send an LFI attack (according to appsec rule id crs-930-110)
+		req, err := http.NewRequest("POST", addr+"/lfi/../../../secret.txt", nil)
+		require.NoError(t, err)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		finished := mt.FinishedSpans()
+		require.Len(t, finished, 1)
+		event, _ := finished[0].Tag("_dd.appsec.json").(string)
+		require.Contains(t, event, "server.request.uri.raw")
+		require.Contains(t, event, "crs-930-110")
+	})
+
+	t.Run("SDK-body", func(t *testing.T) {
+		mt := mocktracer.Start()
+		defer mt.Stop()
+		req, err := http.NewRequest("POST", addr+"/body", strings.NewReader("$globals"))
+		require.NoError(t, err)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		finished := mt.FinishedSpans()
+		require.Len(t, finished, 1)
+		event, _ := finished[0].Tag("_dd.appsec.json").(string)
+		require.Contains(t, event, "server.request.body")
+	})
+}
+
+func TestBlocking(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "../../../internal/appsec/testdata/blocking.json")
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+	addr := startAppSecServer(t, appSecHandler)
+
+	for _, tc := range []struct {
+		name    string
+		path    string
+		headers map[string]string
+		status  int
+	}{
+		{
+			name:   "no-block",
+			path:   "/",
+			status: 200,
+		},
+		{
+			name:    "ip/block",
+			path:    "/",
+			headers: map[string]string{"x-forwarded-for": "1.2.3.4"},
+			status:  403,
+		},
+		{
+			name:    "ip/no-block",
+			path:    "/",
+			headers: map[string]string{"x-forwarded-for": "1.2.3.5"},
+			status:  200,
+		},
+		{
+			name:    "user/block",
+			path:    "/user",
+			headers: map[string]string{"test-usr": "blocked-user-1"},
+			status:  403,
+		},
+		{
+			name:    "user/no-block",
+			path:    "/user",
+			headers: map[string]string{"test-usr": "legit-user"},
+			status:  200,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			req, err := http.NewRequest("POST", addr+tc.path, nil)
+			require.NoError(t, err)
+			req.Header.Set("Accept", "application/json")
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			b, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.status, res.StatusCode)
+			if tc.status == 200 {
+				require.Equal(t, "Hello World!\n", string(b))
+				return
+			}
+			require.Equal(t, "application/json", res.Header.Get("Content-Type"))
+			require.Contains(t, string(b), "You've been blocked")
+
+			finished := mt.FinishedSpans()
+			require.Len(t, finished, 1)
+			require.Equal(t, "true", finished[0].Tag("appsec.blocked"))
+			require.Equal(t, "403", finished[0].Tag("http.status_code"))
+		})
+	}
+}
+
+func TestRedirect(t *testing.T) {
+	rules := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(rules, []byte(redirectRules), 0o644))
+	t.Setenv("DD_APPSEC_RULES", rules)
+	testutils.StartAppSec(t)
+	if !instr.AppSecEnabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+	addr := startAppSecServer(t, appSecHandler)
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := client.Get(addr + "/?redirect=please")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusSeeOther, res.StatusCode)
+	require.Equal(t, "/redirected", res.Header.Get("Location"))
+}
+
+const redirectRules = `{
+  "version": "2.2",
+  "metadata": {"rules_version": "1.4.2"},
+  "rules": [
+    {
+      "id": "redirect-001",
+      "name": "Redirect requests",
+      "tags": {"type": "security_scanner", "category": "attack_attempt"},
+      "conditions": [
+        {
+          "parameters": {"inputs": [{"address": "server.request.query"}], "regex": "please"},
+          "operator": "match_regex"
+        }
+      ],
+      "on_match": ["redirect"]
+    }
+  ],
+  "actions": [
+    {
+      "id": "redirect",
+      "type": "redirect_request",
+      "parameters": {"status_code": 303, "location": "/redirected"}
+    }
+  ]
+}`
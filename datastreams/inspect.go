@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/datastreams"
+)
+
+// ErrPathwayNotFound is returned by [InspectBase64Carrier] when the carrier holds no pathway.
+var ErrPathwayNotFound = errors.New("datastreams: no pathway found in the carrier")
+
+// PathwayInfo is the decoded content of a propagated pathway, meant to debug why the pathways of an integration
+// do not match across services.
+type PathwayInfo struct {
+	// Hash is the hash of the pathway, matching the hash of the stats point of its last checkpoint.
+	Hash uint64
+	// PathwayStart is the time of the first checkpoint of the pathway.
+	PathwayStart time.Time
+	// EdgeStart is the time of the last checkpoint of the pathway.
+	EdgeStart time.Time
+}
+
+// DecodeBase64Pathway decodes a pathway propagated with the base64 encoding, such as the value of the
+// dd-pathway-ctx-base64 header.
+func DecodeBase64Pathway(encoded string) (PathwayInfo, error) {
+	p, _, err := datastreams.DecodeBase64(context.Background(), encoded)
+	if err != nil {
+		return PathwayInfo{}, err
+	}
+	return PathwayInfo{Hash: p.GetHash(), PathwayStart: p.PathwayStart(), EdgeStart: p.EdgeStart()}, nil
+}
+
+// InspectBase64Carrier decodes the pathway propagated in carrier by [InjectToBase64Carrier], or returns
+// [ErrPathwayNotFound] when there is none.
+func InspectBase64Carrier(carrier TextMapReader) (info PathwayInfo, err error) {
+	err = ErrPathwayNotFound
+	carrier.ForeachKey(func(key, val string) error {
+		if strings.EqualFold(key, datastreams.PropagationKeyBase64) {
+			info, err = DecodeBase64Pathway(val)
+		}
+		return nil
+	})
+	return info, err
+}
@@ -9,3 +9,25 @@ type CheckpointParams struct {
 	PayloadSize     int64
 	ServiceOverride string
 }
+
+// SchemaOperation is the operation a schema is used for.
+type SchemaOperation string
+
+const (
+	// SchemaOperationSerialization is the serialization of a payload, before producing it.
+	SchemaOperationSerialization SchemaOperation = "serialization"
+	// SchemaOperationDeserialization is the deserialization of a payload, after consuming it.
+	SchemaOperationDeserialization SchemaOperation = "deserialization"
+)
+
+// Schema identifies the schema of a payload, such as an Avro or Protobuf schema of a schema registry.
+type Schema struct {
+	// ID is the schema identity, such as its schema registry ID or its fingerprint.
+	ID string
+	// Name is the schema name, such as the Avro record name or the Protobuf message name.
+	Name string
+	// Type is the schema type, such as "avro" or "protobuf".
+	Type string
+	// Definition is the optional schema definition, only reported when the schema usage is sampled.
+	Definition string
+}
@@ -7,6 +7,7 @@ package datastreams
 
 import (
 	"context"
+	"strings"
 
 	"github.com/DataDog/dd-trace-go/v2/internal/datastreams"
 )
@@ -53,7 +54,8 @@ type TextMapReader interface {
 func ExtractFromBase64Carrier(ctx context.Context, carrier TextMapReader) (outCtx context.Context) {
 	outCtx = ctx
 	carrier.ForeachKey(func(key, val string) error {
-		if key == datastreams.PropagationKeyBase64 {
+		// the keys are case insensitive, as for HTTP headers, which are canonicalized
+		if strings.EqualFold(key, datastreams.PropagationKeyBase64) {
 			_, outCtx, _ = datastreams.DecodeBase64(ctx, val)
 		}
 		return nil
@@ -7,6 +7,7 @@ package datastreams
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
@@ -43,3 +44,36 @@ func TestBase64Propagation(t *testing.T) {
 	assert.Equal(t, expected.GetHash(), got.GetHash())
 	assert.NotEqual(t, 0, expected.GetHash())
 }
+
+func TestBase64PropagationHTTPHeaders(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+	c := tracer.HTTPHeadersCarrier(http.Header{})
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "type:http")
+	InjectToBase64Carrier(ctx, c)
+	got, ok := datastreams.PathwayFromContext(ExtractFromBase64Carrier(context.Background(), c))
+	assert.True(t, ok, "the canonicalized header should be extracted")
+	expected, _ := datastreams.PathwayFromContext(ctx)
+	assert.Equal(t, expected.GetHash(), got.GetHash())
+}
+
+func TestInspectBase64Carrier(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	_, err := InspectBase64Carrier(make(carrier))
+	assert.ErrorIs(t, err, ErrPathwayNotFound)
+
+	c := make(carrier)
+	ctx, _ := tracer.SetDataStreamsCheckpoint(context.Background(), "direction:out", "type:kafka", "topic:topic1")
+	InjectToBase64Carrier(ctx, c)
+	info, err := InspectBase64Carrier(c)
+	assert.NoError(t, err)
+	expected, _ := datastreams.PathwayFromContext(ctx)
+	assert.Equal(t, expected.GetHash(), info.Hash)
+	assert.Equal(t, expected.PathwayStart().UnixMilli(), info.PathwayStart.UnixMilli())
+	assert.Equal(t, expected.EdgeStart().UnixMilli(), info.EdgeStart.UnixMilli())
+
+	_, err = DecodeBase64Pathway("not a pathway")
+	assert.Error(t, err)
+}
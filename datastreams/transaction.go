@@ -0,0 +1,28 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"context"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
+	"github.com/DataDog/dd-trace-go/v2/internal/datastreams"
+)
+
+// TrackTransaction tracks a checkpoint of the business transaction identified by transactionID, such as an order ID,
+// so that individual transactions can be followed across services that do not share a trace context, e.g. through
+// batch files. checkpointName names the step of the transaction, such as "order-received" or "order-shipped". The
+// pathway of ctx, if any, is recorded along with the checkpoint.
+// To learn more about the data streams product, see: https://docs.datadoghq.com/data_streams/go/
+func TrackTransaction(ctx context.Context, transactionID, checkpointName string) {
+	if t, ok := tracer.GetGlobalTracer().(interface {
+		GetDataStreamsProcessor() *datastreams.Processor
+	}); ok {
+		if p := t.GetDataStreamsProcessor(); p != nil {
+			p.TrackTransaction(ctx, transactionID, checkpointName)
+		}
+	}
+}
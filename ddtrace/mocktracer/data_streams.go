@@ -16,6 +16,7 @@ import (
 
 type mockDSMTransport struct {
 	backlogs []datastreams.Backlog
+	schemas  []datastreams.SchemaUsage
 }
 
 // RoundTrip does nothing and returns a dummy response.
@@ -32,6 +33,7 @@ func (t *mockDSMTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 	for _, bucket := range p.Stats {
 		t.backlogs = append(t.backlogs, bucket.Backlogs...)
+		t.schemas = append(t.schemas, bucket.Schemas...)
 	}
 	return &http.Response{
 		StatusCode:    200,
@@ -29,6 +29,9 @@ var _ Tracer = (*mocktracer)(nil)
 // DSMBacklog is an alias to datastreams.Backlog
 type DSMBacklog = datastreams.Backlog
 
+// DSMSchemaUsage is an alias to datastreams.SchemaUsage
+type DSMSchemaUsage = datastreams.SchemaUsage
+
 // Tracer exposes an interface for querying the currently running mock tracer.
 type Tracer interface {
 	tracer.Tracer
@@ -42,6 +45,9 @@ type Tracer interface {
 
 	SentDSMBacklogs() []DSMBacklog
 
+	// SentDSMSchemas returns the schema usages sent by Data Streams Monitoring.
+	SentDSMSchemas() []DSMSchemaUsage
+
 	// Reset resets the spans and services recorded in the tracer. This is
 	// especially useful when running tests in a loop, where a clean start
 	// is desired for FinishedSpans calls.
@@ -79,6 +85,11 @@ func (t *mocktracer) SentDSMBacklogs() []DSMBacklog {
 	return t.dsmTransport.backlogs
 }
 
+func (t *mocktracer) SentDSMSchemas() []DSMSchemaUsage {
+	t.dsmProcessor.Flush()
+	return t.dsmTransport.schemas
+}
+
 func newMockTracer() *mocktracer {
 	var t mocktracer
 	t.openSpans = make(map[uint64]*Span)
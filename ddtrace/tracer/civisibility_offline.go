@@ -0,0 +1,331 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/constants"
+	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// Ensure that ciVisibilityOfflineTransport implements the transport interface.
+var _ transport = (*ciVisibilityOfflineTransport)(nil)
+
+// ciVisibilityOfflineTransport is a transport writing the CI Visibility payloads to files of the offline directory
+// instead of sending them, so that they can be uploaded later to the test cycle intake as they are.
+type ciVisibilityOfflineTransport struct {
+	config *config       // Configuration for the tracer.
+	dir    string        // Directory where the payload files are written.
+	count  atomic.Uint64 // Number of payload files written, used to name them.
+}
+
+// newCiVisibilityOfflineTransport creates a new ciVisibilityOfflineTransport writing to the given directory.
+//
+// Parameters:
+//
+//	config - The tracer configuration.
+//	dir - The offline directory.
+//
+// Returns:
+//
+//	A pointer to an initialized ciVisibilityOfflineTransport.
+func newCiVisibilityOfflineTransport(config *config, dir string) *ciVisibilityOfflineTransport {
+	log.Debug("ciVisibilityOfflineTransport: creating transport instance [dir: %v]", dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Error("ciVisibilityOfflineTransport: cannot create the offline directory: %v", err)
+	}
+	return &ciVisibilityOfflineTransport{
+		config: config,
+		dir:    dir,
+	}
+}
+
+// send writes the payload to a new file of the offline directory, with the body that would have been sent to the
+// test cycle intake.
+func (t *ciVisibilityOfflineTransport) send(p *payload) (body io.ReadCloser, err error) {
+	ciVisibilityPayload := &ciVisibilityPayload{p, 0}
+	buffer, err := ciVisibilityPayload.getBuffer(t.config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create buffer payload: %v", err)
+	}
+
+	name := fmt.Sprintf("citestcycle-%s-%d.msgpack", globalconfig.RuntimeID(), t.count.Add(1))
+	log.Debug("ciVisibilityOfflineTransport: writing payload file %s: %v bytes", name, buffer.Len())
+	if err := os.WriteFile(filepath.Join(t.dir, name), buffer.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("cannot write payload file: %v", err)
+	}
+	return io.NopCloser(nil), nil
+}
+
+// sendStats does nothing, as stats are not supported by CI Visibility.
+func (t *ciVisibilityOfflineTransport) sendStats(*pb.ClientStatsPayload, int) error {
+	return nil
+}
+
+// endpoint returns the offline directory.
+func (t *ciVisibilityOfflineTransport) endpoint() string {
+	return "file://" + t.dir
+}
+
+// junitEvent holds the data of a test session, module, suite or test event that is reported in the JUnit XML reports.
+type junitEvent struct {
+	sessionID uint64
+	moduleID  uint64
+	suiteID   uint64
+	start     int64
+	duration  int64
+	meta      map[string]string
+	metrics   map[string]float64
+}
+
+// junitReport collects the test events of the tracer to write them as JUnit XML reports, one per test module, in
+// the offline directory.
+type junitReport struct {
+	mu       sync.Mutex
+	dir      string
+	sessions map[uint64]*junitEvent
+	modules  map[uint64]*junitEvent
+	suites   map[uint64]*junitEvent
+	tests    []*junitEvent
+}
+
+func newJUnitReport(dir string) *junitReport {
+	return &junitReport{
+		dir:      dir,
+		sessions: make(map[uint64]*junitEvent),
+		modules:  make(map[uint64]*junitEvent),
+		suites:   make(map[uint64]*junitEvent),
+	}
+}
+
+// add collects the given event if it's a test session, module, suite or test.
+func (r *junitReport) add(event *ciVisibilityEvent) {
+	e := &junitEvent{
+		sessionID: event.Content.SessionID,
+		moduleID:  event.Content.ModuleID,
+		suiteID:   event.Content.SuiteID,
+		start:     event.Content.Start,
+		duration:  event.Content.Duration,
+		meta:      maps.Clone(event.Content.Meta),
+		metrics:   maps.Clone(event.Content.Metrics),
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch event.Type {
+	case constants.SpanTypeTestSession:
+		r.sessions[e.sessionID] = e
+	case constants.SpanTypeTestModule:
+		r.modules[e.moduleID] = e
+	case constants.SpanTypeTestSuite:
+		r.suites[e.suiteID] = e
+	case constants.SpanTypeTest:
+		r.tests = append(r.tests, e)
+	}
+}
+
+type (
+	// junitTestSuites is the root element of a JUnit XML report, holding a test module and its session.
+	junitTestSuites struct {
+		XMLName    xml.Name          `xml:"testsuites"`
+		Name       string            `xml:"name,attr"`
+		Tests      int               `xml:"tests,attr"`
+		Failures   int               `xml:"failures,attr"`
+		Skipped    int               `xml:"skipped,attr"`
+		Time       string            `xml:"time,attr,omitempty"`
+		Timestamp  string            `xml:"timestamp,attr,omitempty"`
+		Properties *junitProperties  `xml:"properties,omitempty"`
+		Suites     []*junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		XMLName    xml.Name         `xml:"testsuite"`
+		Name       string           `xml:"name,attr"`
+		Tests      int              `xml:"tests,attr"`
+		Failures   int              `xml:"failures,attr"`
+		Skipped    int              `xml:"skipped,attr"`
+		Time       string           `xml:"time,attr,omitempty"`
+		Timestamp  string           `xml:"timestamp,attr,omitempty"`
+		Properties *junitProperties `xml:"properties,omitempty"`
+		TestCases  []*junitTestCase `xml:"testcase"`
+
+		start int64
+	}
+
+	junitTestCase struct {
+		XMLName    xml.Name         `xml:"testcase"`
+		Name       string           `xml:"name,attr"`
+		ClassName  string           `xml:"classname,attr"`
+		Time       string           `xml:"time,attr"`
+		Properties *junitProperties `xml:"properties,omitempty"`
+		Failure    *junitFailure    `xml:"failure,omitempty"`
+		Skipped    *junitSkipped    `xml:"skipped,omitempty"`
+	}
+
+	junitProperties struct {
+		Properties []junitProperty `xml:"property"`
+	}
+
+	junitProperty struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value,attr"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr,omitempty"`
+		Type    string `xml:"type,attr,omitempty"`
+		Stack   string `xml:",chardata"`
+	}
+
+	junitSkipped struct {
+		Message string `xml:"message,attr,omitempty"`
+	}
+)
+
+// write writes a JUnit XML report for every test module of the collected events.
+func (r *junitReport) write() {
+	for moduleID, report := range r.build() {
+		data, err := xml.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Error("ciVisibilityTraceWriter: cannot encode the JUnit report: %v", err)
+			continue
+		}
+		name := filepath.Join(r.dir, fmt.Sprintf("junit-%d.xml", moduleID))
+		if err := os.WriteFile(name, append([]byte(xml.Header), data...), 0o644); err != nil {
+			log.Error("ciVisibilityTraceWriter: cannot write the JUnit report: %v", err)
+		}
+	}
+}
+
+// build returns the JUnit XML report of every test module of the collected events, by module ID.
+func (r *junitReport) build() map[uint64]*junitTestSuites {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reports := make(map[uint64]*junitTestSuites)
+	getReport := func(moduleID, sessionID uint64) *junitTestSuites {
+		if report, ok := reports[moduleID]; ok {
+			return report
+		}
+		report := &junitTestSuites{}
+		properties := make(map[string]string)
+		if session, ok := r.sessions[sessionID]; ok {
+			addJUnitProperties(properties, session)
+		}
+		if module, ok := r.modules[moduleID]; ok {
+			addJUnitProperties(properties, module)
+			report.Name = module.meta[constants.TestModule]
+			report.Time, report.Timestamp = junitTime(module.duration), junitTimestamp(module.start)
+		}
+		report.Properties = newJUnitProperties(properties)
+		reports[moduleID] = report
+		return report
+	}
+
+	suites := make(map[uint64]*junitTestSuite)
+	getSuite := func(test *junitEvent) *junitTestSuite {
+		if suite, ok := suites[test.suiteID]; ok {
+			return suite
+		}
+		suite := &junitTestSuite{Name: test.meta[constants.TestSuite], start: test.start}
+		if e, ok := r.suites[test.suiteID]; ok {
+			properties := make(map[string]string)
+			addJUnitProperties(properties, e)
+			suite.Properties = newJUnitProperties(properties)
+			suite.Time, suite.Timestamp, suite.start = junitTime(e.duration), junitTimestamp(e.start), e.start
+		}
+		suites[test.suiteID] = suite
+		report := getReport(test.moduleID, test.sessionID)
+		report.Suites = append(report.Suites, suite)
+		return suite
+	}
+
+	for _, test := range r.tests {
+		testCase := &junitTestCase{
+			Name:      test.meta[constants.TestName],
+			ClassName: test.meta[constants.TestSuite],
+			Time:      junitTime(test.duration),
+		}
+		properties := make(map[string]string)
+		addJUnitProperties(properties, test)
+		testCase.Properties = newJUnitProperties(properties)
+
+		suite := getSuite(test)
+		report := getReport(test.moduleID, test.sessionID)
+		suite.Tests++
+		report.Tests++
+		switch test.meta[constants.TestStatus] {
+		case constants.TestStatusFail:
+			testCase.Failure = &junitFailure{
+				Message: test.meta[ext.ErrorMsg],
+				Type:    test.meta[ext.ErrorType],
+				Stack:   test.meta[ext.ErrorStack],
+			}
+			suite.Failures++
+			report.Failures++
+		case constants.TestStatusSkip:
+			testCase.Skipped = &junitSkipped{Message: test.meta[constants.TestSkipReason]}
+			suite.Skipped++
+			report.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	// the modules without any test are reported as well
+	for moduleID, module := range r.modules {
+		getReport(moduleID, module.sessionID)
+	}
+	for _, report := range reports {
+		slices.SortStableFunc(report.Suites, func(a, b *junitTestSuite) int {
+			return cmp.Compare(a.start, b.start)
+		})
+	}
+	return reports
+}
+
+// addJUnitProperties adds the tags of the event to the given properties.
+func addJUnitProperties(properties map[string]string, e *junitEvent) {
+	maps.Copy(properties, e.meta)
+	for k, v := range e.metrics {
+		properties[k] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
+
+// newJUnitProperties returns the given properties sorted by name, or nil when there are none.
+func newJUnitProperties(properties map[string]string) *junitProperties {
+	if len(properties) == 0 {
+		return nil
+	}
+	p := &junitProperties{Properties: make([]junitProperty, 0, len(properties))}
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		p.Properties = append(p.Properties, junitProperty{Name: name, Value: properties[name]})
+	}
+	return p
+}
+
+// junitTime returns the given duration in nanoseconds as the seconds expected by JUnit.
+func junitTime(duration int64) string {
+	return strconv.FormatFloat(time.Duration(duration).Seconds(), 'f', 3, 64)
+}
+
+// junitTimestamp returns the given time in nanoseconds since epoch as the ISO 8601 timestamp expected by JUnit.
+func junitTimestamp(start int64) string {
+	return time.Unix(0, start).UTC().Format("2006-01-02T15:04:05")
+}
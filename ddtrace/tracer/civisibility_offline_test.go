@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package tracer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/constants"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+)
+
+func newCiVisibilityTestSpan(spanType string, meta map[string]string) *Span {
+	s := newBasicSpan(spanType)
+	s.spanType = spanType
+	for k, v := range meta {
+		s.meta[k] = v
+	}
+	return s
+}
+
+func TestCiVisibilityOfflineWriter(t *testing.T) {
+	dir := t.TempDir()
+	c := &config{ciVisibilityEnabled: true, ciVisibilityOfflineDir: dir}
+	c.transport = newCiVisibilityOfflineTransport(c, dir)
+	w := newCiVisibilityTraceWriter(c)
+
+	ids := map[string]string{
+		constants.TestSessionIDTag: "1",
+		constants.TestModuleIDTag:  "2",
+		constants.TestSuiteIDTag:   "3",
+	}
+	testSpan := func(name, status string, meta map[string]string) *Span {
+		s := newCiVisibilityTestSpan(constants.SpanTypeTest, ids)
+		s.meta[constants.TestSuite] = "suite"
+		s.meta[constants.TestName] = name
+		s.meta[constants.TestStatus] = status
+		for k, v := range meta {
+			s.meta[k] = v
+		}
+		return s
+	}
+	w.add([]*Span{
+		testSpan("TestPass", constants.TestStatusPass, nil),
+		testSpan("TestFail", constants.TestStatusFail, map[string]string{ext.ErrorMsg: "boom", ext.ErrorStack: "stack"}),
+		testSpan("TestSkip", constants.TestStatusSkip, map[string]string{constants.TestSkipReason: "skipped"}),
+		newCiVisibilityTestSpan(constants.SpanTypeTestSuite, map[string]string{
+			constants.TestSessionIDTag: "1", constants.TestModuleIDTag: "2", constants.TestSuiteIDTag: "3", constants.TestSuite: "suite",
+		}),
+		newCiVisibilityTestSpan(constants.SpanTypeTestModule, map[string]string{
+			constants.TestSessionIDTag: "1", constants.TestModuleIDTag: "2", constants.TestModule: "module",
+		}),
+		newCiVisibilityTestSpan(constants.SpanTypeTestSession, map[string]string{
+			constants.TestSessionIDTag: "1", constants.TestCommand: "go test ./...",
+		}),
+	})
+	w.stop()
+
+	t.Run("payloads", func(t *testing.T) {
+		files, err := filepath.Glob(filepath.Join(dir, "citestcycle-*.msgpack"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		data, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+
+		var payload ciTestCyclePayload
+		require.NoError(t, msgp.Decode(bytes.NewReader(data), &payload))
+		var events ciVisibilityEvents
+		require.NoError(t, msgp.Decode(bytes.NewReader(payload.Events), &events))
+		assert.Len(t, events, 6)
+	})
+
+	t.Run("junit", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(dir, "junit-2.xml"))
+		require.NoError(t, err)
+
+		var report junitTestSuites
+		require.NoError(t, xml.Unmarshal(data, &report))
+		assert.Equal(t, "module", report.Name)
+		assert.Equal(t, 3, report.Tests)
+		assert.Equal(t, 1, report.Failures)
+		assert.Equal(t, 1, report.Skipped)
+		require.NotNil(t, report.Properties)
+		assert.Contains(t, report.Properties.Properties, junitProperty{Name: constants.TestCommand, Value: "go test ./..."})
+		assert.Contains(t, report.Properties.Properties, junitProperty{Name: constants.TestModule, Value: "module"})
+
+		require.Len(t, report.Suites, 1)
+		suite := report.Suites[0]
+		assert.Equal(t, "suite", suite.Name)
+		assert.Equal(t, 3, suite.Tests)
+		require.NotNil(t, suite.Properties)
+		require.Len(t, suite.TestCases, 3)
+
+		pass, fail, skip := suite.TestCases[0], suite.TestCases[1], suite.TestCases[2]
+		assert.Equal(t, "TestPass", pass.Name)
+		assert.Equal(t, "suite", pass.ClassName)
+		assert.Nil(t, pass.Failure)
+		assert.Nil(t, pass.Skipped)
+		assert.Contains(t, pass.Properties.Properties, junitProperty{Name: constants.TestStatus, Value: constants.TestStatusPass})
+		require.NotNil(t, fail.Failure)
+		assert.Equal(t, "boom", fail.Failure.Message)
+		assert.Equal(t, "stack", fail.Failure.Stack)
+		require.NotNil(t, skip.Skipped)
+		assert.Equal(t, "skipped", skip.Skipped.Message)
+	})
+}
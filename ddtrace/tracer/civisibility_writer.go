@@ -34,6 +34,7 @@ type ciVisibilityTraceWriter struct {
 	payload *ciVisibilityPayload // Encodes and buffers events in msgpack format.
 	climit  chan struct{}        // Limits the number of concurrent outgoing connections.
 	wg      sync.WaitGroup       // Waits for all uploads to finish.
+	junit   *junitReport         // Collects the test events for the JUnit XML reports in offline mode.
 }
 
 // newCiVisibilityTraceWriter creates a new instance of ciVisibilityTraceWriter.
@@ -47,11 +48,15 @@ type ciVisibilityTraceWriter struct {
 //	A pointer to an initialized ciVisibilityTraceWriter.
 func newCiVisibilityTraceWriter(c *config) *ciVisibilityTraceWriter {
 	log.Debug("ciVisibilityTraceWriter: creating trace writer instance")
-	return &ciVisibilityTraceWriter{
+	w := &ciVisibilityTraceWriter{
 		config:  c,
 		payload: newCiVisibilityPayload(),
 		climit:  make(chan struct{}, concurrentConnectionLimit),
 	}
+	if c.ciVisibilityOfflineDir != "" {
+		w.junit = newJUnitReport(c.ciVisibilityOfflineDir)
+	}
+	return w
 }
 
 // add adds a new trace to the payload. If the payload size exceeds the limit,
@@ -64,6 +69,9 @@ func (w *ciVisibilityTraceWriter) add(trace []*Span) {
 	telemetry.EventsEnqueueForSerialization()
 	for _, s := range trace {
 		cvEvent := getCiVisibilityEvent(s)
+		if w.junit != nil {
+			w.junit.add(cvEvent)
+		}
 		if err := w.payload.push(cvEvent); err != nil {
 			log.Error("ciVisibilityTraceWriter: Error encoding msgpack: %v", err)
 		}
@@ -77,6 +85,9 @@ func (w *ciVisibilityTraceWriter) add(trace []*Span) {
 func (w *ciVisibilityTraceWriter) stop() {
 	w.flush()
 	w.wg.Wait()
+	if w.junit != nil {
+		w.junit.write()
+	}
 }
 
 // flush sends the current payload to the transport. It ensures that the payload is reset
@@ -62,8 +62,9 @@ func TrackKafkaProduceOffset(topic string, partition int32, offset int64) {
 	}
 }
 
-// TrackKafkaHighWatermarkOffset should be used in the producer, to track when it produces a message.
-// if used together with TrackKafkaCommitOffset it can generate a Kafka lag in seconds metric.
+// TrackKafkaHighWatermarkOffset should be used in the consumer, to track the high watermark offset of a partition.
+// if used together with TrackKafkaCommitOffset it can generate a Kafka lag in seconds metric, and the
+// datadog.datastreams.kafka.consumer_lag gauge reporting the lag in number of messages.
 func TrackKafkaHighWatermarkOffset(cluster string, topic string, partition int32, offset int64) {
 	if t, ok := GetGlobalTracer().(dataStreamsContainer); ok {
 		if p := t.GetDataStreamsProcessor(); p != nil {
@@ -71,3 +72,25 @@ func TrackKafkaHighWatermarkOffset(cluster string, topic string, partition int32
 		}
 	}
 }
+
+// TrackDataStreamsBacklog tracks the latest value of a queue backlog identified by the given tags, such as the
+// approximate number of messages in a queue or the delay of a stream consumer. The tags must include the backlog
+// type, e.g. "type:sqs_approximate_messages".
+func TrackDataStreamsBacklog(value int64, tags ...string) {
+	if t, ok := GetGlobalTracer().(dataStreamsContainer); ok {
+		if p := t.GetDataStreamsProcessor(); p != nil {
+			p.TrackBacklog(value, tags...)
+		}
+	}
+}
+
+// TrackDataStreamsSchema tracks the usage of a schema, such as an Avro or Protobuf schema of a schema registry, to
+// serialize or deserialize a payload of the given topic. The schema usages are sampled and aggregated per topic by
+// Data Streams Monitoring, which also detects the schema changes.
+func TrackDataStreamsSchema(topic string, operation options.SchemaOperation, schema options.Schema) {
+	if t, ok := GetGlobalTracer().(dataStreamsContainer); ok {
+		if p := t.GetDataStreamsProcessor(); p != nil {
+			p.TrackSchema(topic, operation, schema)
+		}
+	}
+}
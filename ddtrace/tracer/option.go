@@ -72,6 +72,7 @@ var contribIntegrations = map[string]struct {
 	"google.golang.org/api":                         {"Google API", false},
 	"google.golang.org/grpc":                        {"gRPC", false},
 	"github.com/gorilla/mux":                        {"Gorilla Mux", false},
+	"github.com/gorilla/websocket":                  {"Gorilla WebSocket", false},
 	"gorm.io/gorm.v1":                               {"Gorm v1", false},
 	"github.com/graph-gophers/graphql-go":           {"Graph Gophers GraphQL", false},
 	"github.com/graphql-go/graphql":                 {"GraphQL-Go GraphQL", false},
@@ -84,6 +85,7 @@ var contribIntegrations = map[string]struct {
 	"github.com/labstack/echo/v4":                   {"echo v4", false},
 	"log/slog":                                      {"log/slog", false},
 	"github.com/miekg/dns":                          {"miekg/dns", false},
+	"nhooyr.io/websocket":                           {"nhooyr WebSocket", false},
 	"net/http":                                      {"HTTP", false},
 	"gopkg.in/olivere/elastic.v5":                   {"Elasticsearch v5", false},
 	"github.com/redis/go-redis/v9":                  {"Redis v9", false},
@@ -273,6 +275,9 @@ type config struct {
 	// dataStreamsMonitoringEnabled specifies whether the tracer should enable monitoring of data streams
 	dataStreamsMonitoringEnabled bool
 
+	// dataStreamsExportWriter is the writer the data streams stats payloads are exported to, if any
+	dataStreamsExportWriter io.Writer
+
 	// orchestrionCfg holds Orchestrion (aka auto-instrumentation) configuration.
 	// Only used for telemetry currently.
 	orchestrionCfg orchestrionConfig
@@ -299,6 +304,10 @@ type config struct {
 	// ciVisibilityAgentless controls if the tracer is loaded with CI Visibility agentless mode. default false
 	ciVisibilityAgentless bool
 
+	// ciVisibilityOfflineDir is the directory where the CI Visibility payloads and JUnit XML reports are written
+	// instead of being sent, when the offline mode is enabled. default empty/unused
+	ciVisibilityOfflineDir string
+
 	// logDirectory is directory for tracer logs specified by user-setting DD_TRACE_LOG_DIRECTORY. default empty/unused
 	logDirectory string
 
@@ -570,12 +579,19 @@ func newConfig(opts ...StartOption) (*config, error) {
 
 	// Check if CI Visibility mode is enabled
 	if internal.BoolEnv(constants.CIVisibilityEnabledEnvironmentVariable, false) {
-		c.ciVisibilityEnabled = true               // Enable CI Visibility mode
-		c.httpClientTimeout = time.Second * 45     // Increase timeout up to 45 seconds (same as other tracers in CIVis mode)
-		c.logStartup = false                       // If we are in CI Visibility mode we don't want to log the startup to stdout to avoid polluting the output
-		ciTransport := newCiVisibilityTransport(c) // Create a default CI Visibility Transport
-		c.transport = ciTransport                  // Replace the default transport with the CI Visibility transport
-		c.ciVisibilityAgentless = ciTransport.agentless
+		c.ciVisibilityEnabled = true           // Enable CI Visibility mode
+		c.httpClientTimeout = time.Second * 45 // Increase timeout up to 45 seconds (same as other tracers in CIVis mode)
+		c.logStartup = false                   // If we are in CI Visibility mode we don't want to log the startup to stdout to avoid polluting the output
+		if dir := os.Getenv(constants.CIVisibilityOfflineDirEnvironmentVariable); dir != "" {
+			// In offline mode neither the agent nor the backend can be reached, so the payloads are written to files
+			c.ciVisibilityOfflineDir = dir
+			c.transport = newCiVisibilityOfflineTransport(c, dir)
+			c.ciVisibilityAgentless = true
+		} else {
+			ciTransport := newCiVisibilityTransport(c) // Create a default CI Visibility Transport
+			c.transport = ciTransport                  // Replace the default transport with the CI Visibility transport
+			c.ciVisibilityAgentless = ciTransport.agentless
+		}
 	}
 
 	// if using stdout or traces are disabled or we are in ci visibility agentless mode, agent is disabled
@@ -896,6 +912,34 @@ func WithAppSecEnabled(enabled bool) StartOption {
 	}
 }
 
+// WithAppSecSignalsWriter exports the security signals detected by AppSec to w,
+// as JSON lines, in addition to the span tags. Each line is a rule match
+// holding the rule ID, the matched addresses with their values redacted, the
+// client IP, the user ID and the trace ID of the request.
+//
+// By default, the signals are only exported to the file, syslog or standard
+// output set by `DD_APPSEC_SIGNALS_OUTPUT`, if any.
+func WithAppSecSignalsWriter(w io.Writer) StartOption {
+	return func(c *config) {
+		c.appsecStartOptions = append(c.appsecStartOptions, appsecconfig.WithSignalsWriter(w))
+	}
+}
+
+// WithDataStreamsExportWriter exports the stats payloads flushed by Data Streams
+// Monitoring to w, as JSON lines, in addition to sending them to the agent.
+// Each line is a payload holding the stats buckets of a service, with the
+// hashes, parent hashes and edge tags of the pathways, so that they can be
+// verified locally.
+//
+// By default, the payloads are only exported to the file, standard output or
+// logger set by `DD_DATA_STREAMS_EXPORT_OUTPUT`, if any. Data Streams
+// Monitoring must be enabled for this option to have any effect.
+func WithDataStreamsExportWriter(w io.Writer) StartOption {
+	return func(c *config) {
+		c.dataStreamsExportWriter = w
+	}
+}
+
 // WithFeatureFlags specifies a set of feature flags to enable. Please take into account
 // that most, if not all features flags are considered to be experimental and result in
 // unexpected bugs.
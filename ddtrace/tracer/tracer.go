@@ -216,8 +216,10 @@ func Start(opts ...StartOption) error {
 		// CI Visibility agentless mode doesn't require remote configuration.
 
 		// start instrumentation telemetry unless it is disabled through the
-		// DD_INSTRUMENTATION_TELEMETRY_ENABLED env var
-		startTelemetry(t.config)
+		// DD_INSTRUMENTATION_TELEMETRY_ENABLED env var or we are in offline mode
+		if t.config.ciVisibilityOfflineDir == "" {
+			startTelemetry(t.config)
+		}
 
 		globalinternal.SetTracerInitialized(true)
 		return nil
@@ -237,9 +239,9 @@ func Start(opts ...StartOption) error {
 	// appsec.Start() may use the telemetry client to report activation, so it is
 	// important this happens _AFTER_ startTelemetry() has been called, so the
 	// client is appropriately configured.
-	appsecopts := make([]appsecConfig.StartOption, 0, len(t.config.appsecStartOptions)+1)
+	appsecopts := make([]appsecConfig.StartOption, 0, len(t.config.appsecStartOptions)+3)
 	appsecopts = append(appsecopts, t.config.appsecStartOptions...)
-	appsecopts = append(appsecopts, appsecConfig.WithRCConfig(cfg), appsecConfig.WithMetaStructAvailable(t.config.agent.metaStructAvailable))
+	appsecopts = append(appsecopts, appsecConfig.WithRCConfig(cfg), appsecConfig.WithMetaStructAvailable(t.config.agent.metaStructAvailable), appsecConfig.WithSpanTraceID(spanTraceID))
 	appsec.Start(appsecopts...)
 
 	// start instrumentation telemetry unless it is disabled through the
@@ -255,6 +257,16 @@ func Start(opts ...StartOption) error {
 	return nil
 }
 
+// spanTraceID returns the trace ID of the given span, which AppSec includes in
+// the security signals it exports.
+func spanTraceID(span any) string {
+	s, ok := span.(*Span)
+	if !ok || s == nil {
+		return ""
+	}
+	return s.Context().TraceID()
+}
+
 func storeConfig(c *config) {
 	uuid, _ := uuid.NewRandom()
 	name := fmt.Sprintf("datadog-tracer-info-%s", uuid.String()[0:8])
@@ -369,6 +381,9 @@ func newUnstartedTracer(opts ...StartOption) (*tracer, error) {
 	var dataStreamsProcessor *datastreams.Processor
 	if c.dataStreamsMonitoringEnabled {
 		dataStreamsProcessor = datastreams.NewProcessor(statsd, c.env, c.serviceName, c.version, c.agentURL, c.httpClient)
+		if c.dataStreamsExportWriter != nil {
+			dataStreamsProcessor.SetExportWriter(c.dataStreamsExportWriter)
+		}
 	}
 	var logFile *log.ManagedFile
 	if v := c.logDirectory; v != "" {
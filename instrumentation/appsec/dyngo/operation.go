@@ -71,6 +71,10 @@ type EventListener[O Operation, T any] func(O, T)
 // contextKey is used to store in a context.Context the ongoing Operation
 type contextKey struct{}
 
+// ContextKey is the key of the ongoing Operation in a context.Context, for the request contexts storing their values in
+// place, such as fasthttp's RequestCtx, which cannot be derived with RegisterOperation.
+type ContextKey = contextKey
+
 // Atomic *Operation so we can atomically read or swap it.
 var rootOperation atomic.Pointer[Operation]
 
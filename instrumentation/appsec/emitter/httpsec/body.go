@@ -0,0 +1,233 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httpsec
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// RequestBodyParsing is emitted as data by the HandlerOperation listeners to
+// request the HTTP request body to be buffered and parsed before the handler
+// runs, so that it can be monitored.
+type RequestBodyParsing struct {
+	// SizeLimit is the maximum number of bytes of the request body to buffer.
+	// Bodies larger than this limit are not parsed.
+	SizeLimit int
+}
+
+// monitorRequestBody buffers up to sizeLimit bytes of the request body,
+// re-wraps r.Body so that the handler can still read the whole body, and runs
+// the WAF with the parsed body.
+func monitorRequestBody(op *HandlerOperation, r *http.Request, sizeLimit int) {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return
+	}
+	parse := bodyParser(r.Header.Get("Content-Type"))
+	if parse == nil {
+		return
+	}
+
+	body, complete, err := bufferRequestBody(r, sizeLimit)
+	if err != nil {
+		log.Debug("appsec: could not read the http request body: %v", err)
+		return
+	}
+	if !complete {
+		log.Debug("appsec: http request body monitoring ignored: the body is larger than the size limit of %d bytes", sizeLimit)
+		return
+	}
+
+	op.runRequestBody(parse, body)
+}
+
+// MonitorRequestBody parses and monitors the given request body when requested
+// by the listeners with [RequestBodyParsing]. It is meant for frameworks, such
+// as fasthttp, which read the whole request body before calling handlers.
+func (op *HandlerOperation) MonitorRequestBody(contentType string, body []byte) {
+	if op.bodyParsingSizeLimit == 0 || len(body) == 0 {
+		return
+	}
+	if len(body) > op.bodyParsingSizeLimit {
+		log.Debug("appsec: http request body monitoring ignored: the body is larger than the size limit of %d bytes", op.bodyParsingSizeLimit)
+		return
+	}
+	if parse := bodyParser(contentType); parse != nil {
+		op.runRequestBody(parse, body)
+	}
+}
+
+func (op *HandlerOperation) runRequestBody(parse func([]byte) (any, error), body []byte) {
+	parsed, err := parse(body)
+	if err != nil {
+		log.Debug("appsec: could not parse the http request body: %v", err)
+		return
+	}
+
+	op.Run(op, addresses.NewAddressesBuilder().WithRequestBody(parsed).Build())
+}
+
+// bufferedBody is a request body whose first bytes were read in advance.
+type bufferedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bufferRequestBody reads up to sizeLimit bytes of the request body and
+// replaces r.Body with a reader returning the buffered bytes followed by the
+// rest of the original body. It returns the buffered bytes and whether they
+// are the whole body.
+func bufferRequestBody(r *http.Request, sizeLimit int) ([]byte, bool, error) {
+	if r.ContentLength > int64(sizeLimit) {
+		// Avoid buffering a body we already know we won't parse
+		return nil, false, nil
+	}
+
+	// Read one more byte than the limit to know whether the body is complete
+	buf, err := io.ReadAll(io.LimitReader(r.Body, int64(sizeLimit)+1))
+	r.Body = &bufferedBody{
+		Reader: io.MultiReader(bytes.NewReader(buf), r.Body),
+		Closer: r.Body,
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return buf, len(buf) <= sizeLimit, nil
+}
+
+// bodyParser returns the function parsing request bodies of the given content
+// type, or nil when the content type is not supported.
+func bodyParser(contentType string) func([]byte) (any, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return parseJSON
+	case mediaType == "application/x-www-form-urlencoded":
+		return parseURLEncodedForm
+	case mediaType == "multipart/form-data":
+		boundary := params["boundary"]
+		return func(body []byte) (any, error) {
+			return parseMultipartForm(body, boundary)
+		}
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return parseXML
+	default:
+		return nil
+	}
+}
+
+func parseJSON(body []byte) (any, error) {
+	var parsed any
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+func parseURLEncodedForm(body []byte) (any, error) {
+	parsed, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]string(parsed), nil
+}
+
+// parseMultipartForm returns the values of the form fields of the given
+// multipart body. The content of uploaded files is not returned, only their
+// file names are.
+func parseMultipartForm(body []byte, boundary string) (map[string][]string, error) {
+	if boundary == "" {
+		return nil, errors.New("missing multipart boundary")
+	}
+
+	form := make(map[string][]string)
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return form, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		if filename := part.FileName(); filename != "" {
+			form[name] = append(form[name], filename)
+			continue
+		}
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		form[name] = append(form[name], string(value))
+	}
+}
+
+// parseXML returns a generic representation of the given XML document, where
+// every element is a map from its name to the list of its contents: a map of
+// its attributes (if any), its non-blank character data, and its child
+// elements.
+func parseXML(body []byte) (any, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// Stack of the contents of the elements being decoded, the first one
+	// holding the document's root element.
+	stack := [][]any{nil}
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			var content []any
+			if len(token.Attr) > 0 {
+				attrs := make(map[string]string, len(token.Attr))
+				for _, attr := range token.Attr {
+					attrs[attr.Name.Local] = attr.Value
+				}
+				content = append(content, attrs)
+			}
+			stack = append(stack, content)
+		case xml.EndElement:
+			content := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			parent := len(stack) - 1
+			stack[parent] = append(stack[parent], map[string]any{token.Name.Local: content})
+		case xml.CharData:
+			if text := strings.TrimSpace(string(token)); text != "" && len(stack) > 1 {
+				stack[len(stack)-1] = append(stack[len(stack)-1], text)
+			}
+		}
+	}
+
+	if len(stack) != 1 || len(stack[0]) == 0 {
+		return nil, errors.New("invalid xml document")
+	}
+	return stack[0][0], nil
+}
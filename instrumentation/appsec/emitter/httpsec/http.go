@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/actions"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/iast"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/trace"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
 	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
@@ -41,6 +42,14 @@ type (
 		method string
 		// route is the HTTP route for the current handler operation (or the URL if no route is available).
 		route string
+		// bodyParsingSizeLimit is the maximum number of bytes of the request body to parse before the handler runs,
+		// as requested by the listeners with [RequestBodyParsing]. Zero disables body parsing.
+		bodyParsingSizeLimit int
+		// responseBodyParsingSizeLimit is the maximum number of bytes of the response body to capture, as requested
+		// by the listeners with [ResponseBodyParsing]. Zero disables response body parsing.
+		responseBodyParsingSizeLimit int
+		// taintScope is the IAST taint scope of the request, as provided by the listeners.
+		taintScope *iast.Scope
 	}
 
 	// HandlerOperationArgs is the HTTP handler operation arguments.
@@ -61,6 +70,7 @@ type (
 	HandlerOperationRes struct {
 		Headers    map[string][]string
 		StatusCode int
+		Body       any // Optional: the parsed response body, if it was captured
 	}
 )
 
@@ -92,10 +102,24 @@ func StartOperation(ctx context.Context, args HandlerOperationArgs, span trace.T
 	dyngo.OnData(op, func(a *actions.BlockHTTP) {
 		action.Store(a)
 	})
+	dyngo.OnData(op, func(p RequestBodyParsing) {
+		op.bodyParsingSizeLimit = p.SizeLimit
+	})
+	dyngo.OnData(op, func(p ResponseBodyParsing) {
+		op.responseBodyParsingSizeLimit = p.SizeLimit
+	})
+	dyngo.OnData(op, func(s *iast.Scope) {
+		op.taintScope = s
+	})
 
 	return op, &action, dyngo.StartAndRegisterOperation(ctx, op, args)
 }
 
+// TaintScope returns the IAST taint scope of the request, or nil when IAST is disabled.
+func (op *HandlerOperation) TaintScope() *iast.Scope {
+	return op.taintScope
+}
+
 // Framework returns the name of the framework or library that started the operation.
 func (op *HandlerOperation) Framework() string {
 	return op.framework
@@ -111,6 +135,16 @@ func (op *HandlerOperation) Route() string {
 	return op.route
 }
 
+// MonitorPathParams monitors the given path parameters. It is meant for
+// frameworks, such as fiber, whose routes are only resolved when the middleware
+// calls the next handlers, after the operation started.
+func (op *HandlerOperation) MonitorPathParams(params map[string]string) {
+	if len(params) == 0 {
+		return
+	}
+	op.Run(op, addresses.NewAddressesBuilder().WithPathParams(params).Build())
+}
+
 // Finish the HTTP handler operation and its children operations and write everything to the service entry span.
 func (op *HandlerOperation) Finish(res HandlerOperationRes) {
 	dyngo.FinishOperation(op, res)
@@ -183,15 +217,29 @@ func BeforeHandle(
 		PathParams:   pathParams,
 	}, span)
 	tr := r.WithContext(ctx)
+	if op.bodyParsingSizeLimit > 0 && blockAtomic.Load() == nil {
+		monitorRequestBody(op, tr, op.bodyParsingSizeLimit)
+	}
+
+	tw := w
+	var bodyWriter *responseBodyWriter
+	if op.responseBodyParsingSizeLimit > 0 {
+		tw, bodyWriter = newResponseBodyWriter(w, op.responseBodyParsingSizeLimit)
+	}
 
 	afterHandle := func() {
 		var statusCode int
 		if res, ok := w.(interface{ Status() int }); ok {
 			statusCode = res.Status()
 		}
+		var body any
+		if bodyWriter != nil {
+			body = bodyWriter.parsedBody()
+		}
 		op.Finish(HandlerOperationRes{
 			Headers:    opts.ResponseHeaderCopier(w),
 			StatusCode: statusCode,
+			Body:       body,
 		})
 
 		// Execute the onBlock functions to make sure blocking works properly
@@ -214,7 +262,7 @@ func BeforeHandle(
 		blockPtr.Handler = nil
 		handled = true
 	}
-	return w, tr, afterHandle, handled
+	return tw, tr, afterHandle, handled
 }
 
 // WrapHandler wraps the given HTTP handler with the abstract HTTP operation defined by HandlerOperationArgs and
@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build ignore
+// +build ignore
+
+// This program generates the wrapper implementations of http.ResponseWriter
+// capturing the response body that also satisfy http.Flusher, http.Pusher,
+// http.CloseNotifier, http.Hijacker and io.ReaderFrom, based on whether or not
+// the passed in http.ResponseWriter also satisfies them.
+
+package main
+
+import (
+	"os"
+	"text/template"
+)
+
+func main() {
+	interfaces := []string{"Flusher", "Pusher", "CloseNotifier", "Hijacker", "ReaderFrom"}
+	var combos [][][]string
+	for pick := len(interfaces); pick > 0; pick-- {
+		combos = append(combos, combinations(interfaces, pick))
+	}
+	template.Must(template.New("").Funcs(template.FuncMap{"pkg": pkg}).Parse(tpl)).Execute(os.Stdout, map[string]interface{}{
+		"Interfaces":   interfaces,
+		"Combinations": combos,
+	})
+}
+
+// pkg returns the package of the given interface.
+func pkg(iface string) string {
+	if iface == "ReaderFrom" {
+		return "io"
+	}
+	return "http"
+}
+
+// combinations returns all possible unique selections of size `pick` of a list
+// of strings for which order does not matter
+//
+// an example:
+//
+//	Combinations([cat, dog, bird], 2):
+//	  [cat] -> Combinations([dog, bird], 1)
+//	    [cat, dog]
+//	    [cat, bird]
+//	  [dog] -> Combinations([bird], 1)
+//	    [dog, bird]
+//	  [bird] -> Combinations([], 0)
+//	    n/a
+func combinations(list []string, pick int) (all [][]string) {
+	switch pick {
+	case 0:
+		// nothing to do
+	case 1:
+		for i := range list {
+			all = append(all, list[i:i+1])
+		}
+	default:
+		// we recursively find combinations by taking each item in the list
+		// and then finding the combinations at (pick-1) for the remaining
+		// items in the list
+		// the reason we start at [i+1:], is because the order of the items in
+		// the list doesn't matter, so this will remove all the duplicates we
+		// would get otherwise
+		for i := range list {
+			for _, next := range combinations(list[i+1:], pick-1) {
+				all = append(all, append([]string{list[i]}, next...))
+			}
+		}
+	}
+	return all
+}
+
+var tpl = `// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Code generated by make_response_body.go DO NOT EDIT
+
+package httpsec
+
+import (
+	"io"
+	"net/http"
+)
+
+// newResponseBodyWriter wraps w into a responseBodyWriter capturing the
+// response body up to sizeLimit bytes. It also checks for various interfaces
+// (Flusher, Pusher, CloseNotifier, Hijacker, ReaderFrom) and if the underlying
+// http.ResponseWriter implements them it generates an unnamed struct with the
+// appropriate fields. The data written with io.ReaderFrom is captured too.
+//
+// This code is generated because we have to account for all the permutations
+// of the interfaces.
+func newResponseBodyWriter(w http.ResponseWriter, sizeLimit int) (http.ResponseWriter, *responseBodyWriter) {
+{{- range .Interfaces }}
+	h{{.}}, ok{{.}} := w.({{pkg .}}.{{.}})
+{{- end }}
+
+	bw := &responseBodyWriter{ResponseWriter: w, sizeLimit: sizeLimit}
+	if okReaderFrom {
+		hReaderFrom = &responseBodyReaderFrom{bw, hReaderFrom}
+	}
+	switch {
+{{- range .Combinations }}
+	{{- range . }}
+	case {{ range $i, $v := . }}{{ if gt $i 0 }} && {{ end }}ok{{ $v }}{{ end }}:
+		w = struct {
+			*responseBodyWriter
+		{{- range . }}
+			{{pkg .}}.{{.}}
+		{{- end }}
+		}{bw{{ range . }}, h{{.}}{{ end }}}
+	{{- end }}
+{{- end }}
+	default:
+		w = bw
+	}
+
+	return w, bw
+}
+`
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httpsec
+
+//go:generate sh -c "go run make_response_body.go | gofmt > response_body_gen.go"
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// ResponseBodyParsing is emitted as data by the HandlerOperation listeners to
+// request the HTTP response body to be captured and parsed once the handler
+// returned, so that it can be monitored.
+type ResponseBodyParsing struct {
+	// SizeLimit is the maximum number of bytes of the response body to capture.
+	// Bodies larger than this limit are not parsed.
+	SizeLimit int
+}
+
+// responseBodyWriter is an http.ResponseWriter capturing a copy of the response
+// body written through it, up to a size limit.
+type responseBodyWriter struct {
+	http.ResponseWriter
+	sizeLimit int
+	body      bytes.Buffer
+	truncated bool
+}
+
+// Write writes the data to the underlying http.ResponseWriter and captures it.
+func (w *responseBodyWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.capture(b[:n])
+	return n, err
+}
+
+// capture appends b to the captured response body, unless the size limit is
+// reached.
+func (w *responseBodyWriter) capture(b []byte) {
+	if w.truncated {
+		return
+	}
+	if w.body.Len()+len(b) > w.sizeLimit {
+		// Partial bodies cannot be parsed: release what was captured so far
+		w.truncated = true
+		w.body = bytes.Buffer{}
+		return
+	}
+	w.body.Write(b)
+}
+
+// responseBodyReaderFrom is the io.ReaderFrom of a responseBodyWriter whose
+// underlying http.ResponseWriter implements it, capturing the data it reads.
+type responseBodyReaderFrom struct {
+	w  *responseBodyWriter
+	rf io.ReaderFrom
+}
+
+// ReadFrom reads the data from r with the underlying io.ReaderFrom and captures
+// it.
+func (r *responseBodyReaderFrom) ReadFrom(src io.Reader) (int64, error) {
+	return r.rf.ReadFrom(io.TeeReader(src, captureWriter{r.w}))
+}
+
+// captureWriter is an io.Writer capturing the data written to it in the
+// response body of a responseBodyWriter.
+type captureWriter struct {
+	w *responseBodyWriter
+}
+
+func (c captureWriter) Write(b []byte) (int, error) {
+	c.w.capture(b)
+	return len(b), nil
+}
+
+// Unwrap returns the underlying wrapped http.ResponseWriter.
+func (w *responseBodyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// parsedBody returns the parsed response body, or nil when it was not
+// captured entirely or is not a JSON document.
+func (w *responseBodyWriter) parsedBody() any {
+	if w.truncated {
+		log.Debug("appsec: http response body monitoring ignored: the body is larger than the size limit of %d bytes", w.sizeLimit)
+		return nil
+	}
+	return parseResponseBody(w.Header().Get("Content-Type"), w.body.Bytes())
+}
+
+// ParseResponseBody returns the given response body parsed when requested by
+// the listeners with [ResponseBodyParsing], so that it can be passed in the
+// [HandlerOperationRes]. It is meant for frameworks, such as fasthttp, which
+// buffer the whole response body.
+func (op *HandlerOperation) ParseResponseBody(contentType string, body []byte) any {
+	if op.responseBodyParsingSizeLimit == 0 {
+		return nil
+	}
+	if len(body) > op.responseBodyParsingSizeLimit {
+		log.Debug("appsec: http response body monitoring ignored: the body is larger than the size limit of %d bytes", op.responseBodyParsingSizeLimit)
+		return nil
+	}
+	return parseResponseBody(contentType, body)
+}
+
+// parseResponseBody returns the given response body parsed, or nil when it is
+// not a JSON document.
+func parseResponseBody(contentType string, body []byte) any {
+	if len(body) == 0 {
+		return nil
+	}
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return nil
+		}
+	}
+
+	parsed, err := parseJSON(body)
+	if err != nil {
+		log.Debug("appsec: could not parse the http response body: %v", err)
+		return nil
+	}
+	return parsed
+}
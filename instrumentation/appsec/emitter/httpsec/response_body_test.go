@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package httpsec
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseBodyWriter(t *testing.T) {
+	t.Run("interfaces", func(t *testing.T) {
+		var i struct {
+			http.ResponseWriter
+			http.Flusher
+			http.Pusher
+			http.CloseNotifier
+			http.Hijacker
+		}
+		w, _ := newResponseBodyWriter(i, 256)
+		_, ok := w.(http.Flusher)
+		assert.True(t, ok)
+		_, ok = w.(http.Pusher)
+		assert.True(t, ok)
+		_, ok = w.(http.CloseNotifier)
+		assert.True(t, ok)
+		_, ok = w.(http.Hijacker)
+		assert.True(t, ok)
+		_, ok = w.(io.ReaderFrom)
+		assert.False(t, ok)
+	})
+
+	t.Run("ReaderFrom", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		var i struct {
+			http.ResponseWriter
+			io.ReaderFrom
+		}
+		i.ResponseWriter = rec
+		i.ReaderFrom = rec.Body
+		i.Header().Set("Content-Type", "application/json")
+
+		w, bw := newResponseBodyWriter(i, 256)
+		rf, ok := w.(io.ReaderFrom)
+		require.True(t, ok)
+		n, err := rf.ReadFrom(strings.NewReader(`{"id": 1337}`))
+		require.NoError(t, err)
+		require.EqualValues(t, 12, n)
+		require.Equal(t, `{"id": 1337}`, rec.Body.String())
+		require.Equal(t, map[string]any{"id": float64(1337)}, bw.parsedBody())
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		rec.Header().Set("Content-Type", "application/json")
+		w, bw := newResponseBodyWriter(rec, 8)
+		_, err := io.Copy(w, bytes.NewBufferString(`{"id": 1337}`))
+		require.NoError(t, err)
+		require.Equal(t, `{"id": 1337}`, rec.Body.String())
+		require.Nil(t, bw.parsedBody())
+	})
+}
@@ -7,6 +7,7 @@ package httpsec
 
 import (
 	"context"
+	"net/url"
 	"sync"
 
 	"github.com/DataDog/dd-trace-go/v2/appsec/events"
@@ -25,6 +26,9 @@ type (
 	RoundTripOperationArgs struct {
 		// URL corresponds to the address `server.io.net.url`.
 		URL string
+		// ParsedURL is the URL of the request as given to the HTTP client, when available. Its components are the
+		// strings the URL was parsed from, unlike URL which is serialized.
+		ParsedURL *url.URL
 	}
 
 	// RoundTripOperationRes is the round trip operation results.
@@ -35,10 +39,21 @@ func (RoundTripOperationArgs) IsArgOf(*RoundTripOperation)   {}
 func (RoundTripOperationRes) IsResultOf(*RoundTripOperation) {}
 
 func ProtectRoundTrip(ctx context.Context, url string) error {
-	opArgs := RoundTripOperationArgs{
+	return protectRoundTrip(ctx, RoundTripOperationArgs{
 		URL: url,
-	}
+	})
+}
+
+// ProtectRoundTripURL is like [ProtectRoundTrip] but also provides the parsed
+// URL to the listeners.
+func ProtectRoundTripURL(ctx context.Context, u *url.URL) error {
+	return protectRoundTrip(ctx, RoundTripOperationArgs{
+		URL:       u.String(),
+		ParsedURL: u,
+	})
+}
 
+func protectRoundTrip(ctx context.Context, opArgs RoundTripOperationArgs) error {
 	parent, _ := dyngo.FromContext(ctx)
 	if parent == nil { // No parent operation => we can't monitor the request
 		badInputContextOnce.Do(func() {
@@ -63,7 +78,7 @@ func ProtectRoundTrip(ctx context.Context, url string) error {
 	dyngo.FinishOperation(op, RoundTripOperationRes{})
 
 	if err != nil {
-		log.Debug("appsec: outgoing http request blocked by the WAF on URL: %s", url)
+		log.Debug("appsec: outgoing http request blocked by the WAF on URL: %s", opArgs.URL)
 		return err
 	}
 
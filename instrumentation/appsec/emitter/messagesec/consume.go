@@ -0,0 +1,148 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package messagesec is the message queue consumer instrumentation API and
+// contract for AppSec, defining an abstract run-time representation of the
+// consumption of a message.
+// Message queue integrations must use this package to enable AppSec features
+// for their consumers, which listens to this package's operation events.
+//
+// The consume span of a message is the service entry span of its consume
+// operation, which starts when the message is received and finishes when its
+// processing is considered done by the integration, which is usually when
+// the next message is received. The WAF blocking actions cannot interrupt
+// the consumption of a message and are only reported in the consume span,
+// while the RASP protections of the message processing can block their
+// operations when their context holds the consume operation, as returned by
+// StartConsumeOperation or ContextWithMessage.
+package messagesec
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/trace"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
+)
+
+// The messaging systems of the consume operations.
+const (
+	SystemKafka     = "kafka"
+	SystemGCPPubsub = "google-pubsub"
+)
+
+type (
+	// ConsumeOperation represents the consumption of a message by a message
+	// queue consumer. It must be created with StartConsumeOperation() and
+	// finished with its Finish() method.
+	ConsumeOperation struct {
+		dyngo.Operation
+		*waf.ContextOperation
+
+		// key identifies the message for ContextWithMessage.
+		key MessageKey
+		// wafContextOwner indicates if the waf.ContextOperation was started by us or not and if we need to close it.
+		wafContextOwner bool
+	}
+
+	// ConsumeOperationArgs is the consume operation arguments.
+	ConsumeOperationArgs struct {
+		// System is the messaging system, such as SystemKafka or SystemGCPPubsub.
+		System string
+		// Topic is the topic or subscription the message was consumed from.
+		Topic string
+		// Headers are the message headers, or attributes.
+		// Corresponds to the address `server.request.headers.no_cookies`.
+		Headers map[string][]string
+		// Key is the message key, or ordering key.
+		// Corresponds to the `key` path parameter of the address `server.request.path_params`.
+		Key string
+		// Value is the decoded message value, as returned by DecodeValue.
+		// Corresponds to the address `server.request.body`.
+		Value any
+	}
+
+	// ConsumeOperationRes is the consume operation results. Empty as of today.
+	ConsumeOperationRes struct{}
+
+	// MessageKey uniquely identifies a message being consumed in ContextWithMessage.
+	MessageKey struct {
+		Topic     string
+		Partition int32
+		Offset    int64
+		ID        string
+	}
+)
+
+func (ConsumeOperationArgs) IsArgOf(*ConsumeOperation)   {}
+func (ConsumeOperationRes) IsResultOf(*ConsumeOperation) {}
+
+// consumedMessages indexes the consume operations that are not finished yet by their message key.
+var consumedMessages sync.Map
+
+// StartConsumeOperation starts the consume operation of a message, whose
+// consume span is given, along with the given arguments, and emits a start
+// event up in the operation stack. The returned context holds the operation
+// so that the RASP protections apply to the processing of the message. When
+// key is not the zero value, the context can also be retrieved with
+// ContextWithMessage until the operation finishes.
+func StartConsumeOperation(ctx context.Context, span trace.TagSetter, key MessageKey, args ConsumeOperationArgs) (context.Context, *ConsumeOperation) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	wafOp, found := dyngo.FindOperation[waf.ContextOperation](ctx)
+	if !found {
+		wafOp, ctx = waf.StartContextOperation(ctx, span)
+	}
+	op := &ConsumeOperation{
+		Operation:        dyngo.NewOperation(wafOp),
+		ContextOperation: wafOp,
+		key:              key,
+		wafContextOwner:  !found,
+	}
+	ctx = dyngo.StartAndRegisterOperation(ctx, op, args)
+	if key != (MessageKey{}) {
+		consumedMessages.Store(key, op)
+	}
+	return ctx, op
+}
+
+// Finish the consume operation and emit a finish event up in the operation
+// stack. It must be called before finishing the consume span.
+func (op *ConsumeOperation) Finish() {
+	if op.key != (MessageKey{}) {
+		consumedMessages.CompareAndDelete(op.key, op)
+	}
+	dyngo.FinishOperation(op, ConsumeOperationRes{})
+	if op.wafContextOwner {
+		op.ContextOperation.Finish()
+	}
+}
+
+// ContextWithMessage returns a copy of ctx holding the consume operation of
+// the message identified by key, so that the RASP protections apply to its
+// processing. ctx is returned as is when the message is not being consumed.
+func ContextWithMessage(ctx context.Context, key MessageKey) context.Context {
+	op, ok := consumedMessages.Load(key)
+	if !ok {
+		return ctx
+	}
+	return dyngo.RegisterOperation(ctx, op.(*ConsumeOperation))
+}
+
+// DecodeValue decodes the given message value as JSON when possible, or
+// returns it as a string otherwise.
+func DecodeValue(value []byte) any {
+	if len(value) == 0 {
+		return nil
+	}
+	var decoded any
+	if err := json.Unmarshal(value, &decoded); err == nil {
+		return decoded
+	}
+	return string(value)
+}
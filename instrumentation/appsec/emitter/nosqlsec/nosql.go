@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package nosqlsec defines the NoSQL database operations monitored by AppSec
+// to protect against NoSQL injections, such as the injection of MongoDB query
+// operators or of Redis commands.
+//
+// No rule of the default security ruleset monitors NoSQL operations yet: they
+// are only monitored when the rules provided with DD_APPSEC_RULES, or through
+// remote configuration, use the `server.db.nosql.query` address, as reported
+// by [IsMonitored]. Integrations should check it before building the queries
+// to monitor.
+package nosqlsec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+var badInputContextOnce sync.Once
+
+// The database systems of the NoSQL operations.
+const (
+	SystemMongoDB = "mongodb"
+	SystemRedis   = "redis"
+)
+
+type (
+	// NoSQLOperation type embodies the queries and commands sent to a NoSQL database.
+	NoSQLOperation struct {
+		dyngo.Operation
+	}
+
+	// NoSQLOperationArgs is the arguments for a NoSQL operation
+	NoSQLOperationArgs struct {
+		// System is the database system, such as SystemMongoDB or SystemRedis
+		System string
+		// Query corresponds to the address `server.db.nosql.query`: the filter documents or aggregation pipeline of
+		// a MongoDB query, or the command arguments of Redis
+		Query any
+	}
+
+	// NoSQLOperationRes is the result of a NoSQL operation
+	NoSQLOperationRes struct{}
+)
+
+func (NoSQLOperationArgs) IsArgOf(*NoSQLOperation)   {}
+func (NoSQLOperationRes) IsResultOf(*NoSQLOperation) {}
+
+// IsMonitored returns whether the NoSQL operations of the request found in ctx
+// are monitored by its security rules.
+func IsMonitored(ctx context.Context) bool {
+	op, ok := dyngo.FindOperation[waf.ContextOperation](ctx)
+	return ok && op.SupportsAnyAddress(addresses.ServerDBNoSQLQueryAddr)
+}
+
+// ProtectRedisCommand runs the NoSQL operation of the given Redis command
+// arguments when they are monitored, and returns an
+// *events.BlockingSecurityEvent when the command must be blocked.
+func ProtectRedisCommand(ctx context.Context, args ...any) error {
+	if !IsMonitored(ctx) {
+		return nil
+	}
+	query := make([]string, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			query[i] = arg
+		case []byte:
+			query[i] = string(arg)
+		default:
+			query[i] = fmt.Sprint(arg)
+		}
+	}
+	return ProtectNoSQLOperation(ctx, SystemRedis, query)
+}
+
+// ProtectNoSQLOperation runs the NoSQL operation of the given database system
+// and query, and returns an *events.BlockingSecurityEvent when it must be
+// blocked.
+func ProtectNoSQLOperation(ctx context.Context, system string, query any) error {
+	parent, _ := dyngo.FromContext(ctx)
+	if parent == nil { // No parent operation => we can't monitor the request
+		badInputContextOnce.Do(func() {
+			log.Debug("appsec: outgoing NoSQL operation monitoring ignored: could not find the handler " +
+				"instrumentation metadata in the request context: the request handler is not being monitored by a " +
+				"middleware function or the incoming request context has not be forwarded correctly to the database client")
+		})
+		return nil
+	}
+
+	op := &NoSQLOperation{
+		Operation: dyngo.NewOperation(parent),
+	}
+
+	var err *events.BlockingSecurityEvent
+	dyngo.OnData(op, func(e *events.BlockingSecurityEvent) {
+		err = e
+	})
+
+	dyngo.StartOperation(op, NoSQLOperationArgs{
+		System: system,
+		Query:  query,
+	})
+	dyngo.FinishOperation(op, NoSQLOperationRes{})
+
+	if err != nil {
+		log.Debug("appsec: outgoing %s operation blocked by the WAF", system)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ossec
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+)
+
+type (
+	// ExecOperation type embodies any kind of function calls that will result in a call to an execve(2) syscall
+	ExecOperation struct {
+		dyngo.Operation
+	}
+
+	// ExecOperationArgs is the arguments for an exec operation
+	ExecOperationArgs struct {
+		// Path is the path of the executable to be run
+		Path string
+		// Args holds the command line arguments, including the command as Args[0]
+		Args []string
+	}
+
+	// ExecOperationRes is the result of an exec operation
+	ExecOperationRes struct {
+		// Err is the error returned by the function
+		Err *error
+	}
+)
+
+func (ExecOperationArgs) IsArgOf(*ExecOperation)   {}
+func (ExecOperationRes) IsResultOf(*ExecOperation) {}
@@ -15,6 +15,7 @@ const (
 	ServerRequestBodyAddr              = "server.request.body"
 	ServerResponseStatusAddr           = "server.response.status"
 	ServerResponseHeadersNoCookiesAddr = "server.response.headers.no_cookies"
+	ServerResponseBodyAddr             = "server.response.body"
 
 	ClientIPAddr = "http.client_ip"
 
@@ -25,11 +26,13 @@ const (
 	UserLoginSuccessAddr = "server.business_logic.users.login.success"
 	UserLoginFailureAddr = "server.business_logic.users.login.failure"
 
-	ServerIoNetURLAddr    = "server.io.net.url"
-	ServerIOFSFileAddr    = "server.io.fs.file"
-	ServerDBStatementAddr = "server.db.statement"
-	ServerDBTypeAddr      = "server.db.system"
-	ServerSysExecCmd      = "server.sys.exec.cmd"
+	ServerIoNetURLAddr     = "server.io.net.url"
+	ServerIOFSFileAddr     = "server.io.fs.file"
+	ServerDBStatementAddr  = "server.db.statement"
+	ServerDBTypeAddr       = "server.db.system"
+	ServerDBNoSQLQueryAddr = "server.db.nosql.query"
+	ServerSysExecCmd       = "server.sys.exec.cmd"
+	ServerSysShellCmd      = "server.sys.shell.cmd"
 
 	GRPCServerMethodAddr                   = "grpc.server.method"
 	GRPCServerRequestMetadataAddr          = "grpc.server.request.metadata"
@@ -77,6 +77,16 @@ func (b *RunAddressDataBuilder) WithRequestBody(body any) *RunAddressDataBuilder
 	return b
 }
 
+// WithWebSocketMessage sets the given inbound WebSocket message as an ephemeral request body, since a connection
+// receives many messages which must all be inspected by the WAF context of its upgrade request.
+func (b *RunAddressDataBuilder) WithWebSocketMessage(message any) *RunAddressDataBuilder {
+	if message == nil {
+		return b
+	}
+	b.Ephemeral[ServerRequestBodyAddr] = message
+	return b
+}
+
 func (b *RunAddressDataBuilder) WithResponseStatus(status int) *RunAddressDataBuilder {
 	if status == 0 {
 		return b
@@ -93,6 +103,14 @@ func (b *RunAddressDataBuilder) WithResponseHeadersNoCookies(headers map[string]
 	return b
 }
 
+func (b *RunAddressDataBuilder) WithResponseBody(body any) *RunAddressDataBuilder {
+	if body == nil {
+		return b
+	}
+	b.Persistent[ServerResponseBodyAddr] = body
+	return b
+}
+
 func (b *RunAddressDataBuilder) WithClientIP(ip netip.Addr) *RunAddressDataBuilder {
 	if !ip.IsValid() {
 		return b
@@ -180,6 +198,15 @@ func (b *RunAddressDataBuilder) WithDBType(driver string) *RunAddressDataBuilder
 	return b
 }
 
+func (b *RunAddressDataBuilder) WithDBNoSQLQuery(query any) *RunAddressDataBuilder {
+	if query == nil {
+		return b
+	}
+	b.Ephemeral[ServerDBNoSQLQueryAddr] = query
+	b.Scope = waf.RASPScope
+	return b
+}
+
 func (b *RunAddressDataBuilder) WithSysExecCmd(cmd []string) *RunAddressDataBuilder {
 	if len(cmd) == 0 {
 		return b
@@ -189,6 +216,15 @@ func (b *RunAddressDataBuilder) WithSysExecCmd(cmd []string) *RunAddressDataBuil
 	return b
 }
 
+func (b *RunAddressDataBuilder) WithSysShellCmd(cmd string) *RunAddressDataBuilder {
+	if cmd == "" {
+		return b
+	}
+	b.Ephemeral[ServerSysShellCmd] = cmd
+	b.Scope = waf.RASPScope
+	return b
+}
+
 func (b *RunAddressDataBuilder) WithGRPCMethod(method string) *RunAddressDataBuilder {
 	if method == "" {
 		return b
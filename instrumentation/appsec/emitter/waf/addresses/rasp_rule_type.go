@@ -12,10 +12,12 @@ import (
 type RASPRuleType string
 
 const (
-	RASPRuleTypeLFI  RASPRuleType = "lfi"
-	RASPRuleTypeSSRF RASPRuleType = "ssrf"
-	RASPRuleTypeSQLI RASPRuleType = "sql_injection"
-	RASPRuleTypeCMDI RASPRuleType = "command_injection"
+	RASPRuleTypeLFI    RASPRuleType = "lfi"
+	RASPRuleTypeSSRF   RASPRuleType = "ssrf"
+	RASPRuleTypeSQLI   RASPRuleType = "sql_injection"
+	RASPRuleTypeCMDI   RASPRuleType = "command_injection"
+	RASPRuleTypeSHI    RASPRuleType = "shell_injection"
+	RASPRuleTypeNoSQLI RASPRuleType = "nosql_injection"
 )
 
 func RASPRuleTypes() []RASPRuleType {
@@ -24,6 +26,8 @@ func RASPRuleTypes() []RASPRuleType {
 		RASPRuleTypeSSRF,
 		RASPRuleTypeSQLI,
 		RASPRuleTypeCMDI,
+		RASPRuleTypeSHI,
+		RASPRuleTypeNoSQLI,
 	}
 }
 
@@ -43,6 +47,10 @@ func RASPRuleTypeFromAddressSet(addressSet waf.RunAddressData) (RASPRuleType, bo
 			return RASPRuleTypeSQLI, true
 		case ServerSysExecCmd:
 			return RASPRuleTypeCMDI, true
+		case ServerSysShellCmd:
+			return RASPRuleTypeSHI, true
+		case ServerDBNoSQLQueryAddr:
+			return RASPRuleTypeNoSQLI, true
 		}
 	}
 
@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package websocketsec defines the WebSocket connection and message
+// operations monitored by AppSec, so that the inbound messages of a WebSocket
+// connection are inspected with a WAF context living as long as the
+// connection, which usually outlives the handler of its HTTP upgrade request.
+package websocketsec
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/trace"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+var badInputContextOnce sync.Once
+
+type (
+	// ConnectionOperation type embodies a WebSocket connection, from its upgrade to its closing. It must be created with
+	// StartConnectionOperation() and finished with its Finish() method.
+	ConnectionOperation struct {
+		dyngo.Operation
+		// wafOp is the WAF context operation of the inbound messages of the connection.
+		wafOp *waf.ContextOperation
+	}
+
+	// ConnectionOperationArgs is the arguments of a WebSocket connection operation. Empty as of today.
+	ConnectionOperationArgs struct{}
+
+	// ConnectionOperationRes is the result of a WebSocket connection operation. Empty as of today.
+	ConnectionOperationRes struct{}
+
+	// MessageOperation type embodies the inbound messages received by a WebSocket connection.
+	MessageOperation struct {
+		dyngo.Operation
+	}
+
+	// MessageOperationArgs is the arguments of a WebSocket message operation
+	MessageOperationArgs struct {
+		// Message is the decoded message payload, inspected as an ephemeral `server.request.body` address
+		Message any
+	}
+
+	// MessageOperationRes is the result of a WebSocket message operation
+	MessageOperationRes struct{}
+)
+
+func (ConnectionOperationArgs) IsArgOf(*ConnectionOperation)   {}
+func (ConnectionOperationRes) IsResultOf(*ConnectionOperation) {}
+func (MessageOperationArgs) IsArgOf(*MessageOperation)         {}
+func (MessageOperationRes) IsResultOf(*MessageOperation)       {}
+
+// StartConnectionOperation starts the operation of an upgraded WebSocket
+// connection, whose span is given, along with the WAF context of its inbound
+// messages, whose security events are reported in the connection span. The
+// returned context holds the operation and must be passed to ProtectMessage.
+// The operation must be finished by Finish when the connection is closed, and
+// before finishing its span.
+func StartConnectionOperation(ctx context.Context, span trace.TagSetter) (context.Context, *ConnectionOperation) {
+	// The connection operation is a child of the root operation rather than of the upgrade request operations, which
+	// are finished once the upgrade request handler returns, and whose HTTP blocking actions cannot be applied to the
+	// hijacked connection.
+	op := &ConnectionOperation{
+		Operation: dyngo.NewOperation(nil),
+	}
+	ctx = dyngo.StartAndRegisterOperation(ctx, op, ConnectionOperationArgs{})
+	op.wafOp, ctx = waf.StartContextOperation(ctx, span)
+	return ctx, op
+}
+
+// Finish finishes the WAF context of the connection, which reports its security events in the connection span, and
+// the connection operation.
+func (op *ConnectionOperation) Finish() {
+	op.wafOp.Finish()
+	dyngo.FinishOperation(op, ConnectionOperationRes{})
+}
+
+// ProtectMessage runs the operation of the given inbound WebSocket message
+// with the WAF context of its connection, held by ctx as returned by
+// StartConnectionOperation, and returns an *events.BlockingSecurityEvent when
+// the connection must be closed. The message is decoded as JSON when possible,
+// or inspected as a string otherwise. The WAF timeout budget is shared by the
+// messages of the connection.
+func ProtectMessage(ctx context.Context, message []byte) error {
+	conn, found := dyngo.FindOperation[ConnectionOperation](ctx)
+	if !found { // No connection operation => we can't monitor the connection
+		badInputContextOnce.Do(func() {
+			log.Debug("appsec: WebSocket message monitoring ignored: could not find the connection instrumentation " +
+				"metadata in the message context: the connection was not started with StartConnectionOperation or " +
+				"its context has not be forwarded correctly")
+		})
+		return nil
+	}
+	if len(message) == 0 {
+		return nil
+	}
+
+	op := &MessageOperation{
+		Operation: dyngo.NewOperation(conn.wafOp),
+	}
+
+	var err *events.BlockingSecurityEvent
+	dyngo.OnData(op, func(e *events.BlockingSecurityEvent) {
+		err = e
+	})
+
+	dyngo.StartOperation(op, MessageOperationArgs{
+		Message: decodeMessage(message),
+	})
+	dyngo.FinishOperation(op, MessageOperationRes{})
+
+	if err != nil {
+		log.Debug("appsec: WebSocket connection blocked by the WAF")
+		return err
+	}
+
+	return nil
+}
+
+func decodeMessage(message []byte) any {
+	var decoded any
+	if err := json.Unmarshal(message, &decoded); err == nil {
+		return decoded
+	}
+	return string(message)
+}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package fasthttpsec provides the helpers shared by the AppSec integrations of
+// fasthttp and of the frameworks built on top of it, such as fiber. It does not
+// depend on fasthttp: its types are used through the interfaces they implement.
+package fasthttpsec
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+)
+
+type (
+	// Visitor is implemented by the fasthttp request and response headers, and
+	// by the fasthttp arguments.
+	Visitor interface {
+		VisitAll(f func(key, value []byte))
+	}
+
+	// CookieVisitor is implemented by the fasthttp request headers.
+	CookieVisitor interface {
+		VisitAllCookie(f func(key, value []byte))
+	}
+
+	// Args is implemented by the fasthttp arguments, such as the query
+	// arguments of a request.
+	Args interface {
+		Visitor
+		Len() int
+	}
+
+	// RequestCtx is implemented by the fasthttp request context, which stores
+	// its values in place.
+	RequestCtx interface {
+		context.Context
+		UserValue(key any) any
+		SetUserValue(key, value any)
+		RemoveUserValue(key any)
+	}
+)
+
+// RegisterOperation registers op in the context tree and stores it in the
+// given fasthttp request context, which fasthttp handlers use as their
+// context.Context, so that they find it. The returned function removes op from
+// the request context, restoring the operation it held before, and must be
+// called once op is finished.
+func RegisterOperation(ctx RequestCtx, op dyngo.Operation) (unregister func()) {
+	dyngo.RegisterOperation(ctx, op)
+	prev := ctx.UserValue(dyngo.ContextKey{})
+	ctx.SetUserValue(dyngo.ContextKey{}, op)
+	return func() {
+		if prev != nil {
+			ctx.SetUserValue(dyngo.ContextKey{}, prev)
+		} else {
+			ctx.RemoveUserValue(dyngo.ContextKey{})
+		}
+	}
+}
+
+// MakeHeaders returns the given fasthttp headers as http.Header. It accepts
+// both request and response headers.
+func MakeHeaders(h Visitor) http.Header {
+	headers := make(http.Header)
+	h.VisitAll(func(key, value []byte) {
+		headers.Add(string(key), string(value))
+	})
+	return headers
+}
+
+// MakeCookies returns the cookies of the given fasthttp request headers,
+// following the specification of the rule address `server.request.cookies`.
+func MakeCookies(h CookieVisitor) map[string][]string {
+	var cookies map[string][]string
+	h.VisitAllCookie(func(key, value []byte) {
+		if cookies == nil {
+			cookies = make(map[string][]string)
+		}
+		cookies[string(key)] = append(cookies[string(key)], string(value))
+	})
+	return cookies
+}
+
+// MakeQueryParams returns the given fasthttp query arguments, following the
+// specification of the rule address `server.request.query`.
+func MakeQueryParams(args Args) map[string][]string {
+	query := make(map[string][]string, args.Len())
+	args.VisitAll(func(key, value []byte) {
+		query[string(key)] = append(query[string(key)], string(value))
+	})
+	return query
+}
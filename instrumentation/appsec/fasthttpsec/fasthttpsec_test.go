@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package fasthttpsec
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+)
+
+// args mimics the fasthttp headers and arguments, which may hold a key several times.
+type args [][2]string
+
+func (a args) VisitAll(f func(key, value []byte)) {
+	for _, kv := range a {
+		f([]byte(kv[0]), []byte(kv[1]))
+	}
+}
+
+func (a args) VisitAllCookie(f func(key, value []byte)) {
+	a.VisitAll(f)
+}
+
+func (a args) Len() int {
+	return len(a)
+}
+
+func TestMakeHeaders(t *testing.T) {
+	headers := MakeHeaders(args{{"Content-Type", "text/plain"}, {"X-Forwarded-For", "1.2.3.4"}, {"x-forwarded-for", "5.6.7.8"}})
+	assert.Equal(t, http.Header{
+		"Content-Type":    {"text/plain"},
+		"X-Forwarded-For": {"1.2.3.4", "5.6.7.8"},
+	}, headers)
+}
+
+func TestMakeCookies(t *testing.T) {
+	assert.Nil(t, MakeCookies(args{}))
+	assert.Equal(t, map[string][]string{"session": {"a", "b"}, "theme": {"dark"}},
+		MakeCookies(args{{"session", "a"}, {"theme", "dark"}, {"session", "b"}}))
+}
+
+func TestMakeQueryParams(t *testing.T) {
+	assert.Empty(t, MakeQueryParams(args{}))
+	assert.Equal(t, map[string][]string{"id": {"1", "2"}, "q": {""}},
+		MakeQueryParams(args{{"id", "1"}, {"q", ""}, {"id", "2"}}))
+}
+
+// requestCtx mimics the fasthttp RequestCtx, which stores its values in place.
+type requestCtx struct {
+	context.Context
+	values map[any]any
+}
+
+func (ctx *requestCtx) Value(key any) any {
+	return ctx.values[key]
+}
+
+func (ctx *requestCtx) UserValue(key any) any {
+	return ctx.values[key]
+}
+
+func (ctx *requestCtx) SetUserValue(key, value any) {
+	ctx.values[key] = value
+}
+
+func (ctx *requestCtx) RemoveUserValue(key any) {
+	delete(ctx.values, key)
+}
+
+func TestRegisterOperation(t *testing.T) {
+	ctx := &requestCtx{Context: context.Background(), values: make(map[any]any)}
+	op := dyngo.NewOperation(nil)
+	unregister := RegisterOperation(ctx, op)
+
+	found, ok := dyngo.FromContext(ctx)
+	require.True(t, ok)
+	require.Equal(t, op, found)
+
+	child := dyngo.NewOperation(op)
+	unregisterChild := RegisterOperation(ctx, child)
+	found, _ = dyngo.FromContext(ctx)
+	require.Equal(t, child, found)
+
+	unregisterChild()
+	found, _ = dyngo.FromContext(ctx)
+	require.Equal(t, op, found)
+
+	// Finished operations are not reachable from the request context
+	unregister()
+	_, ok = dyngo.FromContext(ctx)
+	require.False(t, ok)
+	require.Empty(t, ctx.values)
+}
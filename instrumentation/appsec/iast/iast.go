@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+// Package iast implements the taint tracking of Interactive Application
+// Security Testing (IAST). Request-derived strings are marked as tainted by
+// the AppSec HTTP instrumentation, and the taint is propagated through the
+// string operations of this package, which Orchestrion injects in place of
+// their standard library counterparts. Tainted data reaching a sensitive sink,
+// such as an SQL query or a file path, is reported as a vulnerability.
+//
+// Taint tracking works on the memory of the strings: substrings of a tainted
+// string, such as the values parsed from a tainted query string, are tainted
+// too without needing any propagation. The tainted strings of a request are
+// tracked by its [Scope], held by the request's operation, which the string
+// operations find through the goroutine-local context of Orchestrion.
+//
+// IAST is disabled by default. It is enabled with DD_IAST_ENABLED=true, and its
+// Orchestrion integration is opt-in: it is not part of the
+// github.com/DataDog/dd-trace-go/orchestrion/all/v2 package, and must be
+// imported from the application's orchestrion.tool.go file:
+//
+//	import _ "github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/iast" // integration
+//
+// The string operations are only replaced in the application's packages, not
+// in the standard library nor in the tracer. String concatenation with the +
+// operator is not propagated, as Orchestrion cannot replace operators: the
+// concatenation of a tainted string is not tainted.
+package iast
+
+// Source is the origin of a tainted string.
+type Source struct {
+	// Origin is the kind of request data the tainted string comes from, such as [OriginParameter].
+	Origin string `json:"origin"`
+	// Name is the name of the request data, such as the query parameter or header name, if any.
+	Name string `json:"name,omitempty"`
+	// Value is the tainted request data.
+	Value string `json:"value,omitempty"`
+}
+
+// The origins of the tainted request data.
+const (
+	OriginURI           = "http.request.uri"
+	OriginParameter     = "http.request.parameter"
+	OriginPathParameter = "http.request.path.parameter"
+	OriginHeader        = "http.request.header"
+	OriginCookie        = "http.request.cookie.value"
+	OriginBody          = "http.request.body"
+)
@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package iast_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	wrapos "github.com/DataDog/dd-trace-go/v2/contrib/os"
+	wrapexec "github.com/DataDog/dd-trace-go/v2/contrib/os/exec"
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/httpsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/sqlsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/iast"
+	httptrace "github.com/DataDog/dd-trace-go/v2/instrumentation/httptracemock"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec"
+)
+
+// TestPropagatedSinks checks the vulnerabilities reported when the tainted
+// request data reaches the sinks through the string operations Orchestrion
+// injects in the application's code.
+func TestPropagatedSinks(t *testing.T) {
+	t.Setenv("DD_IAST_ENABLED", "true")
+	t.Setenv("DD_APPSEC_RASP_ENABLED", "false")
+	testutils.StartAppSec(t)
+
+	if !appsec.IASTEnabled() {
+		t.Skip("IAST needs to be enabled for this test")
+	}
+
+	// The scope of the request being served, found by the string operations
+	// like Orchestrion does through its goroutine-local context.
+	var served atomic.Pointer[iast.Scope]
+	iast.SetCurrentScope(t, served.Load)
+
+	dir := t.TempDir()
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		op, _ := dyngo.FromContext(ctx)
+		served.Store(iast.OperationScope(op))
+		defer served.Store(nil)
+
+		id := r.URL.Query().Get("id")
+		switch r.URL.Query().Get("sink") {
+		case "sql-sprintf":
+			require.NoError(t, sqlsec.ProtectSQLOperation(ctx, iast.Sprintf("SELECT * FROM users WHERE id = %s", id), "sqlite"))
+		case "sql-replace":
+			require.NoError(t, sqlsec.ProtectSQLOperation(ctx, iast.ReplaceAll("SELECT * FROM users WHERE id = ?", "?", id), "sqlite"))
+		case "sql-concat":
+			require.NoError(t, sqlsec.ProtectSQLOperation(ctx, "SELECT * FROM users WHERE id = "+id, "sqlite"))
+		case "file":
+			if f, err := wrapos.OpenFile(ctx, iast.FilepathJoin(dir, id), 0, 0); err == nil {
+				f.Close()
+			}
+		case "exec":
+			require.NoError(t, wrapexec.CommandContext(ctx, "echo", iast.Join([]string{"user", id}, "-")).Run())
+		case "ssrf":
+			u, err := url.Parse(iast.ToLower(r.Header.Get("X-Target")))
+			require.NoError(t, err)
+			require.NoError(t, httpsec.ProtectRoundTripURL(ctx, u))
+		}
+		w.WriteHeader(204)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		sink     string
+		headers  map[string]string
+		vuln     string
+		evidence string
+		source   iast.Source
+	}{
+		{
+			sink:     "sql-sprintf",
+			vuln:     "SQL_INJECTION",
+			evidence: "SELECT * FROM users WHERE id = 1",
+			source:   iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"},
+		},
+		{
+			sink:     "sql-replace",
+			vuln:     "SQL_INJECTION",
+			evidence: "SELECT * FROM users WHERE id = 1",
+			source:   iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"},
+		},
+		{
+			// The + operator is not propagated.
+			sink: "sql-concat",
+		},
+		{
+			sink:     "file",
+			vuln:     "PATH_TRAVERSAL",
+			evidence: filepath.Join(dir, "1"),
+			source:   iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"},
+		},
+		{
+			sink:     "exec",
+			vuln:     "COMMAND_INJECTION",
+			evidence: "echo user-1",
+			source:   iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"},
+		},
+		{
+			sink:     "ssrf",
+			headers:  map[string]string{"X-Target": "HTTP://EXAMPLE.COM/"},
+			vuln:     "SSRF",
+			evidence: "http://example.com/",
+			source:   iast.Source{Origin: iast.OriginHeader, Name: "X-Target", Value: "HTTP://EXAMPLE.COM/"},
+		},
+	} {
+		t.Run(tc.sink, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			req, err := http.NewRequest("GET", srv.URL+"?id=1&sink="+tc.sink, nil)
+			require.NoError(t, err)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			res, err := srv.Client().Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, 204, res.StatusCode)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			if tc.vuln == "" {
+				require.NotContains(t, spans[0].Tags(), "_dd.iast.json")
+				return
+			}
+			var report struct {
+				Sources         []iast.Source
+				Vulnerabilities []struct {
+					Type     string
+					Evidence struct{ Value string }
+				}
+			}
+			tag, _ := spans[0].Tag("_dd.iast.json").(string)
+			require.NoError(t, json.Unmarshal([]byte(tag), &report))
+			require.Len(t, report.Vulnerabilities, 1)
+			require.Equal(t, tc.vuln, report.Vulnerabilities[0].Type)
+			require.Equal(t, tc.evidence, report.Vulnerabilities[0].Evidence.Value)
+			require.Equal(t, []iast.Source{tc.source}, report.Sources)
+		})
+	}
+}
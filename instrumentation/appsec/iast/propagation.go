@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package iast
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Sprintf is like [fmt.Sprintf] and propagates the taint of its format and
+// string arguments to its result.
+func Sprintf(format string, a ...any) string {
+	res := fmt.Sprintf(format, a...)
+	scope := currentScope()
+	if scope == nil {
+		return res
+	}
+	inputs := make([]string, 0, len(a)+1)
+	inputs = append(inputs, format)
+	for _, arg := range a {
+		if arg, ok := arg.(string); ok {
+			inputs = append(inputs, arg)
+		}
+	}
+	return scope.propagate(res, inputs...)
+}
+
+// Join is like [strings.Join] and propagates the taint of its arguments to its
+// result.
+func Join(elems []string, sep string) string {
+	res := strings.Join(elems, sep)
+	scope := currentScope()
+	if scope == nil {
+		return res
+	}
+	return scope.propagate(scope.propagate(res, elems...), sep)
+}
+
+// Replace is like [strings.Replace] and propagates the taint of its arguments
+// to its result.
+func Replace(s, old, new string, n int) string {
+	res := strings.Replace(s, old, new, n)
+	scope := currentScope()
+	if scope == nil {
+		return res
+	}
+	return scope.propagate(res, s, new)
+}
+
+// ReplaceAll is like [strings.ReplaceAll] and propagates the taint of its
+// arguments to its result.
+func ReplaceAll(s, old, new string) string {
+	res := strings.ReplaceAll(s, old, new)
+	scope := currentScope()
+	if scope == nil {
+		return res
+	}
+	return scope.propagate(res, s, new)
+}
+
+// ToLower is like [strings.ToLower] and propagates the taint of its argument
+// to its result.
+func ToLower(s string) string {
+	res := strings.ToLower(s)
+	scope := currentScope()
+	if scope == nil {
+		return res
+	}
+	return scope.propagate(res, s)
+}
+
+// ToUpper is like [strings.ToUpper] and propagates the taint of its argument
+// to its result.
+func ToUpper(s string) string {
+	res := strings.ToUpper(s)
+	scope := currentScope()
+	if scope == nil {
+		return res
+	}
+	return scope.propagate(res, s)
+}
+
+// FilepathJoin is like [filepath.Join] and propagates the taint of its
+// arguments to its result.
+func FilepathJoin(elem ...string) string {
+	res := filepath.Join(elem...)
+	scope := currentScope()
+	if scope == nil {
+		return res
+	}
+	return scope.propagate(res, elem...)
+}
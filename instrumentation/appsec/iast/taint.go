@@ -0,0 +1,183 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package iast
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+)
+
+// maxTaintedRanges is the maximum number of tainted strings tracked per scope.
+// Strings tainted past this limit are ignored and the scope is marked as
+// truncated.
+const maxTaintedRanges = 4096
+
+type (
+	// taintedRange is a range of memory holding tainted string data.
+	taintedRange struct {
+		start, end uintptr
+		// value references the tainted string so that its memory cannot be
+		// reused while it is tracked.
+		value  string
+		source *Source
+	}
+
+	// Scope tracks the strings tainted over the course of a request. They
+	// stop being tainted once the scope is closed.
+	Scope struct {
+		mu        sync.RWMutex
+		ranges    []*taintedRange
+		closed    bool
+		truncated bool
+	}
+
+	// ScopeHolder is implemented by the operations holding the taint scope of
+	// the request they monitor, such as the HTTP handler operation.
+	ScopeHolder interface {
+		TaintScope() *Scope
+	}
+)
+
+// openScopes is the number of scopes not closed yet, allowing to skip the
+// lookup of the current scope when nothing is tainted.
+var openScopes atomic.Int32
+
+// NewScope returns a new taint scope.
+func NewScope() *Scope {
+	openScopes.Add(1)
+	return &Scope{}
+}
+
+// OperationScope returns the taint scope of the given operation or of its
+// closest parent holding one, or nil when none of them does.
+func OperationScope(op dyngo.Operation) *Scope {
+	for ; op != nil; op = op.Parent() {
+		if holder, ok := op.(ScopeHolder); ok {
+			if scope := holder.TaintScope(); scope != nil {
+				return scope
+			}
+		}
+	}
+	return nil
+}
+
+// currentScope returns the taint scope of the request being served by the
+// current goroutine. It is only found when Orchestrion is enabled, through the
+// operation stored in its goroutine-local context.
+var currentScope = func() *Scope {
+	if openScopes.Load() == 0 {
+		return nil
+	}
+	op, _ := dyngo.FromContext(context.Background())
+	return OperationScope(op)
+}
+
+// Taint marks the given string as tainted by the given source until the scope
+// is closed.
+func (s *Scope) Taint(value string, source Source) {
+	s.add(value, &source)
+}
+
+// Close stops tracking the strings tainted in the scope, including the ones
+// the taint was propagated to.
+func (s *Scope) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.ranges = nil
+	openScopes.Add(-1)
+}
+
+// Truncated returns whether strings were left untainted because the scope
+// reached its maximum number of tainted strings.
+func (s *Scope) Truncated() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.truncated
+}
+
+// SourceOf returns the source of the given string when it is tainted, fully or
+// partially. It is safe to call on a nil scope.
+func (s *Scope) SourceOf(value string) (Source, bool) {
+	r := s.lookup(value)
+	if r == nil {
+		return Source{}, false
+	}
+	return *r.source, true
+}
+
+// IsTainted returns whether the given string is tainted, fully or partially.
+// It is safe to call on a nil scope.
+func (s *Scope) IsTainted(value string) bool {
+	return s.lookup(value) != nil
+}
+
+func (s *Scope) add(value string, source *Source) {
+	if len(value) == 0 {
+		return
+	}
+	start := uintptr(unsafe.Pointer(unsafe.StringData(value)))
+	r := &taintedRange{
+		start:  start,
+		end:    start + uintptr(len(value)),
+		value:  value,
+		source: source,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if len(s.ranges) >= maxTaintedRanges {
+		s.truncated = true
+		return
+	}
+	s.ranges = append(s.ranges, r)
+}
+
+// lookup returns the narrowest tainted range overlapping the given string, so
+// that the most specific source is returned for values parsed out of a larger
+// tainted string.
+func (s *Scope) lookup(value string) *taintedRange {
+	if s == nil || len(value) == 0 {
+		return nil
+	}
+	start := uintptr(unsafe.Pointer(unsafe.StringData(value)))
+	end := start + uintptr(len(value))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var found *taintedRange
+	for _, r := range s.ranges {
+		if start < r.end && r.start < end && (found == nil || r.end-r.start < found.end-found.start) {
+			found = r
+		}
+	}
+	return found
+}
+
+// propagate taints result with the source of the first tainted input, and
+// returns it.
+func (s *Scope) propagate(result string, inputs ...string) string {
+	if len(result) == 0 || s.lookup(result) != nil {
+		return result
+	}
+	for _, input := range inputs {
+		if r := s.lookup(input); r != nil {
+			s.add(result, r.source)
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package iast
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+)
+
+func TestTaint(t *testing.T) {
+	uri := strings.Clone("/users?id=1&name=bob")
+	scope := NewScope()
+	defer scope.Close()
+	scope.Taint(uri, Source{Origin: OriginURI, Value: uri})
+
+	t.Run("tainted", func(t *testing.T) {
+		src, ok := scope.SourceOf(uri)
+		require.True(t, ok)
+		require.Equal(t, Source{Origin: OriginURI, Value: uri}, src)
+	})
+
+	t.Run("not-tainted", func(t *testing.T) {
+		require.False(t, scope.IsTainted(strings.Clone(uri)))
+		require.False(t, scope.IsTainted("/users"))
+		require.False(t, scope.IsTainted(""))
+	})
+
+	t.Run("other-scope", func(t *testing.T) {
+		other := NewScope()
+		defer other.Close()
+		require.False(t, other.IsTainted(uri))
+		require.False(t, (*Scope)(nil).IsTainted(uri))
+	})
+
+	t.Run("substring", func(t *testing.T) {
+		u, err := url.ParseRequestURI(uri)
+		require.NoError(t, err)
+		require.True(t, scope.IsTainted(u.Path))
+		require.True(t, scope.IsTainted(u.Query().Get("name")))
+	})
+
+	t.Run("narrowest-source", func(t *testing.T) {
+		u, err := url.ParseRequestURI(uri)
+		require.NoError(t, err)
+		name := u.Query().Get("name")
+		scope.Taint(name, Source{Origin: OriginParameter, Name: "name", Value: name})
+
+		src, ok := scope.SourceOf(u.Query().Get("name"))
+		require.True(t, ok)
+		require.Equal(t, Source{Origin: OriginParameter, Name: "name", Value: "bob"}, src)
+		src, ok = scope.SourceOf(u.Path)
+		require.True(t, ok)
+		require.Equal(t, OriginURI, src.Origin)
+	})
+}
+
+func TestScopeClose(t *testing.T) {
+	value := strings.Clone("tainted")
+	scope := NewScope()
+	withCurrentScope(t, scope)
+	scope.Taint(value, Source{Origin: OriginHeader, Name: "x-header", Value: value})
+	propagated := Sprintf("SELECT %s", value)
+	require.True(t, scope.IsTainted(value))
+	require.True(t, scope.IsTainted(propagated))
+
+	scope.Close()
+	require.False(t, scope.IsTainted(value))
+	require.False(t, scope.IsTainted(propagated))
+
+	// Tainting a closed scope is ignored
+	scope.Taint(value, Source{Origin: OriginHeader})
+	require.False(t, scope.IsTainted(value))
+}
+
+func TestScopeTruncated(t *testing.T) {
+	scope := NewScope()
+	defer scope.Close()
+	for i := 0; i < maxTaintedRanges; i++ {
+		scope.Taint(strings.Clone("value"), Source{Origin: OriginBody})
+	}
+	require.False(t, scope.Truncated())
+
+	value := strings.Clone("value")
+	scope.Taint(value, Source{Origin: OriginBody})
+	require.True(t, scope.Truncated())
+	require.False(t, scope.IsTainted(value))
+}
+
+type holderOperation struct {
+	dyngo.Operation
+	scope *Scope
+}
+
+func (op *holderOperation) TaintScope() *Scope {
+	return op.scope
+}
+
+func TestOperationScope(t *testing.T) {
+	scope := NewScope()
+	defer scope.Close()
+	root := dyngo.NewRootOperation()
+	holder := &holderOperation{Operation: dyngo.NewOperation(root), scope: scope}
+	child := dyngo.NewOperation(holder)
+
+	require.Same(t, scope, OperationScope(holder))
+	require.Same(t, scope, OperationScope(child))
+	require.Nil(t, OperationScope(root))
+	require.Nil(t, OperationScope(nil))
+}
+
+func TestPropagation(t *testing.T) {
+	value := strings.Clone("../etc/passwd")
+	source := Source{Origin: OriginParameter, Name: "file", Value: value}
+	scope := NewScope()
+	defer scope.Close()
+	withCurrentScope(t, scope)
+	scope.Taint(value, source)
+
+	for _, tc := range []struct {
+		name    string
+		result  string
+		tainted bool
+	}{
+		{name: "Sprintf", result: Sprintf("cat %s", value), tainted: true},
+		{name: "Sprintf/untainted", result: Sprintf("cat %s", "file"), tainted: false},
+		{name: "Join", result: Join([]string{"a", value}, "/"), tainted: true},
+		{name: "Join/untainted", result: Join([]string{"a", "b"}, "/"), tainted: false},
+		{name: "Replace", result: Replace(value, "..", ".", 1), tainted: true},
+		{name: "ReplaceAll", result: ReplaceAll("/tmp/FILE", "FILE", value), tainted: true},
+		{name: "ToLower", result: ToLower(strings.ToUpper(value)), tainted: false},
+		{name: "ToUpper", result: ToUpper(value), tainted: true},
+		{name: "FilepathJoin", result: FilepathJoin("/tmp", "dir", value), tainted: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src, ok := scope.SourceOf(tc.result)
+			require.Equal(t, tc.tainted, ok)
+			if tc.tainted {
+				require.Equal(t, source, src)
+			}
+		})
+	}
+}
+
+// withCurrentScope makes the string operations propagate the taint of the
+// given scope, as they do for the request being served under Orchestrion.
+func withCurrentScope(t *testing.T, scope *Scope) {
+	SetCurrentScope(t, func() *Scope { return scope })
+}
+
+// SetCurrentScope makes the string operations propagate the taint of the scope
+// returned by current until the end of the test, in place of the scope of the
+// request being served that Orchestrion finds.
+func SetCurrentScope(t *testing.T, current func() *Scope) {
+	prev := currentScope
+	currentScope = current
+	t.Cleanup(func() { currentScope = prev })
+}
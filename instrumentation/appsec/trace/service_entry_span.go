@@ -48,6 +48,11 @@ type (
 
 func (ServiceEntrySpanArgs) IsArgOf(*ServiceEntrySpanOperation) {}
 
+// Span returns the service entry span the tags are added to.
+func (op *ServiceEntrySpanOperation) Span() TagSetter {
+	return op.tagSetter
+}
+
 // SetTag adds the key/value pair to the tags to add to the service entry span
 func (op *ServiceEntrySpanOperation) SetTag(key string, value any) {
 	op.mu.Lock()
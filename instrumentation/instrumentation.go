@@ -108,6 +108,10 @@ func (i *Instrumentation) AppSecRASPEnabled() bool {
 	return appsec.RASPEnabled()
 }
 
+func (i *Instrumentation) AppSecIASTEnabled() bool {
+	return appsec.IASTEnabled()
+}
+
 func (i *Instrumentation) DataStreamsEnabled() bool {
 	return internal.BoolEnv("DD_DATA_STREAMS_ENABLED", false)
 }
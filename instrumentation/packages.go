@@ -66,12 +66,15 @@ const (
 	PackageGraphGophersGraphQLGo   Package = "graph-gophers/graphql-go"
 	PackageGormIOGormV1            Package = "gorm.io/gorm.v1"
 	PackageGorillaMux              Package = "gorilla/mux"
+	PackageGorillaWebsocket        Package = "gorilla/websocket"
+	PackageNhooyrWebsocket         Package = "nhooyr.io/websocket"
 	PackageUptraceBun              Package = "uptrace/bun"
 	PackageLogSlog                 Package = "log/slog"
 
 	PackageValkeyIoValkeyGo         Package = "valkey-io/valkey-go"
 	PackageEnvoyProxyGoControlPlane Package = "envoyproxy/go-control-plane"
 	PackageOS                       Package = "os"
+	PackageOSExec                   Package = "os/exec"
 	PackageRedisRueidis             Package = "redis/rueidis"
 
 	// Deprecated packages
@@ -748,6 +751,30 @@ var packages = map[Package]PackageInfo{
 			},
 		},
 	},
+	PackageGorillaWebsocket: {
+		TracedPackage: "github.com/gorilla/websocket",
+		EnvVarPrefix:  "GORILLA_WEBSOCKET",
+		naming: map[Component]componentNames{
+			ComponentServer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("websocket"),
+				buildOpNameV0:      staticName("websocket.connection"),
+				buildOpNameV1:      staticName("websocket.connection"),
+			},
+		},
+	},
+	PackageNhooyrWebsocket: {
+		TracedPackage: "nhooyr.io/websocket",
+		EnvVarPrefix:  "NHOOYR_WEBSOCKET",
+		naming: map[Component]componentNames{
+			ComponentServer: {
+				useDDServiceV0:     true,
+				buildServiceNameV0: staticName("websocket"),
+				buildOpNameV0:      staticName("websocket.connection"),
+				buildOpNameV1:      staticName("websocket.connection"),
+			},
+		},
+	},
 	PackageUptraceBun: {
 		TracedPackage: "github.com/uptrace/bun",
 		naming: map[Component]componentNames{
@@ -777,6 +804,9 @@ var packages = map[Package]PackageInfo{
 	PackageOS: {
 		TracedPackage: "os",
 	},
+	PackageOSExec: {
+		TracedPackage: "os/exec",
+	},
 	PackageEmickleiGoRestful: {
 		TracedPackage: "github.com/emicklei/go-restful",
 		EnvVarPrefix:  "RESTFUL",
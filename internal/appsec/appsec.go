@@ -35,6 +35,13 @@ func RASPEnabled() bool {
 	return activeAppSec != nil && activeAppSec.started && activeAppSec.cfg.RASP
 }
 
+// IASTEnabled returns true when DD_IAST_ENABLED=true. Granted that AppSec is enabled.
+func IASTEnabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return activeAppSec != nil && activeAppSec.started && activeAppSec.cfg.IAST
+}
+
 // Start AppSec when enabled is enabled by both using the appsec build tag and
 // setting the environment variable DD_APPSEC_ENABLED to true.
 func Start(opts ...config.StartOption) {
@@ -147,6 +154,11 @@ type appsec struct {
 	features   []listener.Feature
 	featuresMu sync.Mutex
 	started    bool
+	// rulesMu serializes the updates of the security rules, coming from remote config or the local rules directory
+	rulesMu         sync.Mutex
+	rulesDirWatcher *rulesDirWatcher
+	// rulesDirUnreadable is whether the local rules directory could not be read the last time it was polled
+	rulesDirUnreadable bool
 }
 
 func newAppSec(cfg *config.Config) *appsec {
@@ -169,8 +181,11 @@ func (a *appsec) start(telemetry *appsecTelemetry) error {
 		log.Error("appsec: non-critical error while loading libddwaf: %v", err)
 	}
 
+	a.loadRulesDir()
+
 	// Register dyngo listeners
 	if err := a.SwapRootOperation(); err != nil {
+		a.stopRulesDirWatcher()
 		return err
 	}
 
@@ -198,6 +213,7 @@ func (a *appsec) stop() {
 	a.started = false
 	// Disable RC blocking first so that the following is guaranteed not to be concurrent anymore.
 	a.disableRCBlocking()
+	a.stopRulesDirWatcher()
 
 	a.featuresMu.Lock()
 	defer a.featuresMu.Unlock()
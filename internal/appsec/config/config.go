@@ -7,6 +7,7 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"time"
@@ -42,8 +43,41 @@ const (
 	EnvEnabled = "DD_APPSEC_ENABLED"
 	// EnvSCAEnabled controls ASM Software Composition Analysis (SCA)'s enablement.
 	EnvSCAEnabled = "DD_APPSEC_SCA_ENABLED"
+	// EnvIASTEnabled controls ASM Interactive Application Security Testing (IAST)'s enablement.
+	EnvIASTEnabled = "DD_IAST_ENABLED"
 )
 
+// The following environment variables configure the automatic parsing of HTTP request bodies.
+const (
+	// EnvBodyParsingEnabled controls whether HTTP request bodies are automatically buffered and parsed so that they
+	// can be monitored by the WAF.
+	EnvBodyParsingEnabled = "DD_APPSEC_BODY_PARSING_ENABLED"
+	// EnvBodyParsingSizeLimit is the maximum number of bytes of an HTTP request body that are buffered and parsed.
+	EnvBodyParsingSizeLimit = "DD_APPSEC_BODY_PARSING_SIZE_LIMIT"
+	// EnvResponseBodyParsingEnabled controls whether JSON HTTP response bodies are captured and parsed so that they
+	// can be monitored by the WAF and used by API Security to extract response schemas.
+	EnvResponseBodyParsingEnabled = "DD_APPSEC_RESPONSE_BODY_PARSING_ENABLED"
+	// EnvResponseBodyParsingSizeLimit is the maximum number of bytes of an HTTP response body that are captured.
+	EnvResponseBodyParsingSizeLimit = "DD_APPSEC_RESPONSE_BODY_PARSING_SIZE_LIMIT"
+	// EnvResponseBodyParsingSampleRate is the ratio of HTTP responses, between 0 and 1, whose body is captured.
+	EnvResponseBodyParsingSampleRate = "DD_APPSEC_RESPONSE_BODY_PARSING_SAMPLE_RATE"
+)
+
+// EnvRulesDir is the path of a local directory of JSON rules files, such as custom rules, exclusions or IP and user
+// denylists, that are merged with the security rules and hot reloaded when they change.
+const EnvRulesDir = "DD_APPSEC_RULES_DIR"
+
+// EnvSignalsOutput is the local sink where the security signals of the WAF are exported as JSON lines, in addition
+// to the span tags: `stdout`, `stderr`, `syslog` or the path of a file the signals are appended to.
+const EnvSignalsOutput = "DD_APPSEC_SIGNALS_OUTPUT"
+
+// DefaultBodyParsingSizeLimit is the default value of [EnvBodyParsingSizeLimit] and
+// [EnvResponseBodyParsingSizeLimit].
+const DefaultBodyParsingSizeLimit = 128 << 10
+
+// DefaultResponseBodyParsingSampleRate is the default value of [EnvResponseBodyParsingSampleRate].
+const DefaultResponseBodyParsingSampleRate = 0.1
+
 // StartOption is used to customize the AppSec configuration when invoked with appsec.Start()
 type StartOption func(c *StartConfig)
 
@@ -57,6 +91,12 @@ type StartConfig struct {
 	MetaStructAvailable bool
 
 	APISecOptions []internal.APISecOption
+
+	// SignalsWriter is the writer the security signals are exported to. It takes precedence over
+	// [EnvSignalsOutput].
+	SignalsWriter io.Writer
+	// SpanTraceID returns the trace ID of the service entry span given to AppSec, or an empty string when unknown.
+	SpanTraceID func(span any) string
 }
 
 type EnablementMode int8
@@ -134,11 +174,28 @@ func WithAPISecOptions(opts ...internal.APISecOption) StartOption {
 	}
 }
 
+// WithSignalsWriter sets the writer the security signals of the WAF are exported to as JSON lines.
+func WithSignalsWriter(w io.Writer) StartOption {
+	return func(c *StartConfig) {
+		c.SignalsWriter = w
+	}
+}
+
+// WithSpanTraceID sets the function used to get the trace ID of the service entry spans, which is included in the
+// exported security signals.
+func WithSpanTraceID(f func(span any) string) StartOption {
+	return func(c *StartConfig) {
+		c.SpanTraceID = f
+	}
+}
+
 // Config is the AppSec configuration.
 type Config struct {
 	// rules loaded via the env var DD_APPSEC_RULES. When not set, the builtin rules will be used
 	// and live-updated with remote configuration.
 	RulesManager *RulesManager
+	// RulesDir is the local rules directory set by [EnvRulesDir], if any.
+	RulesDir string
 	// Maximum WAF execution time
 	WAFTimeout time.Duration
 	// AppSec trace rate limit (traces per second).
@@ -150,10 +207,33 @@ type Config struct {
 	// RC is the remote configuration client used to receive product configuration updates. Nil if RC is disabled (default)
 	RC   *remoteconfig.ClientConfig
 	RASP bool
+	// IAST is true when the taint tracking of Interactive Application Security Testing is enabled.
+	IAST bool
 	// SupportedAddresses are the addresses that the AppSec listener will bind to.
 	SupportedAddresses AddressSet
 	// MetaStructAvailable is true if meta struct is supported by the trace agent.
 	MetaStructAvailable bool
+	// BodyParsingSizeLimit is the maximum number of bytes of HTTP request bodies that are buffered and parsed
+	// before the request handler runs. Automatic body parsing is disabled when zero.
+	BodyParsingSizeLimit int
+	// ResponseBodyParsing is the configuration of the capture and parsing of HTTP response bodies.
+	ResponseBodyParsing ResponseBodyParsingConfig
+	// SignalsWriter is the writer the security signals are exported to, if any. It takes precedence over
+	// SignalsOutput.
+	SignalsWriter io.Writer
+	// SignalsOutput is the value of [EnvSignalsOutput].
+	SignalsOutput string
+	// SpanTraceID returns the trace ID of the service entry span given to AppSec, or an empty string when unknown.
+	SpanTraceID func(span any) string
+}
+
+// ResponseBodyParsingConfig is the configuration of the capture and parsing of HTTP response bodies.
+type ResponseBodyParsingConfig struct {
+	// SizeLimit is the maximum number of bytes of HTTP response bodies that are captured. Response body parsing is
+	// disabled when zero.
+	SizeLimit int
+	// SampleRate is the ratio of HTTP responses whose body is captured.
+	SampleRate float64
 }
 
 // AddressSet is a set of WAF addresses.
@@ -214,13 +294,78 @@ func (c *StartConfig) NewConfig() (*Config, error) {
 	}
 
 	return &Config{
-		RulesManager:        r,
-		WAFTimeout:          internal.WAFTimeoutFromEnv(),
-		TraceRateLimit:      int64(internal.RateLimitFromEnv()),
-		Obfuscator:          internal.NewObfuscatorConfig(),
-		APISec:              internal.NewAPISecConfig(c.APISecOptions...),
-		RASP:                internal.RASPEnabled(),
-		RC:                  c.RC,
-		MetaStructAvailable: c.MetaStructAvailable,
+		RulesManager:         r,
+		RulesDir:             os.Getenv(EnvRulesDir),
+		WAFTimeout:           internal.WAFTimeoutFromEnv(),
+		TraceRateLimit:       int64(internal.RateLimitFromEnv()),
+		Obfuscator:           internal.NewObfuscatorConfig(),
+		APISec:               internal.NewAPISecConfig(c.APISecOptions...),
+		RASP:                 internal.RASPEnabled(),
+		IAST:                 IASTEnabledFromEnv(),
+		RC:                   c.RC,
+		MetaStructAvailable:  c.MetaStructAvailable,
+		BodyParsingSizeLimit: BodyParsingSizeLimitFromEnv(),
+		ResponseBodyParsing:  ResponseBodyParsingFromEnv(),
+		SignalsWriter:        c.SignalsWriter,
+		SignalsOutput:        os.Getenv(EnvSignalsOutput),
+		SpanTraceID:          c.SpanTraceID,
 	}, nil
 }
+
+// IASTEnabledFromEnv returns whether the taint tracking of Interactive Application Security Testing is enabled
+// according to [EnvIASTEnabled]. It is disabled by default.
+func IASTEnabledFromEnv() bool {
+	enabled, _, err := parseBoolEnvVar(EnvIASTEnabled)
+	if err != nil {
+		log.Error("appsec: %v", err)
+		return false
+	}
+	return enabled
+}
+
+// BodyParsingSizeLimitFromEnv returns the maximum number of bytes of HTTP request bodies to automatically parse
+// according to [EnvBodyParsingEnabled] and [EnvBodyParsingSizeLimit]. It returns zero when automatic body parsing is
+// disabled, which is the default.
+func BodyParsingSizeLimitFromEnv() int {
+	return bodyParsingSizeLimitFromEnv(EnvBodyParsingEnabled, EnvBodyParsingSizeLimit)
+}
+
+// ResponseBodyParsingFromEnv returns the configuration of the capture and parsing of HTTP response bodies according
+// to [EnvResponseBodyParsingEnabled], [EnvResponseBodyParsingSizeLimit] and [EnvResponseBodyParsingSampleRate].
+// Response body parsing is disabled by default.
+func ResponseBodyParsingFromEnv() ResponseBodyParsingConfig {
+	cfg := ResponseBodyParsingConfig{
+		SizeLimit:  bodyParsingSizeLimitFromEnv(EnvResponseBodyParsingEnabled, EnvResponseBodyParsingSizeLimit),
+		SampleRate: DefaultResponseBodyParsingSampleRate,
+	}
+	if str := os.Getenv(EnvResponseBodyParsingSampleRate); str != "" {
+		rate, err := strconv.ParseFloat(str, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			log.Error("appsec: could not parse %s value `%s` as a sample rate between 0 and 1, using the default value %v", EnvResponseBodyParsingSampleRate, str, DefaultResponseBodyParsingSampleRate)
+		} else {
+			cfg.SampleRate = rate
+		}
+	}
+	return cfg
+}
+
+func bodyParsingSizeLimitFromEnv(enabledEnv, sizeLimitEnv string) int {
+	enabled, _, err := parseBoolEnvVar(enabledEnv)
+	if err != nil {
+		log.Error("appsec: %v", err)
+		return 0
+	}
+	if !enabled {
+		return 0
+	}
+	str := os.Getenv(sizeLimitEnv)
+	if str == "" {
+		return DefaultBodyParsingSizeLimit
+	}
+	limit, err := strconv.Atoi(str)
+	if err != nil || limit <= 0 {
+		log.Error("appsec: could not parse %s value `%s` as a positive integer value, using the default value %d", sizeLimitEnv, str, DefaultBodyParsingSizeLimit)
+		return DefaultBodyParsingSizeLimit
+	}
+	return limit
+}
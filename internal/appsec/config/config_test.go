@@ -64,3 +64,116 @@ func TestSCAEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestBodyParsingSizeLimitFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		env      map[string]string
+		expected int
+	}{
+		{
+			name:     "undefined",
+			expected: 0,
+		},
+		{
+			name:     "disabled",
+			env:      map[string]string{EnvBodyParsingEnabled: "false", EnvBodyParsingSizeLimit: "1024"},
+			expected: 0,
+		},
+		{
+			name:     "enabled",
+			env:      map[string]string{EnvBodyParsingEnabled: "true"},
+			expected: DefaultBodyParsingSizeLimit,
+		},
+		{
+			name:     "size-limit",
+			env:      map[string]string{EnvBodyParsingEnabled: "true", EnvBodyParsingSizeLimit: "1024"},
+			expected: 1024,
+		},
+		{
+			name:     "invalid-size-limit",
+			env:      map[string]string{EnvBodyParsingEnabled: "true", EnvBodyParsingSizeLimit: "-1"},
+			expected: DefaultBodyParsingSizeLimit,
+		},
+		{
+			name:     "parsing error",
+			env:      map[string]string{EnvBodyParsingEnabled: "yes please"},
+			expected: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if got := BodyParsingSizeLimitFromEnv(); got != tc.expected {
+				t.Fatalf("expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIASTEnabledFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		env      string
+		expected bool
+	}{
+		{name: "undefined", expected: false},
+		{name: "enabled", env: "true", expected: true},
+		{name: "disabled", env: "false", expected: false},
+		{name: "parsing error", env: "yes please", expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env != "" {
+				t.Setenv(EnvIASTEnabled, tc.env)
+			}
+			if got := IASTEnabledFromEnv(); got != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestResponseBodyParsingFromEnv(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		env      map[string]string
+		expected ResponseBodyParsingConfig
+	}{
+		{
+			name:     "undefined",
+			expected: ResponseBodyParsingConfig{SampleRate: DefaultResponseBodyParsingSampleRate},
+		},
+		{
+			name:     "enabled",
+			env:      map[string]string{EnvResponseBodyParsingEnabled: "true"},
+			expected: ResponseBodyParsingConfig{SizeLimit: DefaultBodyParsingSizeLimit, SampleRate: DefaultResponseBodyParsingSampleRate},
+		},
+		{
+			name: "configured",
+			env: map[string]string{
+				EnvResponseBodyParsingEnabled:    "true",
+				EnvResponseBodyParsingSizeLimit:  "1024",
+				EnvResponseBodyParsingSampleRate: "0.5",
+			},
+			expected: ResponseBodyParsingConfig{SizeLimit: 1024, SampleRate: 0.5},
+		},
+		{
+			name: "invalid-sample-rate",
+			env: map[string]string{
+				EnvResponseBodyParsingEnabled:    "true",
+				EnvResponseBodyParsingSampleRate: "2",
+			},
+			expected: ResponseBodyParsingConfig{SizeLimit: DefaultBodyParsingSizeLimit, SampleRate: DefaultResponseBodyParsingSampleRate},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if got := ResponseBodyParsingFromEnv(); got != tc.expected {
+				t.Fatalf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalRulesPathPrefix is the prefix of the RulesManager edit paths and base path of the fragments loaded from the
+// local rules directory set by [EnvRulesDir].
+const LocalRulesPathPrefix = "local/"
+
+// RulesDirState identifies the state of the files of a local rules directory, so that changes can be detected
+// without reading them.
+type RulesDirState map[string]rulesFileState
+
+type rulesFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Equal returns true when both states are the same.
+func (s RulesDirState) Equal(other RulesDirState) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for name, file := range s {
+		if otherFile, ok := other[name]; !ok || !file.modTime.Equal(otherFile.modTime) || file.size != otherFile.size {
+			return false
+		}
+	}
+	return true
+}
+
+// StatRulesDir returns the current state of the JSON files of the given local rules directory.
+func StatRulesDir(dir string) (RulesDirState, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	state := make(RulesDirState, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		state[entry.Name()] = rulesFileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return state, nil
+}
+
+// ReadRulesDir reads the JSON files of the given local rules directory as rules fragments, indexed by their path
+// in the RulesManager, i.e. their file name prefixed with [LocalRulesPathPrefix]. An error is returned when any of
+// the files is not a valid rules fragment, so that partial rules directories are never applied.
+func ReadRulesDir(dir string) (map[string]RulesFragment, error) {
+	state, err := StatRulesDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fragments := make(map[string]RulesFragment, len(state))
+	for name := range state {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var f RulesFragment
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("invalid rules file %s: %w", name, err)
+		}
+		fragments[LocalRulesPathPrefix+name] = f
+	}
+	return fragments, nil
+}
+
+// SetLocalRules replaces the rules fragments previously loaded from the local rules directory with the given ones.
+// Fragments holding a full ruleset replace the base rules, which is restored to the default rules once they are
+// removed, while the other fragments are merged with the base rules as edits, like the ASM remote configurations.
+// An error is returned when more than one fragment holds a full ruleset.
+func (r *RulesManager) SetLocalRules(fragments map[string]RulesFragment) error {
+	var basePath string
+	for path, f := range fragments {
+		if len(f.Rules) == 0 {
+			continue
+		}
+		if basePath != "" {
+			return fmt.Errorf("more than one full ruleset found in the local rules: %s and %s", basePath, path)
+		}
+		basePath = path
+	}
+
+	for path := range r.Edits {
+		if strings.HasPrefix(path, LocalRulesPathPrefix) {
+			r.RemoveEdit(path)
+		}
+	}
+	for path, f := range fragments {
+		if path != basePath {
+			r.AddEdit(path, f)
+		}
+	}
+
+	switch {
+	case basePath != "":
+		r.ChangeBase(fragments[basePath], basePath)
+	case strings.HasPrefix(r.BasePath, LocalRulesPathPrefix):
+		r.ChangeBase(DefaultRulesFragment(), "")
+	}
+	return nil
+}
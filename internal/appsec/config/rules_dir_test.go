@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadRulesDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "custom.json"), []byte(`{"custom_rules":[{"id":"custom-1"}]}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`not a rules file`), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub.json"), 0o700))
+
+	state, err := StatRulesDir(dir)
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+
+	fragments, err := ReadRulesDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]RulesFragment{
+		"local/custom.json": {CustomRules: []any{map[string]any{"id": "custom-1"}}},
+	}, fragments)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte(`{`), 0o600))
+	newState, err := StatRulesDir(dir)
+	require.NoError(t, err)
+	require.False(t, state.Equal(newState))
+	_, err = ReadRulesDir(dir)
+	require.Error(t, err)
+}
+
+func TestRulesManagerSetLocalRules(t *testing.T) {
+	custom := RulesFragment{CustomRules: []any{map[string]any{"id": "custom-1"}}}
+	base := RulesFragment{Version: "2.2", Rules: []any{map[string]any{"id": "rule-1"}}}
+
+	r, err := NewRulesManager(nil)
+	require.NoError(t, err)
+	r.AddEdit("datadog/2/ASM/config", RulesFragment{})
+
+	t.Run("edits", func(t *testing.T) {
+		require.NoError(t, r.SetLocalRules(map[string]RulesFragment{"local/custom.json": custom}))
+		r.Compile()
+		require.Contains(t, r.Edits, "datadog/2/ASM/config")
+		require.Contains(t, r.Edits, "local/custom.json")
+		require.Contains(t, r.Latest.CustomRules, custom.CustomRules[0])
+	})
+
+	t.Run("base", func(t *testing.T) {
+		require.NoError(t, r.SetLocalRules(map[string]RulesFragment{"local/base.json": base}))
+		r.Compile()
+		require.Equal(t, "local/base.json", r.BasePath)
+		require.Equal(t, base.Rules, r.Latest.Rules)
+		require.NotContains(t, r.Edits, "local/custom.json")
+		require.NotContains(t, r.Edits, "local/base.json")
+	})
+
+	t.Run("base-removal", func(t *testing.T) {
+		require.NoError(t, r.SetLocalRules(nil))
+		r.Compile()
+		require.Empty(t, r.BasePath)
+		require.Equal(t, DefaultRulesFragment().Rules, r.Latest.Rules)
+		require.Contains(t, r.Edits, "datadog/2/ASM/config")
+	})
+
+	t.Run("multiple-bases", func(t *testing.T) {
+		require.Error(t, r.SetLocalRules(map[string]RulesFragment{"local/a.json": base, "local/b.json": base}))
+	})
+}
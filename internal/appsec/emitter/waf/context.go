@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/internal/stacktrace"
 
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/trace"
 )
 
@@ -39,6 +40,8 @@ type (
 		stacks []*stacktrace.Event
 		// derivatives is where we store any span tags generated by the WAF over the course of the request.
 		derivatives map[string]any
+		// clientIP and userID are the client IP and user ID addresses sent to the WAF over the course of the request.
+		clientIP, userID string
 		// supportedAddresses is the set of addresses supported by the WAF.
 		supportedAddresses config.AddressSet
 		// metrics the place that manages reporting for the current execution
@@ -166,10 +169,52 @@ func (op *ContextOperation) StackTraces() []*stacktrace.Event {
 	return slices.Clone(op.stacks)
 }
 
+// absorbUser keeps the client IP and user ID found in the given persistent addresses, even if the WAF does not
+// support them, so that they can be reported along with the security events of the request.
+func (op *ContextOperation) absorbUser(persistent map[string]any) {
+	clientIP, _ := persistent[addresses.ClientIPAddr].(string)
+	userID, _ := persistent[addresses.UserIDAddr].(string)
+	if clientIP == "" && userID == "" {
+		return
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if clientIP != "" {
+		op.clientIP = clientIP
+	}
+	if userID != "" {
+		op.userID = userID
+	}
+}
+
+// ClientIP returns the client IP address of the request, if it was sent to the WAF.
+func (op *ContextOperation) ClientIP() string {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.clientIP
+}
+
+// UserID returns the ID of the authenticated user of the request, if it was sent to the WAF.
+func (op *ContextOperation) UserID() string {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.userID
+}
+
 func (op *ContextOperation) OnEvent(event RunEvent) {
 	op.Run(event.Operation, event.RunAddressData)
 }
 
 func (op *ContextOperation) SetSupportedAddresses(addrs config.AddressSet) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
 	op.supportedAddresses = addrs
 }
+
+// SupportsAnyAddress returns whether the security rules of the request monitor any of the given addresses.
+func (op *ContextOperation) SupportsAnyAddress(addrs ...string) bool {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.supportedAddresses.AnyOf(addrs...)
+}
@@ -59,10 +59,12 @@ type HandleMetrics struct {
 }
 
 var baseRASPTags = map[addresses.RASPRuleType][]string{
-	addresses.RASPRuleTypeLFI:  {"rule_type:" + string(addresses.RASPRuleTypeLFI)},
-	addresses.RASPRuleTypeSSRF: {"rule_type:" + string(addresses.RASPRuleTypeSSRF)},
-	addresses.RASPRuleTypeSQLI: {"rule_type:" + string(addresses.RASPRuleTypeSQLI)},
-	addresses.RASPRuleTypeCMDI: {"rule_type:" + string(addresses.RASPRuleTypeCMDI), "rule_variant:exec"},
+	addresses.RASPRuleTypeLFI:    {"rule_type:" + string(addresses.RASPRuleTypeLFI)},
+	addresses.RASPRuleTypeSSRF:   {"rule_type:" + string(addresses.RASPRuleTypeSSRF)},
+	addresses.RASPRuleTypeSQLI:   {"rule_type:" + string(addresses.RASPRuleTypeSQLI)},
+	addresses.RASPRuleTypeCMDI:   {"rule_type:" + string(addresses.RASPRuleTypeCMDI), "rule_variant:exec"},
+	addresses.RASPRuleTypeSHI:    {"rule_type:" + string(addresses.RASPRuleTypeCMDI), "rule_variant:shell"},
+	addresses.RASPRuleTypeNoSQLI: {"rule_type:" + string(addresses.RASPRuleTypeNoSQLI)},
 }
 
 // NewMetricsInstance creates a new HandleMetrics struct and submit the `waf.init` or `waf.updates` metric. To be called with the raw results of the WAF handle initialization
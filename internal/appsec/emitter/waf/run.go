@@ -29,6 +29,8 @@ func (op *ContextOperation) Run(eventReceiver dyngo.Operation, addrs waf.RunAddr
 		return
 	}
 
+	op.absorbUser(addrs.Persistent)
+
 	// Remove unsupported addresses in case the listener was registered but some addresses are still unsupported
 	// Technically the WAF does this step for us but doing this check before calling the WAF makes us skip encoding huge
 	// values that may be discarded by the WAF afterward.
@@ -13,11 +13,15 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/graphqlsec"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/grpcsec"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/httpsec"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/iast"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/messagesec"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/nosqlsec"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/ossec"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/sqlsec"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/trace"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/usersec"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/waf"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener/websocketsec"
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 )
 
@@ -27,10 +31,15 @@ var features = []listener.NewFeature{
 	httpsec.NewHTTPSecFeature,
 	grpcsec.NewGRPCSecFeature,
 	graphqlsec.NewGraphQLSecFeature,
+	messagesec.NewMessageSecFeature,
+	websocketsec.NewWebSocketSecFeature,
 	usersec.NewUserSecFeature,
 	sqlsec.NewSQLSecFeature,
+	nosqlsec.NewNoSQLSecFeature,
 	ossec.NewOSSecFeature,
+	ossec.NewCMDiFeature,
 	httpsec.NewSSRFProtectionFeature,
+	iast.NewIASTFeature,
 }
 
 func (a *appsec) SwapRootOperation() error {
@@ -6,6 +6,7 @@
 package httpsec
 
 import (
+	"math/rand/v2"
 	"strings"
 
 	"github.com/DataDog/appsec-internal-go/apisec"
@@ -22,6 +23,10 @@ import (
 
 type Feature struct {
 	APISec appsec.APISecConfig
+	// BodyParsingSizeLimit is the maximum number of bytes of the request bodies to parse. Zero disables body parsing.
+	BodyParsingSizeLimit int
+	// ResponseBodyParsing is the configuration of the response body parsing. A zero size limit disables it.
+	ResponseBodyParsing config.ResponseBodyParsingConfig
 }
 
 func (*Feature) String() string {
@@ -46,6 +51,12 @@ func NewHTTPSecFeature(config *config.Config, rootOp dyngo.Operation) (listener.
 	feature := &Feature{
 		APISec: config.APISec,
 	}
+	if config.SupportedAddresses.AnyOf(addresses.ServerRequestBodyAddr) {
+		feature.BodyParsingSizeLimit = config.BodyParsingSizeLimit
+	}
+	if config.SupportedAddresses.AnyOf(addresses.ServerResponseBodyAddr) {
+		feature.ResponseBodyParsing = config.ResponseBodyParsing
+	}
 
 	dyngo.On(rootOp, feature.OnRequest)
 	dyngo.OnFinish(rootOp, feature.OnResponse)
@@ -73,6 +84,13 @@ func (feature *Feature) OnRequest(op *httpsec.HandlerOperation, args httpsec.Han
 			WithClientIP(ip).
 			Build(),
 	)
+
+	if feature.BodyParsingSizeLimit > 0 {
+		dyngo.EmitData(op, httpsec.RequestBodyParsing{SizeLimit: feature.BodyParsingSizeLimit})
+	}
+	if feature.ResponseBodyParsing.SizeLimit > 0 && rand.Float64() < feature.ResponseBodyParsing.SampleRate {
+		dyngo.EmitData(op, httpsec.ResponseBodyParsing{SizeLimit: feature.ResponseBodyParsing.SizeLimit})
+	}
 }
 
 func (feature *Feature) OnResponse(op *httpsec.HandlerOperation, resp httpsec.HandlerOperationRes) {
@@ -81,7 +99,8 @@ func (feature *Feature) OnResponse(op *httpsec.HandlerOperation, resp httpsec.Ha
 
 	builder := addresses.NewAddressesBuilder().
 		WithResponseHeadersNoCookies(headers).
-		WithResponseStatus(resp.StatusCode)
+		WithResponseStatus(resp.StatusCode).
+		WithResponseBody(resp.Body)
 
 	if feature.shouldExtractShema(op, resp.StatusCode) {
 		builder = builder.ExtractSchema()
@@ -0,0 +1,295 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package iast
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/httpsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/ossec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/sqlsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/iast"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
+)
+
+// The vulnerability types reported when tainted data reaches a sink.
+const (
+	VulnerabilitySQLInjection     = "SQL_INJECTION"
+	VulnerabilityPathTraversal    = "PATH_TRAVERSAL"
+	VulnerabilityCommandInjection = "COMMAND_INJECTION"
+	VulnerabilitySSRF             = "SSRF"
+)
+
+const (
+	// maxVulnerabilities is the maximum number of vulnerabilities reported per request.
+	maxVulnerabilities = 10
+	// maxBodyDepth is the maximum depth of the parsed request body walked to taint its strings.
+	maxBodyDepth = 16
+	// maxLocationDepth is the maximum number of stack frames looked at to find the location of a sink.
+	maxLocationDepth = 32
+)
+
+type (
+	// report is the value of the `_dd.iast.json` span tag.
+	report struct {
+		Sources         []iast.Source   `json:"sources"`
+		Vulnerabilities []vulnerability `json:"vulnerabilities"`
+	}
+
+	vulnerability struct {
+		Type     string   `json:"type"`
+		Evidence evidence `json:"evidence"`
+		Location location `json:"location"`
+		// source is the source of the tainted data, which gets indexed in the report sources.
+		source iast.Source
+	}
+
+	evidence struct {
+		Value string `json:"value"`
+		// Source is the index of the source of the tainted data in the report sources.
+		Source int `json:"source"`
+	}
+
+	// location is the location of the user code calling the sink.
+	location struct {
+		Path   string `json:"path,omitempty"`
+		Line   int    `json:"line,omitempty"`
+		Method string `json:"method,omitempty"`
+	}
+)
+
+// add adds the vulnerability to the report unless it is a duplicate.
+func (r *report) add(v vulnerability) {
+	if len(r.Vulnerabilities) >= maxVulnerabilities {
+		return
+	}
+	for _, reported := range r.Vulnerabilities {
+		if reported.Type == v.Type && reported.Location == v.Location && reported.Evidence.Value == v.Evidence.Value {
+			return
+		}
+	}
+	v.Evidence.Source = -1
+	for i, src := range r.Sources {
+		if src == v.source {
+			v.Evidence.Source = i
+			break
+		}
+	}
+	if v.Evidence.Source == -1 {
+		v.Evidence.Source = len(r.Sources)
+		r.Sources = append(r.Sources, v.source)
+	}
+	r.Vulnerabilities = append(r.Vulnerabilities, v)
+}
+
+type Feature struct{}
+
+func (*Feature) String() string {
+	return "IAST"
+}
+
+func (*Feature) Stop() {}
+
+func NewIASTFeature(cfg *config.Config, rootOp dyngo.Operation) (listener.Feature, error) {
+	if !cfg.IAST {
+		return nil, nil
+	}
+
+	feature := &Feature{}
+	dyngo.On(rootOp, feature.OnRequest)
+	dyngo.On(rootOp, feature.OnSQL)
+	dyngo.On(rootOp, feature.OnOpen)
+	dyngo.On(rootOp, feature.OnExec)
+	dyngo.On(rootOp, feature.OnRoundTrip)
+	return feature, nil
+}
+
+// OnRequest taints the request data for the duration of the request, in a
+// scope held by the handler operation, and reports the vulnerabilities
+// detected in the meantime on the service entry span when the request ends.
+func (*Feature) OnRequest(op *httpsec.HandlerOperation, args httpsec.HandlerOperationArgs) {
+	scope := iast.NewScope()
+	dyngo.EmitData(op, scope)
+	taintRequest(scope, args)
+
+	// The parsed request body can be provided by the SDK or the body parsing
+	// while the handler runs.
+	dyngo.OnData(op, func(e waf.RunEvent) {
+		if body, ok := e.Persistent[addresses.ServerRequestBodyAddr]; ok {
+			taintBody(scope, "", reflect.ValueOf(body), 0)
+		}
+	})
+
+	var (
+		mu sync.Mutex
+		r  report
+	)
+	dyngo.OnData(op, func(v vulnerability) {
+		mu.Lock()
+		defer mu.Unlock()
+		r.add(v)
+	})
+
+	op.SetTag("_dd.iast.enabled", 1)
+	dyngo.OnFinish(op, func(op *httpsec.HandlerOperation, _ httpsec.HandlerOperationRes) {
+		scope.Close()
+		if scope.Truncated() {
+			log.Debug("appsec: iast: the maximum number of tainted values per request was reached: some vulnerabilities may not be reported")
+			telemetry.Count(telemetry.NamespaceIAST, "request.tainted.truncated", nil).Submit(1)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(r.Vulnerabilities) > 0 {
+			op.SetSerializableTag("_dd.iast.json", r)
+		}
+	})
+}
+
+func (*Feature) OnSQL(op *sqlsec.SQLOperation, args sqlsec.SQLOperationArgs) {
+	reportTainted(op, VulnerabilitySQLInjection, args.Query, args.Query)
+}
+
+func (*Feature) OnOpen(op *ossec.OpenOperation, args ossec.OpenOperationArgs) {
+	reportTainted(op, VulnerabilityPathTraversal, args.Path, args.Path)
+}
+
+func (*Feature) OnExec(op *ossec.ExecOperation, args ossec.ExecOperationArgs) {
+	reportTainted(op, VulnerabilityCommandInjection, strings.Join(args.Args, " "), append([]string{args.Path}, args.Args...)...)
+}
+
+func (*Feature) OnRoundTrip(op *httpsec.RoundTripOperation, args httpsec.RoundTripOperationArgs) {
+	values := []string{args.URL}
+	if u := args.ParsedURL; u != nil {
+		values = append(values, u.Host, u.Path, u.RawQuery, u.Opaque)
+	}
+	reportTainted(op, VulnerabilitySSRF, args.URL, values...)
+}
+
+// reportTainted reports a vulnerability of the given type to the request when
+// any of the given values reaching the sink is tainted.
+func reportTainted(op dyngo.Operation, vulnType string, evidenceValue string, values ...string) {
+	scope := iast.OperationScope(op)
+	if scope == nil {
+		return
+	}
+	for _, value := range values {
+		source, ok := scope.SourceOf(value)
+		if !ok {
+			continue
+		}
+		dyngo.EmitData(op, vulnerability{
+			Type:     vulnType,
+			Evidence: evidence{Value: evidenceValue},
+			Location: sinkLocation(),
+			source:   source,
+		})
+		return
+	}
+}
+
+func taintRequest(scope *iast.Scope, args httpsec.HandlerOperationArgs) {
+	scope.Taint(args.RequestURI, iast.Source{Origin: iast.OriginURI, Value: args.RequestURI})
+	for name, values := range args.QueryParams {
+		for _, v := range values {
+			scope.Taint(v, iast.Source{Origin: iast.OriginParameter, Name: name, Value: v})
+		}
+	}
+	for name, v := range args.PathParams {
+		scope.Taint(v, iast.Source{Origin: iast.OriginPathParameter, Name: name, Value: v})
+	}
+	for name, values := range args.Headers {
+		for _, v := range values {
+			scope.Taint(v, iast.Source{Origin: iast.OriginHeader, Name: name, Value: v})
+		}
+	}
+	for name, values := range args.Cookies {
+		for _, v := range values {
+			scope.Taint(v, iast.Source{Origin: iast.OriginCookie, Name: name, Value: v})
+		}
+	}
+}
+
+// taintBody taints the strings of the given parsed request body.
+func taintBody(scope *iast.Scope, name string, v reflect.Value, depth int) {
+	if depth > maxBodyDepth || !v.IsValid() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		taintBody(scope, name, v.Elem(), depth+1)
+	case reflect.String:
+		s := v.String()
+		scope.Taint(s, iast.Source{Origin: iast.OriginBody, Name: name, Value: s})
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			taintBody(scope, name, v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key := name
+			if iter.Key().Kind() == reflect.String {
+				key = iter.Key().String()
+			}
+			taintBody(scope, key, iter.Value(), depth+1)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if field := t.Field(i); field.IsExported() {
+				taintBody(scope, field.Name, v.Field(i), depth+1)
+			}
+		}
+	}
+}
+
+// sinkLocation returns the location of the user code calling the sink, which
+// is the first stack frame outside of dd-trace-go and of the standard library.
+func sinkLocation() location {
+	pcs := make([]uintptr, maxLocationDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if isUserFrame(frame) {
+			return location{Path: frame.File, Line: frame.Line, Method: frame.Function}
+		}
+		if !more {
+			return location{}
+		}
+	}
+}
+
+var internalSymbolPrefixes = []string{
+	"github.com/DataDog/dd-trace-go/",
+	"github.com/DataDog/orchestrion/",
+	"gopkg.in/DataDog/dd-trace-go",
+}
+
+func isUserFrame(frame runtime.Frame) bool {
+	if frame.Function == "" || frame.File == "<generated>" {
+		return false
+	}
+	for _, prefix := range internalSymbolPrefixes {
+		if strings.HasPrefix(frame.Function, prefix) {
+			return false
+		}
+	}
+	// Standard library packages have no dot in their first path element
+	pkg, _, _ := strings.Cut(frame.Function, "/")
+	if !strings.Contains(frame.Function, "/") {
+		pkg, _, _ = strings.Cut(frame.Function, ".")
+	}
+	return pkg == "main" || strings.Contains(pkg, ".")
+}
@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package iast
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/iast"
+)
+
+func TestReport(t *testing.T) {
+	param := iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"}
+	header := iast.Source{Origin: iast.OriginHeader, Name: "x-id", Value: "2"}
+
+	var r report
+	r.add(vulnerability{Type: VulnerabilitySQLInjection, Evidence: evidence{Value: "SELECT 1"}, source: param})
+	r.add(vulnerability{Type: VulnerabilitySQLInjection, Evidence: evidence{Value: "SELECT 1"}, source: param})
+	r.add(vulnerability{Type: VulnerabilityPathTraversal, Evidence: evidence{Value: "/tmp/2"}, source: header})
+	r.add(vulnerability{Type: VulnerabilityCommandInjection, Evidence: evidence{Value: "echo 1"}, source: param})
+
+	require.Equal(t, []iast.Source{param, header}, r.Sources)
+	require.Len(t, r.Vulnerabilities, 3)
+	require.Equal(t, 0, r.Vulnerabilities[0].Evidence.Source)
+	require.Equal(t, 1, r.Vulnerabilities[1].Evidence.Source)
+	require.Equal(t, 0, r.Vulnerabilities[2].Evidence.Source)
+
+	for i := 0; i < 2*maxVulnerabilities; i++ {
+		r.add(vulnerability{Type: VulnerabilitySSRF, Evidence: evidence{Value: string(rune('a' + i))}, source: param})
+	}
+	require.Len(t, r.Vulnerabilities, maxVulnerabilities)
+}
+
+func TestIsUserFrame(t *testing.T) {
+	for _, tc := range []struct {
+		function string
+		user     bool
+	}{
+		{function: "main.handler", user: true},
+		{function: "main.main.func1", user: true},
+		{function: "github.com/acme/app/users.(*Store).Find", user: true},
+		{function: "database/sql.(*DB).QueryContext", user: false},
+		{function: "os.OpenFile", user: false},
+		{function: "net/http.HandlerFunc.ServeHTTP", user: false},
+		{function: "github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/sqlsec.ProtectSQLOperation", user: false},
+		{function: "github.com/DataDog/dd-trace-go/contrib/database/sql/v2.(*TracedConn).QueryContext", user: false},
+	} {
+		t.Run(tc.function, func(t *testing.T) {
+			require.Equal(t, tc.user, isUserFrame(runtime.Frame{Function: tc.function, File: "file.go"}))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package messagesec
+
+import (
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/messagesec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener"
+)
+
+type Feature struct{}
+
+func (*Feature) String() string {
+	return "Message Queue Consumer Security"
+}
+
+func (*Feature) Stop() {}
+
+func NewMessageSecFeature(config *config.Config, rootOp dyngo.Operation) (listener.Feature, error) {
+	if !config.SupportedAddresses.AnyOf(
+		addresses.ServerRequestHeadersNoCookiesAddr,
+		addresses.ServerRequestPathParamsAddr,
+		addresses.ServerRequestBodyAddr) {
+		return nil, nil
+	}
+
+	feature := &Feature{}
+	dyngo.On(rootOp, feature.OnStart)
+	return feature, nil
+}
+
+// OnStart runs the WAF with the consumed message, whose headers, key and value are respectively inspected as the
+// request headers, the `key` path parameter and the request body.
+func (*Feature) OnStart(op *messagesec.ConsumeOperation, args messagesec.ConsumeOperationArgs) {
+	builder := addresses.NewAddressesBuilder().
+		WithHeadersNoCookies(normalizeHeaders(args.Headers))
+	if args.Key != "" {
+		builder = builder.WithPathParams(map[string]string{"key": args.Key})
+	}
+	if args.Value != nil {
+		builder = builder.WithRequestBody(args.Value)
+	}
+	op.Run(op, builder.Build())
+}
+
+// normalizeHeaders returns the given message headers with lower-cased names, as expected by the security rules.
+func normalizeHeaders(headers map[string][]string) map[string][]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	normalized := make(map[string][]string, len(headers))
+	for k, v := range headers {
+		k = strings.ToLower(k)
+		normalized[k] = append(normalized[k], v...)
+	}
+	return normalized
+}
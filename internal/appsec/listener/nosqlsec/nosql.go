@@ -0,0 +1,44 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package nosqlsec
+
+import (
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/nosqlsec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener"
+)
+
+type Feature struct{}
+
+func (*Feature) String() string {
+	return "NoSQLi Protection"
+}
+
+func (*Feature) Stop() {}
+
+// NewNoSQLSecFeature enables the NoSQL injection protection when the security rules use the
+// `server.db.nosql.query` address, which no rule of the default ruleset does yet.
+func NewNoSQLSecFeature(cfg *config.Config, rootOp dyngo.Operation) (listener.Feature, error) {
+	if !cfg.RASP || !cfg.SupportedAddresses.AnyOf(addresses.ServerDBNoSQLQueryAddr) {
+		return nil, nil
+	}
+
+	feature := &Feature{}
+	dyngo.On(rootOp, feature.OnStart)
+	return feature, nil
+}
+
+func (*Feature) OnStart(op *nosqlsec.NoSQLOperation, args nosqlsec.NoSQLOperationArgs) {
+	dyngo.EmitData(op, waf.RunEvent{
+		Operation: op,
+		RunAddressData: addresses.NewAddressesBuilder().
+			WithDBNoSQLQuery(args.Query).
+			Build(),
+	})
+}
@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ossec
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/ossec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/listener"
+)
+
+type CMDiFeature struct{}
+
+func (*CMDiFeature) String() string {
+	return "Command Injection Protection"
+}
+
+func (*CMDiFeature) Stop() {}
+
+func NewCMDiFeature(cfg *config.Config, rootOp dyngo.Operation) (listener.Feature, error) {
+	if !cfg.RASP || !cfg.SupportedAddresses.AnyOf(addresses.ServerSysExecCmd, addresses.ServerSysShellCmd) {
+		return nil, nil
+	}
+
+	feature := &CMDiFeature{}
+	dyngo.On(rootOp, feature.OnStart)
+	return feature, nil
+}
+
+func (*CMDiFeature) OnStart(op *ossec.ExecOperation, args ossec.ExecOperationArgs) {
+	dyngo.OnData(op, func(err *events.BlockingSecurityEvent) {
+		dyngo.OnFinish(op, func(_ *ossec.ExecOperation, res ossec.ExecOperationRes) {
+			if res.Err != nil {
+				*res.Err = err
+			}
+		})
+	})
+
+	cmd := args.Args
+	if len(cmd) == 0 {
+		cmd = []string{args.Path}
+	}
+
+	// Shell invocations are inspected for shell injections, as the command
+	// string is interpreted by the shell. Other commands are inspected for
+	// command injections through their arguments.
+	builder := addresses.NewAddressesBuilder()
+	if shellCmd, ok := shellCommand(cmd); ok {
+		builder = builder.WithSysShellCmd(shellCmd)
+	} else {
+		builder = builder.WithSysExecCmd(cmd)
+	}
+
+	dyngo.EmitData(op, waf.RunEvent{
+		Operation:      op,
+		RunAddressData: builder.Build(),
+	})
+}
+
+// shells lists the shells whose -c argument is inspected for shell injections.
+var shells = map[string]struct{}{
+	"sh": {}, "bash": {}, "dash": {}, "zsh": {}, "ksh": {}, "ash": {}, "fish": {}, "csh": {}, "tcsh": {},
+}
+
+// shellShortFlags lists the single-letter options of the shells, which may be
+// combined in a single argument such as -ec or -lc.
+const shellShortFlags = "abcefhiklmnprstuvxBCDEHPT"
+
+// shellCommand returns the command string run by a shell invocation such as
+// `sh -c "convert $file out.png"`, i.e. the first operand following the -c
+// option.
+func shellCommand(cmd []string) (string, bool) {
+	if len(cmd) < 3 {
+		return "", false
+	}
+	if _, ok := shells[filepath.Base(cmd[0])]; !ok {
+		return "", false
+	}
+	var hasC bool
+	for i := 1; i < len(cmd); i++ {
+		arg := cmd[i]
+		switch {
+		case arg == "--":
+			if hasC && i+1 < len(cmd) {
+				return cmd[i+1], true
+			}
+			return "", false
+		case arg == "-o" || arg == "+o" || arg == "-O" || arg == "+O":
+			// skip the name of the option
+			i++
+		case strings.HasPrefix(arg, "--"):
+			// long options such as --norc
+		case isShortFlags(arg):
+			hasC = hasC || (arg[0] == '-' && strings.ContainsRune(arg[1:], 'c'))
+		default:
+			// the first operand is the command string with -c, or the script to run otherwise
+			if !hasC {
+				return "", false
+			}
+			return arg, true
+		}
+	}
+	return "", false
+}
+
+// isShortFlags returns whether arg is a cluster of single-letter shell options,
+// such as -ec or +x.
+func isShortFlags(arg string) bool {
+	if len(arg) < 2 || (arg[0] != '-' && arg[0] != '+') {
+		return false
+	}
+	for _, r := range arg[1:] {
+		if !strings.ContainsRune(shellShortFlags, r) {
+			return false
+		}
+	}
+	return true
+}
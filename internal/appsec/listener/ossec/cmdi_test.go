@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package ossec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShellCommand(t *testing.T) {
+	for _, tc := range []struct {
+		cmd   []string
+		shell string
+		ok    bool
+	}{
+		{cmd: []string{"sh", "-c", "ls /tmp"}, shell: "ls /tmp", ok: true},
+		{cmd: []string{"/bin/bash", "-ec", "ls /tmp"}, shell: "ls /tmp", ok: true},
+		{cmd: []string{"bash", "--norc", "-lc", "ls /tmp"}, shell: "ls /tmp", ok: true},
+		{cmd: []string{"bash", "-c", "-x", "ls /tmp"}, shell: "ls /tmp", ok: true},
+		{cmd: []string{"bash", "-o", "pipefail", "-c", "ls /tmp"}, shell: "ls /tmp", ok: true},
+		{cmd: []string{"sh", "-c", "--", "ls /tmp"}, shell: "ls /tmp", ok: true},
+		{cmd: []string{"sh", "-norc", "script.sh"}},
+		{cmd: []string{"sh", "--rcfile-xc", "script.sh"}},
+		{cmd: []string{"sh", "script.sh", "-c"}},
+		{cmd: []string{"sh", "+c", "ls /tmp"}},
+		{cmd: []string{"python", "-c", "print(1)"}},
+		{cmd: []string{"sh", "-c"}},
+	} {
+		shell, ok := shellCommand(tc.cmd)
+		assert.Equal(t, tc.ok, ok, "%v", tc.cmd)
+		assert.Equal(t, tc.shell, shell, "%v", tc.cmd)
+	}
+}
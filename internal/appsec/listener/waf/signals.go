@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package waf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/emitter/waf"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// redactedValue replaces the values matched by the WAF in the exported signals so that no user data ends up in the
+// signals sink.
+const redactedValue = "<redacted>"
+
+type (
+	// signal is a WAF match exported as a JSON line to the signals sink.
+	signal struct {
+		Timestamp string            `json:"timestamp"`
+		TraceID   string            `json:"trace_id,omitempty"`
+		RuleID    string            `json:"rule_id"`
+		RuleName  string            `json:"rule_name,omitempty"`
+		RuleTags  map[string]any    `json:"rule_tags,omitempty"`
+		Matches   []signalParameter `json:"matches,omitempty"`
+		ClientIP  string            `json:"client_ip,omitempty"`
+		UserID    string            `json:"user_id,omitempty"`
+		Blocked   bool              `json:"blocked"`
+	}
+
+	// signalParameter is a WAF address matched by a rule.
+	signalParameter struct {
+		Address string `json:"address"`
+		KeyPath []any  `json:"key_path,omitempty"`
+		Value   string `json:"value"`
+	}
+
+	// signalSink writes the signals as JSON lines to its writer.
+	signalSink struct {
+		mu     sync.Mutex
+		w      io.Writer
+		closer io.Closer
+	}
+)
+
+// newSignalSink returns the signals sink configured by [config.Config.SignalsWriter] or [config.EnvSignalsOutput],
+// or nil when the export of the signals is disabled.
+func newSignalSink(cfg *config.Config) (*signalSink, error) {
+	if cfg.SignalsWriter != nil {
+		return &signalSink{w: cfg.SignalsWriter}, nil
+	}
+
+	switch cfg.SignalsOutput {
+	case "":
+		return nil, nil
+	case "stdout":
+		return &signalSink{w: os.Stdout}, nil
+	case "stderr":
+		return &signalSink{w: os.Stderr}, nil
+	case "syslog":
+		w, err := newSyslogWriter()
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to syslog: %w", err)
+		}
+		return &signalSink{w: w, closer: w}, nil
+	default:
+		f, err := os.OpenFile(cfg.SignalsOutput, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("could not open the signals file: %w", err)
+		}
+		return &signalSink{w: f, closer: f}, nil
+	}
+}
+
+// write writes every signal as a single JSON line, so that each one of them is a syslog message of its own.
+func (s *signalSink) write(signals []signal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sig := range signals {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(sig); err != nil {
+			log.Debug("appsec: could not encode the security signal: %v", err)
+			continue
+		}
+		if _, err := s.w.Write(buf.Bytes()); err != nil {
+			log.Debug("appsec: could not export the security signal: %v", err)
+		}
+	}
+}
+
+func (s *signalSink) close() {
+	if s.closer == nil {
+		return
+	}
+	if err := s.closer.Close(); err != nil {
+		log.Debug("appsec: could not close the security signals sink: %v", err)
+	}
+}
+
+// exportSignals listens to the given WAF context operation to collect the blocking decision of the request, and
+// exports its WAF events to the sink when it finishes.
+func (s *signalSink) exportSignals(op *waf.ContextOperation, spanTraceID func(any) string) {
+	var blocked atomic.Bool
+	dyngo.OnData(op, func(*events.BlockingSecurityEvent) {
+		blocked.Store(true)
+	})
+
+	dyngo.OnFinish(op, func(*waf.ContextOperation, waf.ContextRes) {
+		wafEvents := op.Events()
+		if len(wafEvents) == 0 {
+			return
+		}
+
+		base := signal{
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+			ClientIP:  op.ClientIP(),
+			UserID:    op.UserID(),
+			Blocked:   blocked.Load(),
+		}
+		if spanTraceID != nil {
+			base.TraceID = spanTraceID(op.Span())
+		}
+		s.write(newSignals(base, wafEvents))
+	})
+}
+
+// newSignals returns the signals of the given WAF events, as returned by the WAF, completing the request information
+// of base.
+func newSignals(base signal, wafEvents []any) []signal {
+	signals := make([]signal, 0, len(wafEvents))
+	for _, event := range wafEvents {
+		event, ok := event.(map[string]any)
+		if !ok {
+			continue
+		}
+		rule, _ := event["rule"].(map[string]any)
+		sig := base
+		sig.RuleID, _ = rule["id"].(string)
+		sig.RuleName, _ = rule["name"].(string)
+		sig.RuleTags, _ = rule["tags"].(map[string]any)
+
+		matches, _ := event["rule_matches"].([]any)
+		for _, match := range matches {
+			match, _ := match.(map[string]any)
+			params, _ := match["parameters"].([]any)
+			for _, param := range params {
+				param, _ := param.(map[string]any)
+				address, _ := param["address"].(string)
+				keyPath, _ := param["key_path"].([]any)
+				sig.Matches = append(sig.Matches, signalParameter{
+					Address: address,
+					KeyPath: keyPath,
+					Value:   redactedValue,
+				})
+			}
+		}
+		signals = append(signals, sig)
+	}
+	return signals
+}
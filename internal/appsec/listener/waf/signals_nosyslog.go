@@ -0,0 +1,18 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build windows || plan9
+
+package waf
+
+import (
+	"errors"
+	"io"
+)
+
+// newSyslogWriter returns an error since syslog is not available on this platform.
+func newSyslogWriter() (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
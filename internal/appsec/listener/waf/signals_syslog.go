@@ -0,0 +1,18 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+//go:build !windows && !plan9
+
+package waf
+
+import (
+	"io"
+	"log/syslog"
+)
+
+// newSyslogWriter returns a writer to the local syslog daemon.
+func newSyslogWriter() (io.WriteCloser, error) {
+	return syslog.New(syslog.LOG_WARNING|syslog.LOG_DAEMON, "dd-appsec")
+}
@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package waf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+)
+
+func TestNewSignals(t *testing.T) {
+	wafEvents := []any{
+		map[string]any{
+			"rule": map[string]any{
+				"id":   "crs-942-100",
+				"name": "SQL Injection Attack Detected via libinjection",
+				"tags": map[string]any{"type": "sql_injection", "category": "attack_attempt"},
+			},
+			"rule_matches": []any{
+				map[string]any{
+					"operator": "is_sqli",
+					"parameters": []any{
+						map[string]any{
+							"address":   "server.request.query",
+							"key_path":  []any{"id", "0"},
+							"value":     "1' OR '1'='1",
+							"highlight": []any{"s&sos"},
+						},
+					},
+				},
+			},
+		},
+		"unexpected event",
+	}
+
+	base := signal{Timestamp: "2025-01-01T00:00:00Z", TraceID: "1234", ClientIP: "1.2.3.4", UserID: "usr-1", Blocked: true}
+	signals := newSignals(base, wafEvents)
+	require.Equal(t, []signal{{
+		Timestamp: "2025-01-01T00:00:00Z",
+		TraceID:   "1234",
+		RuleID:    "crs-942-100",
+		RuleName:  "SQL Injection Attack Detected via libinjection",
+		RuleTags:  map[string]any{"type": "sql_injection", "category": "attack_attempt"},
+		Matches: []signalParameter{{
+			Address: "server.request.query",
+			KeyPath: []any{"id", "0"},
+			Value:   redactedValue,
+		}},
+		ClientIP: "1.2.3.4",
+		UserID:   "usr-1",
+		Blocked:  true,
+	}}, signals)
+}
+
+func TestNewSignalSink(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		sink, err := newSignalSink(&config.Config{})
+		require.NoError(t, err)
+		require.Nil(t, sink)
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink, err := newSignalSink(&config.Config{SignalsWriter: &buf, SignalsOutput: "stdout"})
+		require.NoError(t, err)
+		sink.write([]signal{{RuleID: "a"}, {RuleID: "b"}})
+		sink.close()
+		require.Equal(t, "{\"timestamp\":\"\",\"rule_id\":\"a\",\"blocked\":false}\n{\"timestamp\":\"\",\"rule_id\":\"b\",\"blocked\":false}\n", buf.String())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "signals.jsonl")
+		sink, err := newSignalSink(&config.Config{SignalsOutput: path})
+		require.NoError(t, err)
+		sink.write([]signal{{RuleID: "a"}})
+		sink.close()
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "{\"timestamp\":\"\",\"rule_id\":\"a\",\"blocked\":false}\n", string(data))
+	})
+
+	t.Run("invalid-file", func(t *testing.T) {
+		_, err := newSignalSink(&config.Config{SignalsOutput: filepath.Join(t.TempDir(), "missing", "signals.jsonl")})
+		require.Error(t, err)
+	})
+}
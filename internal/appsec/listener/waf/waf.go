@@ -38,6 +38,10 @@ type Feature struct {
 	// Determine if we can use [internal.MetaStructValue] to delegate the WAF events serialization to the trace writer
 	// or if we have to use the [SerializableTag] method to serialize the events
 	metaStructAvailable bool
+
+	// signals is the local sink the security signals are exported to, if any
+	signals     *signalSink
+	spanTraceID func(any) string
 }
 
 func NewWAFFeature(cfg *config.Config, rootOp dyngo.Operation) (listener.Feature, error) {
@@ -60,6 +64,11 @@ func NewWAFFeature(cfg *config.Config, rootOp dyngo.Operation) (listener.Feature
 
 	cfg.SupportedAddresses = config.NewAddressSet(newHandle.Addresses())
 
+	signals, err := newSignalSink(cfg)
+	if err != nil {
+		log.Error("appsec: security signals will not be exported to %s: %v", cfg.SignalsOutput, err)
+	}
+
 	tokenTicker := limiter.NewTokenTicker(cfg.TraceRateLimit, cfg.TraceRateLimit)
 	tokenTicker.Start()
 
@@ -70,6 +79,8 @@ func NewWAFFeature(cfg *config.Config, rootOp dyngo.Operation) (listener.Feature
 		supportedAddrs:      cfg.SupportedAddresses,
 		telemetryMetrics:    telemetryMetrics,
 		metaStructAvailable: cfg.MetaStructAvailable,
+		signals:             signals,
+		spanTraceID:         cfg.SpanTraceID,
 	}
 
 	dyngo.On(rootOp, feature.onStart)
@@ -97,6 +108,10 @@ func (waf *Feature) onStart(op *waf.ContextOperation, _ waf.ContextArgs) {
 	dyngo.OnData(op, op.OnEvent)
 
 	waf.SetupActionHandlers(op)
+
+	if waf.signals != nil {
+		waf.signals.exportSignals(op, waf.spanTraceID)
+	}
 }
 
 func (*Feature) SetupActionHandlers(op *waf.ContextOperation) {
@@ -153,4 +168,7 @@ func (*Feature) String() string {
 func (waf *Feature) Stop() {
 	waf.limiter.Stop()
 	waf.handle.Close()
+	if waf.signals != nil {
+		waf.signals.close()
+	}
 }
@@ -165,6 +165,9 @@ func (a *appsec) onRCRulesUpdate(updates map[string]remoteconfig.ProductUpdate)
 		return map[string]rc.ApplyStatus{}
 	}
 
+	a.rulesMu.Lock()
+	defer a.rulesMu.Unlock()
+
 	// Create a new local RulesManager
 	r := a.cfg.RulesManager.Clone()
 	statuses, err := combineRCRulesUpdates(&r, updates)
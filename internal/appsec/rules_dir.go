@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package appsec
+
+import (
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// rulesDirPollInterval is the interval at which the local rules directory is checked for changes.
+var rulesDirPollInterval = 5 * time.Second
+
+// rulesDirWatcher periodically reloads the local rules directory set by [config.EnvRulesDir].
+type rulesDirWatcher struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+// loadRulesDir loads the local rules directory, if any, into the rules manager and starts watching it for changes,
+// which are applied by swapping the root operation with one using the new security rules. It must be called before
+// the initial root operation swap, which applies the initially loaded rules.
+func (a *appsec) loadRulesDir() {
+	if a.cfg.RulesDir == "" {
+		return
+	}
+
+	log.Debug("appsec: loading the local rules directory %s", a.cfg.RulesDir)
+	state := a.updateRulesDir(nil, false)
+
+	w := &rulesDirWatcher{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	a.rulesDirWatcher = w
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(rulesDirPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				state = a.updateRulesDir(state, true)
+			}
+		}
+	}()
+}
+
+// stopRulesDirWatcher stops watching the local rules directory, if it was.
+func (a *appsec) stopRulesDirWatcher() {
+	w := a.rulesDirWatcher
+	if w == nil {
+		return
+	}
+	a.rulesDirWatcher = nil
+	close(w.stop)
+	<-w.done
+}
+
+// updateRulesDir reloads the local rules directory when its state changed since prev, and returns its new state.
+// The current security rules are kept when the directory content is invalid.
+func (a *appsec) updateRulesDir(prev config.RulesDirState, swap bool) config.RulesDirState {
+	state, err := config.StatRulesDir(a.cfg.RulesDir)
+	if err != nil {
+		// Only report the error once until the directory becomes readable again, as it is polled
+		if a.rulesDirUnreadable {
+			log.Debug("appsec: could not read the local rules directory %s: %v", a.cfg.RulesDir, err)
+		} else {
+			log.Error("appsec: could not read the local rules directory %s: %v", a.cfg.RulesDir, err)
+		}
+		a.rulesDirUnreadable = true
+		return prev
+	}
+	a.rulesDirUnreadable = false
+	if prev != nil && state.Equal(prev) {
+		return prev
+	}
+
+	fragments, err := config.ReadRulesDir(a.cfg.RulesDir)
+	if err != nil {
+		log.Error("appsec: could not load the local rules directory %s, keeping the current security rules: %v", a.cfg.RulesDir, err)
+		return state
+	}
+
+	a.rulesMu.Lock()
+	defer a.rulesMu.Unlock()
+
+	r := a.cfg.RulesManager.Clone()
+	if err := r.SetLocalRules(fragments); err != nil {
+		log.Error("appsec: could not load the local rules directory %s, keeping the current security rules: %v", a.cfg.RulesDir, err)
+		return state
+	}
+	r.Compile()
+
+	prevRules := a.cfg.RulesManager
+	a.cfg.RulesManager = &r
+	if !swap {
+		return state
+	}
+
+	if err := a.SwapRootOperation(); err != nil {
+		log.Error("appsec: could not apply the security rules of the local rules directory %s, keeping the current security rules: %v", a.cfg.RulesDir, err)
+		a.cfg.RulesManager = prevRules
+		return state
+	}
+	log.Debug("appsec: applied the security rules of the local rules directory %s", a.cfg.RulesDir)
+	return state
+}
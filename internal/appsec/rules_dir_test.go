@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package appsec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	waf "github.com/DataDog/go-libddwaf/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/internal/appsec/config"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+func TestRulesDir(t *testing.T) {
+	if supported, _ := waf.Health(); !supported {
+		t.Skip("WAF needs to be available for this test")
+	}
+
+	defer func(interval time.Duration) { rulesDirPollInterval = interval }(rulesDirPollInterval)
+	rulesDirPollInterval = 10 * time.Millisecond
+
+	dir := t.TempDir()
+	t.Setenv(config.EnvRulesDir, dir)
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	writeFile("denylist.json", `{"rules_data":[{"id":"blocked_ips","type":"ip_with_expiration","data":[{"value":"1.2.3.4"}]}]}`)
+	writeFile("README.md", "ignored")
+
+	Start(config.WithEnablementMode(config.ForcedOn))
+	defer Stop()
+	if !Enabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+
+	// localEdits returns the paths of the local rules currently merged in the rules manager
+	localEdits := func() []string {
+		mu.RLock()
+		defer mu.RUnlock()
+		activeAppSec.rulesMu.Lock()
+		defer activeAppSec.rulesMu.Unlock()
+		var paths []string
+		for path := range activeAppSec.cfg.RulesManager.Edits {
+			paths = append(paths, path)
+		}
+		return paths
+	}
+
+	t.Run("initial", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"local/denylist.json"}, localEdits())
+
+		mu.RLock()
+		rules := activeAppSec.cfg.RulesManager.Latest
+		mu.RUnlock()
+		handle, err := waf.NewHandle(rules, "", "")
+		require.NoError(t, err)
+		defer handle.Close()
+		wafCtx, err := handle.NewContext()
+		require.NoError(t, err)
+		defer wafCtx.Close()
+		result, err := wafCtx.Run(waf.RunAddressData{Persistent: map[string]any{addresses.ClientIPAddr: "1.2.3.4"}})
+		require.NoError(t, err)
+		require.Contains(t, result.Actions, "block_request")
+	})
+
+	t.Run("add", func(t *testing.T) {
+		writeFile("exclusions.json", `{"exclusions":[{"id":"exclude-health","conditions":[{"operator":"match_regex","parameters":{"inputs":[{"address":"server.request.uri.raw"}],"regex":"^/health"}}]}]}`)
+		require.Eventually(t, func() bool {
+			return len(localEdits()) == 2
+		}, time.Second, 10*time.Millisecond)
+		require.ElementsMatch(t, []string{"local/denylist.json", "local/exclusions.json"}, localEdits())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "denylist.json")))
+		require.Eventually(t, func() bool {
+			return len(localEdits()) == 1
+		}, time.Second, 10*time.Millisecond)
+		require.ElementsMatch(t, []string{"local/exclusions.json"}, localEdits())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		writeFile("invalid.json", `{"custom_rules":`)
+		time.Sleep(10 * rulesDirPollInterval)
+		require.ElementsMatch(t, []string{"local/exclusions.json"}, localEdits())
+	})
+
+	t.Run("unreadable", func(t *testing.T) {
+		var logger log.RecordLogger
+		defer log.UseLogger(&logger)()
+		readErrors := func() int {
+			log.Flush()
+			var n int
+			for _, msg := range logger.Logs() {
+				if strings.Contains(msg, "could not read the local rules directory") {
+					require.NotContains(t, msg, "additional messages skipped")
+					n++
+				}
+			}
+			return n
+		}
+
+		moved := dir + ".moved"
+		require.NoError(t, os.Rename(dir, moved))
+		time.Sleep(10 * rulesDirPollInterval)
+		require.Equal(t, 1, readErrors())
+		require.ElementsMatch(t, []string{"local/exclusions.json"}, localEdits())
+
+		// The error is reported again once the directory was readable in between
+		require.NoError(t, os.Rename(moved, dir))
+		time.Sleep(10 * rulesDirPollInterval)
+		require.NoError(t, os.Rename(dir, moved))
+		time.Sleep(10 * rulesDirPollInterval)
+		require.NoError(t, os.Rename(moved, dir))
+		require.Equal(t, 2, readErrors())
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -26,11 +27,16 @@ import (
 
 	pAppsec "github.com/DataDog/dd-trace-go/v2/appsec"
 	"github.com/DataDog/dd-trace-go/v2/appsec/events"
+	wrapos "github.com/DataDog/dd-trace-go/v2/contrib/os"
+	wrapexec "github.com/DataDog/dd-trace-go/v2/contrib/os/exec"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/mocktracer"
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/dyngo"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/httpsec"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/ossec"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/sqlsec"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/emitter/waf/addresses"
+	"github.com/DataDog/dd-trace-go/v2/instrumentation/appsec/iast"
 	httptrace "github.com/DataDog/dd-trace-go/v2/instrumentation/httptracemock"
 	"github.com/DataDog/dd-trace-go/v2/instrumentation/testutils"
 	"github.com/DataDog/dd-trace-go/v2/internal/appsec"
@@ -471,6 +477,127 @@ func TestBlocking(t *testing.T) {
 	}
 }
 
+// Test that HTTP request bodies are automatically parsed and monitored when body parsing is enabled
+func TestRequestBodyParsing(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "testdata/blocking.json")
+	t.Setenv(config.EnvBodyParsingEnabled, "true")
+	t.Setenv(config.EnvBodyParsingSizeLimit, "256")
+	testutils.StartAppSec(t)
+
+	if !appsec.Enabled() {
+		t.Skip("AppSec needs to be enabled for this test")
+	}
+
+	const bodyBlockingRule = "crs-933-130-block"
+
+	// Start and trace an HTTP server echoing the request body
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/body", func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name        string
+		contentType string
+		reqBody     string
+		status      int
+	}{
+		{
+			name:        "json/no-block",
+			contentType: "application/json",
+			reqBody:     `{"key": ["value"]}`,
+			status:      200,
+		},
+		{
+			name:        "json/block",
+			contentType: "application/json; charset=utf-8",
+			reqBody:     `{"key": ["$globals"]}`,
+			status:      403,
+		},
+		{
+			name:        "urlencoded/no-block",
+			contentType: "application/x-www-form-urlencoded",
+			reqBody:     "key=value",
+			status:      200,
+		},
+		{
+			name:        "urlencoded/block",
+			contentType: "application/x-www-form-urlencoded",
+			reqBody:     "key=%24globals",
+			status:      403,
+		},
+		{
+			name:        "multipart/block",
+			contentType: "multipart/form-data; boundary=boundary",
+			reqBody:     "--boundary\r\nContent-Disposition: form-data; name=\"key\"\r\n\r\n$globals\r\n--boundary--\r\n",
+			status:      403,
+		},
+		{
+			name:        "xml/no-block",
+			contentType: "application/xml",
+			reqBody:     `<root><key attr="value">value</key></root>`,
+			status:      200,
+		},
+		{
+			name:        "xml/block",
+			contentType: "text/xml",
+			reqBody:     `<root><key>$globals</key></root>`,
+			status:      403,
+		},
+		{
+			name:        "xml/attribute/block",
+			contentType: "application/soap+xml",
+			reqBody:     `<root><key attr="$globals"/></root>`,
+			status:      403,
+		},
+		{
+			name:        "unsupported-content-type",
+			contentType: "text/plain",
+			reqBody:     "$globals",
+			status:      200,
+		},
+		{
+			name:        "invalid-json",
+			contentType: "application/json",
+			reqBody:     `{"key": "$globals"`,
+			status:      200,
+		},
+		{
+			name:        "too-large",
+			contentType: "application/json",
+			reqBody:     `{"key": "$globals", "padding": "` + strings.Repeat("a", 256) + `"}`,
+			status:      200,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			req, err := http.NewRequest("POST", srv.URL+"/body", strings.NewReader(tc.reqBody))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", tc.contentType)
+			res, err := srv.Client().Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, tc.status, res.StatusCode)
+			b, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			if tc.status == 200 {
+				// The handler must still be able to read the whole body
+				require.Equal(t, tc.reqBody, string(b))
+				require.NotContains(t, spans[0].Tags(), "_dd.appsec.json")
+			} else {
+				require.Contains(t, spans[0].Tag("_dd.appsec.json"), bodyBlockingRule)
+			}
+		})
+	}
+}
+
 // Test that API Security schemas get collected when API security is enabled
 func TestAPISecurity(t *testing.T) {
 	// Start and trace an HTTP server
@@ -567,6 +694,90 @@ func TestAPISecurity(t *testing.T) {
 	})
 }
 
+// Test that JSON response bodies get captured and their schemas extracted when response body parsing is enabled
+func TestResponseBodyParsing(t *testing.T) {
+	t.Setenv(config.EnvEnabled, "true")
+	if wafOK, err := waf.Health(); !wafOK {
+		t.Skipf("WAF must be usable for this test to run correctly: %v", err)
+	}
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1337, "email": `))
+		w.Write([]byte(`"john.doe@example.com"}`))
+	})
+	mux.HandleFunc("/text", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(`{"id": 1337}`))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"padding": "` + strings.Repeat("a", 256) + `"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name       string
+		endpoint   string
+		sampleRate string
+		extracted  bool
+	}{
+		{
+			name:       "json",
+			endpoint:   "/json",
+			sampleRate: "1",
+			extracted:  true,
+		},
+		{
+			name:       "not-sampled",
+			endpoint:   "/json",
+			sampleRate: "0",
+		},
+		{
+			name:       "not-json",
+			endpoint:   "/text",
+			sampleRate: "1",
+		},
+		{
+			name:       "too-large",
+			endpoint:   "/large",
+			sampleRate: "1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var sampler mockSampler
+			sampler.On("DecisionFor", mock.Anything).Return(true)
+
+			t.Setenv(internal.EnvAPISecEnabled, "true")
+			t.Setenv(config.EnvResponseBodyParsingEnabled, "true")
+			t.Setenv(config.EnvResponseBodyParsingSizeLimit, "256")
+			t.Setenv(config.EnvResponseBodyParsingSampleRate, tc.sampleRate)
+			testutils.StartAppSec(t, config.WithAPISecOptions(internal.WithAPISecSampler(&sampler)))
+			require.True(t, appsec.Enabled())
+
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			res, err := srv.Client().Get(srv.URL + tc.endpoint)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, 200, res.StatusCode)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+
+			// The schemas of the other addresses are always extracted
+			assert.NotNil(t, spans[0].Tag("_dd.appsec.s.res.headers"))
+			if tc.extracted {
+				assert.NotNil(t, spans[0].Tag("_dd.appsec.s.res.body"))
+			} else {
+				assert.Nil(t, spans[0].Tag("_dd.appsec.s.res.body"))
+			}
+		})
+	}
+}
+
 func TestRASPLFI(t *testing.T) {
 	t.Setenv("DD_APPSEC_RULES", "testdata/rasp.json")
 	testutils.StartAppSec(t)
@@ -679,6 +890,299 @@ func TestRASPLFI(t *testing.T) {
 	}
 }
 
+func TestRASPCMDi(t *testing.T) {
+	t.Setenv("DD_APPSEC_RULES", "testdata/rasp.json")
+	testutils.StartAppSec(t)
+
+	if !appsec.RASPEnabled() {
+		t.Skip("RASP needs to be enabled for this test")
+	}
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		file := r.URL.Query().Get("file")
+		var cmd *wrapexec.Cmd
+		if r.URL.Query().Get("shell") == "true" {
+			cmd = wrapexec.CommandContext(r.Context(), "sh", "-c", "ls "+file)
+		} else {
+			args := strings.Fields(file)
+			cmd = wrapexec.CommandContext(r.Context(), args[0], args[1:]...)
+		}
+		err := cmd.Run()
+		if r.URL.Query().Get("block") == "true" {
+			require.ErrorIs(t, err, &events.BlockingSecurityEvent{})
+			return
+		}
+		require.NotErrorIs(t, err, &events.BlockingSecurityEvent{})
+		w.WriteHeader(204)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name    string
+		file    string
+		shell   bool
+		block   bool
+		rule    string
+		variant string
+	}{
+		{
+			name:    "exec-no-error",
+			file:    "ls /tmp",
+			variant: "exec",
+		},
+		{
+			name:    "exec",
+			file:    "/usr/bin/reboot",
+			block:   true,
+			rule:    "rasp-932-110",
+			variant: "exec",
+		},
+		{
+			name:    "shell-no-error",
+			file:    "/tmp",
+			shell:   true,
+			variant: "shell",
+		},
+		{
+			name:    "shell",
+			file:    "/tmp; cat /etc/passwd",
+			shell:   true,
+			block:   true,
+			rule:    "rasp-932-100",
+			variant: "shell",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+			telemetryClient := new(telemetrytest.RecordClient)
+			prevClient := telemetry.SwapClient(telemetryClient)
+			defer telemetry.SwapClient(prevClient)
+
+			query := url.Values{
+				"file":  {tc.file},
+				"shell": {strconv.FormatBool(tc.shell)},
+				"block": {strconv.FormatBool(tc.block)},
+			}
+			req, err := http.NewRequest("GET", srv.URL+"?"+query.Encode(), nil)
+			require.NoError(t, err)
+			res, err := srv.Client().Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+
+			if tc.block {
+				require.Equal(t, 403, res.StatusCode)
+				require.Contains(t, spans[0].Tag("_dd.appsec.json"), tc.rule)
+				require.Contains(t, spans[0].Tags(), "_dd.stack")
+			} else {
+				require.Equal(t, 204, res.StatusCode)
+			}
+
+			assert.Equal(t, 1.0, telemetryClient.Count(telemetry.NamespaceAppSec, "rasp.rule.eval", []string{
+				"rule_type:command_injection",
+				"rule_variant:" + tc.variant,
+				"waf_version:" + waf.Version(),
+				"event_rules_version:1.4.2",
+			}).Get())
+		})
+	}
+}
+
+func TestIAST(t *testing.T) {
+	t.Setenv("DD_IAST_ENABLED", "true")
+	t.Setenv("DD_APPSEC_RASP_ENABLED", "false")
+	testutils.StartAppSec(t)
+
+	if !appsec.IASTEnabled() {
+		t.Skip("IAST needs to be enabled for this test")
+	}
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := r.URL.Query().Get("id")
+		// Without Orchestrion, the taint is not propagated through string
+		// operations: the sinks are given the tainted request data as-is. The
+		// sinks reached through propagation are tested by the iast package.
+		switch r.URL.Query().Get("sink") {
+		case "sql":
+			require.NoError(t, sqlsec.ProtectSQLOperation(ctx, id, "sqlite"))
+		case "sql-safe":
+			require.NoError(t, sqlsec.ProtectSQLOperation(ctx, "SELECT * FROM users WHERE id = ?", "sqlite"))
+		case "file":
+			if f, err := wrapos.OpenFile(ctx, id, os.O_RDONLY, 0); err == nil {
+				f.Close()
+			}
+		case "exec":
+			require.NoError(t, wrapexec.CommandContext(ctx, "echo", id).Run())
+		case "ssrf":
+			u, err := url.Parse(r.Header.Get("X-Target"))
+			require.NoError(t, err)
+			require.NoError(t, httpsec.ProtectRoundTripURL(ctx, u))
+		case "body":
+			var body struct{ Name string }
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.NoError(t, pAppsec.MonitorParsedHTTPBody(ctx, body))
+			require.NoError(t, sqlsec.ProtectSQLOperation(ctx, body.Name, "sqlite"))
+		}
+		w.WriteHeader(204)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		name     string
+		sink     string
+		headers  map[string]string
+		body     string
+		vuln     string
+		evidence string
+		source   iast.Source
+	}{
+		{
+			name:     "sql",
+			sink:     "sql",
+			vuln:     "SQL_INJECTION",
+			evidence: "1",
+			source:   iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"},
+		},
+		{
+			name: "sql-safe",
+			sink: "sql-safe",
+		},
+		{
+			name:     "file",
+			sink:     "file",
+			vuln:     "PATH_TRAVERSAL",
+			evidence: "1",
+			source:   iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"},
+		},
+		{
+			name:     "exec",
+			sink:     "exec",
+			vuln:     "COMMAND_INJECTION",
+			evidence: "echo 1",
+			source:   iast.Source{Origin: iast.OriginParameter, Name: "id", Value: "1"},
+		},
+		{
+			name:     "ssrf",
+			sink:     "ssrf",
+			headers:  map[string]string{"X-Target": "http://example.com/"},
+			vuln:     "SSRF",
+			evidence: "http://example.com/",
+			source:   iast.Source{Origin: iast.OriginHeader, Name: "X-Target", Value: "http://example.com/"},
+		},
+		{
+			name:     "body",
+			sink:     "body",
+			body:     `{"name":"bob"}`,
+			vuln:     "SQL_INJECTION",
+			evidence: "bob",
+			source:   iast.Source{Origin: iast.OriginBody, Name: "Name", Value: "bob"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mt := mocktracer.Start()
+			defer mt.Stop()
+
+			req, err := http.NewRequest("POST", srv.URL+"?id=1&sink="+tc.sink, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			res, err := srv.Client().Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, 204, res.StatusCode)
+
+			spans := mt.FinishedSpans()
+			require.Len(t, spans, 1)
+			require.Contains(t, spans[0].Tags(), "_dd.iast.enabled")
+
+			if tc.vuln == "" {
+				require.NotContains(t, spans[0].Tags(), "_dd.iast.json")
+				return
+			}
+			var report struct {
+				Sources         []iast.Source
+				Vulnerabilities []struct {
+					Type     string
+					Evidence struct {
+						Value  string
+						Source int
+					}
+				}
+			}
+			tag, _ := spans[0].Tag("_dd.iast.json").(string)
+			require.NoError(t, json.Unmarshal([]byte(tag), &report))
+			require.Len(t, report.Vulnerabilities, 1)
+			require.Equal(t, tc.vuln, report.Vulnerabilities[0].Type)
+			require.Equal(t, tc.evidence, report.Vulnerabilities[0].Evidence.Value)
+			require.Equal(t, []iast.Source{tc.source}, report.Sources)
+			require.Equal(t, 0, report.Vulnerabilities[0].Evidence.Source)
+		})
+	}
+}
+
+func TestSignalsExport(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "signals.jsonl")
+	t.Setenv(config.EnvSignalsOutput, output)
+	testutils.StartAppSec(t, config.WithSpanTraceID(func(any) string { return "1234" }))
+	if !appsec.Enabled() {
+		t.Skip("appsec disabled")
+	}
+
+	mux := httptrace.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, pAppsec.SetUser(r.Context(), "usr-1"))
+		w.Write([]byte("Hello World!\n"))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	res, err := srv.Client().Get(srv.URL + "/?file=../../../etc/passwd")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var signal struct {
+		TraceID string `json:"trace_id"`
+		RuleID  string `json:"rule_id"`
+		Matches []struct {
+			Address string `json:"address"`
+			KeyPath []any  `json:"key_path"`
+			Value   string `json:"value"`
+		} `json:"matches"`
+		ClientIP string `json:"client_ip"`
+		UserID   string `json:"user_id"`
+		Blocked  bool   `json:"blocked"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &signal))
+	require.Equal(t, "1234", signal.TraceID)
+	require.Equal(t, "crs-930-120", signal.RuleID)
+	require.Equal(t, "127.0.0.1", signal.ClientIP)
+	require.Equal(t, "usr-1", signal.UserID)
+	require.False(t, signal.Blocked)
+	require.NotEmpty(t, signal.Matches)
+	require.Equal(t, "server.request.query", signal.Matches[0].Address)
+	require.Equal(t, []any{"file", "0"}, signal.Matches[0].KeyPath)
+	require.Equal(t, "<redacted>", signal.Matches[0].Value)
+	require.NotContains(t, lines[0], "passwd")
+}
+
 func TestSuspiciousAttackerBlocking(t *testing.T) {
 	t.Setenv("DD_APPSEC_RULES", "testdata/sab.json")
 	testutils.StartAppSec(t)
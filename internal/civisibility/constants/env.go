@@ -49,4 +49,9 @@ const (
 
 	// CIVisibilityEnvironmentDataFilePath is the environment variable that holds the path to the file containing the environmental data.
 	CIVisibilityEnvironmentDataFilePath = "DD_TEST_OPTIMIZATION_ENV_DATA_FILE"
+
+	// CIVisibilityOfflineDirEnvironmentVariable enables the CI Visibility offline mode, where no request is sent to the
+	// agent or the backend. The settings, known tests, skippable tests and test management tests are read from the
+	// JSON files of this directory, and the test events are written to it as JUnit XML reports and raw payload files.
+	CIVisibilityOfflineDirEnvironmentVariable = "DD_CIVISIBILITY_OFFLINE_DIR"
 )
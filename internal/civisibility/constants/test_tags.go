@@ -128,6 +128,9 @@ const (
 
 	// TestManagementEnabled indicates that the test management feature is enabled
 	TestManagementEnabled = "test.test_management.enabled"
+
+	// TestGinkgoLabels indicates the labels of a Ginkgo spec
+	TestGinkgoLabels = "test.ginkgo.labels"
 )
 
 // Define valid test status types.
@@ -0,0 +1,781 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package gotesting
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	_ "unsafe" // required blank import to run orchestrion
+
+	"github.com/DataDog/dd-trace-go/v2/ddtrace/ext"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/constants"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/integrations"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils/net"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils/telemetry"
+)
+
+const (
+	// ginkgoFramework represents the name of the Ginkgo testing framework.
+	ginkgoFramework = "github.com/onsi/ginkgo/v2"
+
+	// ginkgoPendingReason is the skip reason of the pending specs.
+	ginkgoPendingReason = "Pending spec"
+
+	// ginkgoNotRunReason is the skip reason of the specs skipped by Ginkgo because they are not focused or filtered out.
+	ginkgoNotRunReason = "Spec not focused or filtered out"
+
+	// ginkgoDisabledReason is the skip reason of the specs disabled by the test management feature.
+	ginkgoDisabledReason = "Flaky test is disabled by Datadog"
+
+	// ginkgoQuarantinedReason is the skip reason of the specs quarantined by the test management feature.
+	ginkgoQuarantinedReason = "Flaky test is quarantined by Datadog"
+)
+
+type (
+	// ginkgoRunInfo holds the information about the Ginkgo suite run by `ginkgo.RunSpecs`.
+	ginkgoRunInfo struct {
+		description       string                            // description of the Ginkgo suite
+		module            integrations.TestModule           // module of the test running the Ginkgo suite
+		frameworkVersion  string                            // version of Ginkgo
+		currentSpecReport func() any                        // returns the `ginkgo.SpecReport` of the running spec
+		fail              func(message string, skip ...int) // `ginkgo.Fail`
+		skip              func(message string, skip ...int) // `ginkgo.Skip`
+		suites            map[string]integrations.TestSuite // suites created for the container hierarchies of the specs
+	}
+
+	// ginkgoSpecReport holds the data read from a `ginkgo.SpecReport`.
+	ginkgoSpecReport struct {
+		containers     []string
+		name           string
+		labels         []string
+		file           string
+		line           int
+		state          string
+		failureMessage string
+		failureStack   string
+		forwardedPanic string
+	}
+
+	// ginkgoSpec holds the CI Visibility state of a Ginkgo spec whose body is executed.
+	ginkgoSpec struct {
+		commonInfo
+		run            *ginkgoRunInfo         // Ginkgo suite of the spec
+		suite          integrations.TestSuite // CI Visibility suite of the spec
+		report         *ginkgoSpecReport      // spec report when the spec body started
+		bodyFunc       *runtime.Func          // function of the spec body, used to set the source of the test events
+		isNew          bool                   // flag to tag if the spec is new
+		isQuarantined  bool                   // flag to check if the spec is quarantined
+		isDisabled     bool                   // flag to check if the spec is disabled
+		isAttemptToFix bool                   // flag to check if the spec is marked as attempt to fix
+		isForcedToRun  bool                   // flag to check if the spec is run while skippable because it is unskippable
+		retryReason    string                 // reason of the retries of the spec
+		executions     int                    // number of closed executions
+		passCount      int                    // number of closed executions that passed
+		failCount      int                    // number of closed executions that failed
+		latest         *ginkgoSpecExecution   // latest execution, closed once the spec report is available
+		running        bool                   // flag to check if the spec body is running
+		intercept      bool                   // flag to check if the failures of the running execution are intercepted
+		failure        *ginkgoFailure         // failure reported by `ginkgo.Fail` during the running execution
+		lastFailure    string                 // message of the latest intercepted failure
+		policy         *ginkgoRetryPolicy     // retry policy of the spec
+	}
+
+	// ginkgoSpecExecution holds a single execution of a Ginkgo spec body.
+	ginkgoSpecExecution struct {
+		test     integrations.Test             // CI Visibility test event of the execution
+		status   integrations.TestResultStatus // status of the execution
+		isRetry  bool                          // flag to tag if the execution is a retry
+		hasError bool                          // flag to check if the test event has error data already
+	}
+
+	// ginkgoFailure is a failure reported by `ginkgo.Fail` while a spec body is running, it's also the panic value used
+	// to stop the execution of the spec body when the failure is intercepted.
+	ginkgoFailure struct {
+		message string
+		stack   string
+	}
+
+	// ginkgoRetryPolicy decides how many times a spec body is executed by the additional features.
+	ginkgoRetryPolicy struct {
+		reason               string                            // retry reason ("efd", "atr" or "attempt_to_fix"), empty when the spec is not retried
+		retries              int                               // maximum number of retries, computed after the first execution for early flake detection
+		settings             *net.SettingsResponseData         // settings of the session
+		flakyRetriesSettings *integrations.FlakyRetriesSetting // automatic test retries settings, with the total retries budget
+	}
+)
+
+var (
+	// ginkgoRun holds the Ginkgo suite being run, nil when no instrumented Ginkgo suite is running.
+	ginkgoRun *ginkgoRunInfo
+
+	// ginkgoCurrentSpec holds the Ginkgo spec being run (Ginkgo runs the specs one by one in each process).
+	ginkgoCurrentSpec *ginkgoSpec
+
+	// ginkgoMutex is a mutex for synchronizing access to the Ginkgo suite and spec being run.
+	ginkgoMutex sync.Mutex
+)
+
+// ******************************************************************************************************************
+// WARNING: DO NOT CHANGE THE SIGNATURE OF THESE FUNCTIONS!
+//
+//  The following functions are being used by the Orchestrion automatic instrumentation of Ginkgo.
+// ******************************************************************************************************************
+
+// instrumentGinkgoRunSpecs helper function to instrument the Ginkgo suite run by `ginkgo.RunSpecs`. It returns the
+// function to call once the suite has been run.
+//
+//go:linkname instrumentGinkgoRunSpecs
+func instrumentGinkgoRunSpecs(t any, description string, currentSpecReport func() any, fail func(string, ...int), skip func(string, ...int)) func() {
+	// Check if CI Visibility was disabled using the kill switch or if the testing instrumentation is not running
+	if !isCiVisibilityEnabled() || session == nil {
+		return func() {}
+	}
+
+	// The specs are reported in the module of the test running the suite
+	var module integrations.TestModule
+	if tb, ok := t.(testing.TB); ok {
+		if execMeta := getTestMetadata(tb); execMeta != nil {
+			// Ginkgo can't run the suite twice, so the test running it must not be retried
+			execMeta.isGinkgoSuite = true
+			if execMeta.test != nil && execMeta.test.Suite() != nil {
+				module = execMeta.test.Suite().Module()
+			}
+		}
+	}
+	if module == nil {
+		// If the test is not instrumented, let's use the package of the caller of `ginkgo.RunSpecs`
+		pc, _, _, _ := runtime.Caller(2)
+		moduleName, _ := utils.GetModuleAndSuiteName(pc)
+		module = session.GetOrCreateModule(moduleName)
+	}
+
+	run := &ginkgoRunInfo{
+		description:       description,
+		module:            module,
+		frameworkVersion:  getGinkgoVersion(),
+		currentSpecReport: currentSpecReport,
+		fail:              fail,
+		skip:              skip,
+		suites:            map[string]integrations.TestSuite{},
+	}
+	ginkgoMutex.Lock()
+	ginkgoRun = run
+	ginkgoMutex.Unlock()
+
+	return func() {
+		ginkgoMutex.Lock()
+		spec := ginkgoCurrentSpec
+		ginkgoRun, ginkgoCurrentSpec = nil, nil
+		ginkgoMutex.Unlock()
+
+		// Close the latest execution in case the spec report was not received, and the suites of the specs
+		if spec != nil {
+			spec.finish(nil)
+		}
+		for _, suite := range run.suites {
+			suite.Close()
+		}
+	}
+}
+
+// instrumentGinkgoSpecArgs helper function to instrument the spec bodies of the arguments of `ginkgo.It` and
+// `ginkgo.DescribeTable`.
+//
+//go:linkname instrumentGinkgoSpecArgs
+func instrumentGinkgoSpecArgs(args []any) []any {
+	// Check if CI Visibility was disabled using the kill switch before instrumenting
+	if !isCiVisibilityEnabled() {
+		return args
+	}
+	return wrapGinkgoSpecArgs(args)
+}
+
+// instrumentGinkgoFail helper function to report the failures of `ginkgo.Fail` to the running spec, and to intercept
+// them when the spec is going to be retried.
+//
+//go:linkname instrumentGinkgoFail
+func instrumentGinkgoFail(message string, callerSkip ...int) {
+	// Check if CI Visibility was disabled using the kill switch before
+	if !isCiVisibilityEnabled() {
+		return
+	}
+
+	ginkgoMutex.Lock()
+	spec := ginkgoCurrentSpec
+	if spec == nil || !spec.running {
+		// The failure is not reported by a spec body (e.g. a setup node), Ginkgo handles it
+		ginkgoMutex.Unlock()
+		return
+	}
+	skip := 0
+	if len(callerSkip) > 0 {
+		skip = callerSkip[0]
+	}
+	failure := &ginkgoFailure{message: message, stack: utils.GetStacktrace(2 + skip)}
+	if spec.failure == nil {
+		spec.failure = failure
+	}
+	intercept := spec.intercept
+	ginkgoMutex.Unlock()
+
+	if intercept {
+		// Stop the execution of the spec body without reporting the failure to Ginkgo
+		panic(failure)
+	}
+}
+
+// instrumentGinkgoReportSpec helper function to report a Ginkgo spec once its `ginkgo.SpecReport` is available.
+//
+//go:linkname instrumentGinkgoReportSpec
+func instrumentGinkgoReportSpec(report any) {
+	ginkgoMutex.Lock()
+	run, spec := ginkgoRun, ginkgoCurrentSpec
+	ginkgoCurrentSpec = nil
+	ginkgoMutex.Unlock()
+	if run == nil {
+		return
+	}
+
+	specReport := getGinkgoSpecReport(report)
+	if specReport == nil {
+		return
+	}
+	if spec != nil {
+		spec.finish(specReport)
+		return
+	}
+
+	// The spec body was not executed (pending, not focused, filtered out or failed in a setup node)
+	run.reportSpec(specReport)
+}
+
+// wrapGinkgoSpecArgs returns a copy of the given arguments with the spec bodies wrapped to be reported and retried.
+func wrapGinkgoSpecArgs(args []any) []any {
+	newArgs := slices.Clone(args)
+	for idx, arg := range newArgs {
+		fn := reflect.ValueOf(arg)
+		// Spec bodies don't return anything (the functions returning the entry descriptions of a table are not bodies)
+		if fn.Kind() != reflect.Func || fn.IsNil() || fn.Type().NumOut() > 0 {
+			continue
+		}
+		newArgs[idx] = wrapGinkgoSpecBody(fn).Interface()
+	}
+	return newArgs
+}
+
+// wrapGinkgoSpecBody returns a function with the same signature as the given spec body, which runs it as a spec.
+func wrapGinkgoSpecBody(body reflect.Value) reflect.Value {
+	bodyFunc := runtime.FuncForPC(body.Pointer())
+	isVariadic := body.Type().IsVariadic()
+	return reflect.MakeFunc(body.Type(), func(in []reflect.Value) []reflect.Value {
+		runGinkgoSpec(bodyFunc, func() {
+			if isVariadic {
+				body.CallSlice(in)
+			} else {
+				body.Call(in)
+			}
+		})
+		return nil
+	})
+}
+
+// runGinkgoSpec runs the body of the current Ginkgo spec with the CI Visibility features.
+func runGinkgoSpec(bodyFunc *runtime.Func, body func()) {
+	ginkgoMutex.Lock()
+	run, previous := ginkgoRun, ginkgoCurrentSpec
+	ginkgoCurrentSpec = nil
+	ginkgoMutex.Unlock()
+	if run == nil {
+		body()
+		return
+	}
+
+	// Ginkgo runs the spec again with the FlakeAttempts and MustPassRepeatedly decorators,
+	// the previous attempt is closed before the spec report is available
+	if previous != nil {
+		previous.finish(nil)
+	}
+
+	report := getGinkgoSpecReport(run.currentSpecReport())
+	if report == nil {
+		body()
+		return
+	}
+	spec := run.newSpec(report, bodyFunc)
+	ginkgoMutex.Lock()
+	ginkgoCurrentSpec = spec
+	ginkgoMutex.Unlock()
+	spec.runBody(body)
+}
+
+// newSpec creates the CI Visibility state of a spec, reported in the suite of its container hierarchy.
+func (r *ginkgoRunInfo) newSpec(report *ginkgoSpecReport, bodyFunc *runtime.Func) *ginkgoSpec {
+	suiteName := r.description
+	if len(report.containers) > 0 {
+		suiteName = strings.Join(report.containers, " ")
+	}
+
+	ginkgoMutex.Lock()
+	suite, ok := r.suites[suiteName]
+	if !ok {
+		suite = r.module.GetOrCreateSuite(suiteName)
+		r.suites[suiteName] = suite
+	}
+	ginkgoMutex.Unlock()
+
+	return &ginkgoSpec{
+		commonInfo: commonInfo{
+			moduleName: r.module.Name(),
+			suiteName:  suiteName,
+			testName:   report.name,
+		},
+		run:      r,
+		suite:    suite,
+		report:   report,
+		bodyFunc: bodyFunc,
+	}
+}
+
+// reportSpec reports a spec whose body was not executed with the state of its spec report.
+func (r *ginkgoRunInfo) reportSpec(report *ginkgoSpecReport) {
+	spec := r.newSpec(report, nil)
+	test := spec.startExecution(false).test
+	switch status := report.status(); status {
+	case integrations.ResultStatusSkip:
+		reason := report.failureMessage
+		if report.state == "pending" {
+			reason = ginkgoPendingReason
+		} else if reason == "" {
+			reason = ginkgoNotRunReason
+		}
+		test.Close(status, integrations.WithTestSkipReason(reason))
+	case integrations.ResultStatusFail:
+		test.SetError(integrations.WithErrorInfo(report.state, report.message(), report.failureStack))
+		test.SetTag(ext.Error, true)
+		test.Close(status)
+	default:
+		test.Close(status)
+	}
+}
+
+// runBody runs the spec body as many times as required by the additional features.
+func (s *ginkgoSpec) runBody(body func()) {
+	settings := integrations.GetSettings()
+
+	// ensure that the additional features are initialized
+	_ = integrations.GetKnownTests()
+
+	// Check if the spec is new
+	if settings.KnownTestsEnabled {
+		isKnown, hasKnownData := isKnownTest(&s.commonInfo)
+		s.isNew = hasKnownData && !isKnown
+	}
+
+	// Check the test management properties of the spec
+	if settings.TestManagement.Enabled {
+		if data, ok := getTestManagementData(&s.commonInfo); ok && data != nil {
+			s.isQuarantined = data.Quarantined
+			s.isDisabled = data.Disabled
+			s.isAttemptToFix = data.AttemptToFix
+		}
+	}
+
+	// The first execution is created before running the body to check the unskippable status of the spec
+	execution := s.startExecution(false)
+
+	// If the spec is disabled and not an attempt to fix, we just skip it
+	if s.isDisabled && !s.isAttemptToFix {
+		s.closeExecution(execution, integrations.ResultStatusSkip, ginkgoDisabledReason, false)
+		s.run.skip(ginkgoDisabledReason)
+		return
+	}
+
+	// Check if the spec needs to be skipped by ITR
+	if settings.ItrEnabled && settings.TestsSkipping && isSkippableTest(&s.commonInfo) {
+		if execution.test.Context().Value(constants.TestUnskippable) != true {
+			execution.test.SetTag(constants.TestSkippedByITR, "true")
+			s.closeExecution(execution, integrations.ResultStatusSkip, constants.SkippedByITRReason, false)
+			telemetry.ITRSkipped(telemetry.TestEventType)
+			session.SetTag(constants.ITRTestsSkipped, "true")
+			session.SetTag(constants.ITRTestsSkippingCount, numOfTestsSkipped.Add(1))
+			s.run.skip(constants.SkippedByITRReason)
+			return
+		}
+		s.isForcedToRun = true
+		execution.test.SetTag(constants.TestForcedToRun, "true")
+		telemetry.ITRForcedRun(telemetry.TestEventType)
+	}
+
+	s.policy = newGinkgoRetryPolicy(settings, s)
+	s.retryReason = s.policy.reason
+	for index := 0; ; index++ {
+		if index > 0 {
+			execution = s.startExecution(true)
+		}
+
+		// The failures are left to Ginkgo only when they decide the outcome of the spec
+		intercept := s.isQuarantined || s.isDisabled || s.passCount > 0 || !s.policy.isLast(index)
+
+		startTime := time.Now()
+		ginkgoPanic := s.execute(execution, body, intercept)
+		duration := time.Since(startTime)
+
+		// check if is a new EFD test and the duration >= 5 min
+		if s.isNew && duration.Minutes() >= 5 {
+			// Set the EFD retry abort reason
+			execution.test.SetTag(constants.TestEarlyFlakeDetectionRetryAborted, "slow")
+		}
+
+		if ginkgoPanic != nil {
+			// The spec was skipped or aborted, the latest execution is closed with the spec report
+			panic(ginkgoPanic)
+		}
+		if !s.policy.next(index, execution.status, duration) {
+			break
+		}
+		s.closeExecution(execution, execution.status, "", false)
+	}
+
+	switch {
+	case s.isDisabled:
+		s.run.skip(ginkgoDisabledReason)
+	case s.isQuarantined:
+		s.run.skip(ginkgoQuarantinedReason)
+	case s.latest != nil && s.latest.status == integrations.ResultStatusFail && s.passCount == 0:
+		// All the executions failed but the latest failure was intercepted, so let's report it to Ginkgo
+		s.run.fail(s.lastFailure)
+	}
+}
+
+// execute runs the spec body once. When the failures are intercepted they are recovered to decide if the spec must be
+// retried, otherwise they are left to Ginkgo. The panics of `ginkgo.Skip` and `ginkgo.AbortSuite` are returned.
+func (s *ginkgoSpec) execute(execution *ginkgoSpecExecution, body func(), intercept bool) (ginkgoPanic any) {
+	ginkgoMutex.Lock()
+	s.latest = execution
+	s.running, s.intercept, s.failure = true, intercept, nil
+	ginkgoMutex.Unlock()
+
+	completed := false
+	defer func() {
+		ginkgoMutex.Lock()
+		failure := s.failure
+		s.running, s.intercept, s.failure = false, false, nil
+		ginkgoMutex.Unlock()
+		if completed {
+			return
+		}
+
+		if !intercept {
+			// The panic is left to Ginkgo, and the execution is closed with the spec report
+			execution.status = integrations.ResultStatusFail
+			if failure != nil {
+				s.setError(execution, "Fail", failure.message, failure.stack)
+			}
+			return
+		}
+
+		r := recover()
+		switch {
+		case failure != nil:
+			execution.status = integrations.ResultStatusFail
+			s.setError(execution, "Fail", failure.message, failure.stack)
+			s.lastFailure = failure.message
+		case isGinkgoPanic(r):
+			execution.status = integrations.ResultStatusSkip
+			ginkgoPanic = r
+		default:
+			execution.status = integrations.ResultStatusFail
+			s.setError(execution, "panic", fmt.Sprint(r), utils.GetStacktrace(1))
+			s.lastFailure = fmt.Sprintf("Test Panicked: %v", r)
+		}
+	}()
+
+	body()
+	completed = true
+	execution.status = integrations.ResultStatusPass
+	return nil
+}
+
+// startExecution creates the CI Visibility test event of a new execution of the spec.
+func (s *ginkgoSpec) startExecution(isRetry bool) *ginkgoSpecExecution {
+	test := s.suite.CreateTest(s.testName)
+	test.SetTag(constants.TestFramework, ginkgoFramework)
+	if s.run.frameworkVersion != "" {
+		test.SetTag(constants.TestFrameworkVersion, s.run.frameworkVersion)
+	}
+	if s.bodyFunc != nil {
+		test.SetTestFunc(s.bodyFunc)
+	} else if s.report.file != "" {
+		test.SetTag(constants.TestSourceFile, utils.GetRelativePathFromCITagsSourceRoot(s.report.file))
+		test.SetTag(constants.TestSourceStartLine, s.report.line)
+	}
+	if len(s.report.labels) > 0 {
+		test.SetTag(constants.TestGinkgoLabels, strings.Join(s.report.labels, ","))
+	}
+
+	// If the spec is new we tag the test event from early flake detection
+	if s.isNew {
+		test.SetTag(constants.TestIsNew, "true")
+	}
+
+	// If the execution is a retry we tag the test event with the retry reason
+	if isRetry {
+		test.SetTag(constants.TestIsRetry, "true")
+		test.SetTag(constants.TestRetryReason, s.retryReason)
+	}
+
+	// Tag the test management properties of the spec
+	if s.isAttemptToFix {
+		test.SetTag(constants.TestIsAttempToFix, "true")
+	}
+	if s.isQuarantined {
+		test.SetTag(constants.TestIsQuarantined, "true")
+	}
+	if s.isDisabled {
+		test.SetTag(constants.TestIsDisabled, "true")
+	}
+	if s.isForcedToRun {
+		test.SetTag(constants.TestForcedToRun, "true")
+	}
+
+	return &ginkgoSpecExecution{test: test, isRetry: isRetry}
+}
+
+// setError sets the error information of the test event of an execution.
+func (s *ginkgoSpec) setError(execution *ginkgoSpecExecution, errType string, message string, stack string) {
+	if execution.hasError {
+		return
+	}
+	execution.hasError = true
+	execution.test.SetError(integrations.WithErrorInfo(errType, message, stack))
+}
+
+// closeExecution closes the test event of an execution with the given status. The tags summarizing the retries are
+// only set on the latest execution.
+func (s *ginkgoSpec) closeExecution(execution *ginkgoSpecExecution, status integrations.TestResultStatus, skipReason string, latest bool) {
+	s.executions++
+	switch status {
+	case integrations.ResultStatusPass:
+		s.passCount++
+	case integrations.ResultStatusFail:
+		s.failCount++
+	}
+
+	test := execution.test
+	if latest && execution.isRetry {
+		if s.failCount == s.executions {
+			test.SetTag(constants.TestHasFailedAllRetries, "true")
+		} else if s.isAttemptToFix && s.passCount == s.executions {
+			test.SetTag(constants.TestAttemptToFixPassed, "true")
+		}
+	}
+
+	switch status {
+	case integrations.ResultStatusFail:
+		test.SetTag(ext.Error, true)
+		s.suite.SetTag(ext.Error, true)
+		s.suite.Module().SetTag(ext.Error, true)
+		test.Close(status)
+	case integrations.ResultStatusSkip:
+		test.Close(status, integrations.WithTestSkipReason(skipReason))
+	default:
+		test.Close(status)
+	}
+}
+
+// finish closes the latest execution of the spec with the final state of the spec report. The status of the quarantined
+// and disabled specs is the status of the execution, as they are skipped in Ginkgo.
+func (s *ginkgoSpec) finish(report *ginkgoSpecReport) {
+	ginkgoMutex.Lock()
+	execution := s.latest
+	s.latest = nil
+	ginkgoMutex.Unlock()
+	if execution == nil {
+		return
+	}
+
+	status, skipReason := execution.status, ""
+	if report != nil && !s.isQuarantined && !s.isDisabled {
+		// A spec passing with a failed execution was retried, otherwise the final state of the spec
+		// includes the failures and skips of the setup nodes run after the spec body
+		if reportStatus := report.status(); reportStatus != integrations.ResultStatusPass {
+			status = reportStatus
+		}
+		switch status {
+		case integrations.ResultStatusFail:
+			s.setError(execution, report.state, report.message(), report.failureStack)
+		case integrations.ResultStatusSkip:
+			skipReason = report.failureMessage
+		}
+	}
+	s.closeExecution(execution, status, skipReason, true)
+}
+
+// newGinkgoRetryPolicy returns the retry policy of a spec, with the same precedence as the additional features of the tests.
+func newGinkgoRetryPolicy(settings *net.SettingsResponseData, spec *ginkgoSpec) *ginkgoRetryPolicy {
+	policy := &ginkgoRetryPolicy{settings: settings}
+	switch {
+	case settings.TestManagement.Enabled && (spec.isQuarantined || spec.isDisabled):
+		if spec.isAttemptToFix && settings.TestManagement.AttemptToFixRetries > 1 {
+			policy.reason = "attempt_to_fix"
+			policy.retries = settings.TestManagement.AttemptToFixRetries - 1
+		}
+	case settings.EarlyFlakeDetection.Enabled && spec.isNew:
+		policy.reason = "efd"
+	case settings.FlakyTestRetriesEnabled:
+		flakyRetriesSettings := integrations.GetFlakyRetriesSettings()
+		if flakyRetriesSettings.RetryCount > 1 && atomic.LoadInt64(&flakyRetriesSettings.RemainingTotalRetryCount) > 0 {
+			policy.reason = "atr"
+			policy.retries = int(flakyRetriesSettings.RetryCount)
+			policy.flakyRetriesSettings = flakyRetriesSettings
+		}
+	}
+	return policy
+}
+
+// isLast reports whether the execution with the given index is the last one of the spec.
+func (p *ginkgoRetryPolicy) isLast(index int) bool {
+	switch p.reason {
+	case "":
+		return true
+	case "efd":
+		// the number of retries depends on the duration of the first execution
+		return index > 0 && index >= p.retries
+	case "atr":
+		return index >= p.retries || atomic.LoadInt64(&p.flakyRetriesSettings.RemainingTotalRetryCount) <= 0
+	default:
+		return index >= p.retries
+	}
+}
+
+// next reports whether the spec body must be executed again after the execution with the given index.
+func (p *ginkgoRetryPolicy) next(index int, status integrations.TestResultStatus, duration time.Duration) bool {
+	switch p.reason {
+	case "efd":
+		if index == 0 {
+			p.retries = p.earlyFlakeDetectionRetries(duration)
+		}
+		return index < p.retries
+	case "atr":
+		// retry only failures while there are retries left in the total retries budget
+		return status == integrations.ResultStatusFail && index < p.retries &&
+			atomic.AddInt64(&p.flakyRetriesSettings.RemainingTotalRetryCount, -1) >= 0
+	case "attempt_to_fix":
+		return index < p.retries
+	}
+	return false
+}
+
+// earlyFlakeDetectionRetries returns the number of early flake detection retries for the duration of the first execution.
+func (p *ginkgoRetryPolicy) earlyFlakeDetectionRetries(duration time.Duration) int {
+	slowTestRetriesSettings := p.settings.EarlyFlakeDetection.SlowTestRetries
+	durationSecs := duration.Seconds()
+	if durationSecs < 5 {
+		return slowTestRetriesSettings.FiveS
+	} else if durationSecs < 10 {
+		return slowTestRetriesSettings.TenS
+	} else if durationSecs < 30 {
+		return slowTestRetriesSettings.ThirtyS
+	} else if duration.Minutes() < 5 {
+		return slowTestRetriesSettings.FiveM
+	}
+	return 0
+}
+
+// status returns the CI Visibility status of the state of the spec report.
+func (r *ginkgoSpecReport) status() integrations.TestResultStatus {
+	switch r.state {
+	case "passed":
+		return integrations.ResultStatusPass
+	case "pending", "skipped":
+		return integrations.ResultStatusSkip
+	default:
+		return integrations.ResultStatusFail
+	}
+}
+
+// message returns the failure message of the spec report, with the panic forwarded by Ginkgo if any.
+func (r *ginkgoSpecReport) message() string {
+	if r.forwardedPanic != "" {
+		return fmt.Sprintf("%s: %s", r.failureMessage, r.forwardedPanic)
+	}
+	return r.failureMessage
+}
+
+// getGinkgoSpecReport reads the data of a `ginkgo.SpecReport` by reflection.
+func getGinkgoSpecReport(report any) *ginkgoSpecReport {
+	reportValue := reflect.Indirect(reflect.ValueOf(report))
+	if reportValue.Kind() != reflect.Struct {
+		return nil
+	}
+
+	specReport := &ginkgoSpecReport{
+		name: getGinkgoField[string](reportValue, "LeafNodeText"),
+	}
+	specReport.containers = getGinkgoField[[]string](reportValue, "ContainerHierarchyTexts")
+	if location := reportValue.FieldByName("LeafNodeLocation"); location.IsValid() {
+		specReport.file = getGinkgoField[string](location, "FileName")
+		specReport.line = getGinkgoField[int](location, "LineNumber")
+	}
+	if labels := reflect.ValueOf(report).MethodByName("Labels"); labels.IsValid() && labels.Type().NumIn() == 0 {
+		if out := labels.Call(nil); len(out) == 1 {
+			specReport.labels, _ = out[0].Interface().([]string)
+		}
+	}
+	if state := reportValue.FieldByName("State"); state.IsValid() && state.CanInterface() {
+		if stringer, ok := state.Interface().(fmt.Stringer); ok {
+			specReport.state = stringer.String()
+		}
+	}
+	if failure := reportValue.FieldByName("Failure"); failure.IsValid() && failure.Kind() == reflect.Struct {
+		specReport.failureMessage = getGinkgoField[string](failure, "Message")
+		specReport.forwardedPanic = getGinkgoField[string](failure, "ForwardedPanic")
+		if location := failure.FieldByName("Location"); location.IsValid() && location.Kind() == reflect.Struct {
+			specReport.failureStack = getGinkgoField[string](location, "FullStackTrace")
+		}
+	}
+	return specReport
+}
+
+// getGinkgoField returns the value of the exported field with the given name of a struct, or the zero value.
+func getGinkgoField[T any](value reflect.Value, name string) T {
+	var zero T
+	field := value.FieldByName(name)
+	if !field.IsValid() || !field.CanInterface() {
+		return zero
+	}
+	if v, ok := field.Interface().(T); ok {
+		return v
+	}
+	return zero
+}
+
+// isGinkgoPanic checks if a panic value was raised by Ginkgo after recording the outcome of the spec.
+func isGinkgoPanic(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Name() == "GinkgoError" && t.PkgPath() == ginkgoFramework+"/types"
+}
+
+// getGinkgoVersion returns the version of Ginkgo from the build information.
+func getGinkgoVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == ginkgoFramework {
+				return dep.Version
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package gotesting
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/integrations"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils/net"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ginkgoFlakyCount is the number of failures of the "fails once" spec of TestGinkgoSuite.
+var ginkgoFlakyCount = 0
+
+// fakeGinkgoState mirrors the `types.SpecState` of Ginkgo.
+type fakeGinkgoState uint
+
+func (s fakeGinkgoState) String() string {
+	return [...]string{"passed", "failed", "skipped", "pending"}[s]
+}
+
+// fakeGinkgoSpecReport mirrors the fields of the `types.SpecReport` of Ginkgo read by the integration.
+type fakeGinkgoSpecReport struct {
+	ContainerHierarchyTexts []string
+	LeafNodeText            string
+	LeafNodeLocation        struct {
+		FileName   string
+		LineNumber int
+	}
+	State   fakeGinkgoState
+	Failure struct {
+		Message        string
+		ForwardedPanic string
+		Location       struct {
+			FullStackTrace string
+		}
+	}
+	labels []string
+}
+
+func (r fakeGinkgoSpecReport) Labels() []string {
+	return r.labels
+}
+
+// GinkgoError mirrors the panic value of Ginkgo, in a different package.
+type GinkgoError struct{}
+
+func TestGinkgoSpecReport(t *testing.T) {
+	report := fakeGinkgoSpecReport{
+		ContainerHierarchyTexts: []string{"Books", "when loaded"},
+		LeafNodeText:            "has a title",
+		State:                   1,
+		labels:                  []string{"fast", "books"},
+	}
+	report.LeafNodeLocation.FileName = "/src/books_test.go"
+	report.LeafNodeLocation.LineNumber = 42
+	report.Failure.Message = "expected a title"
+	report.Failure.ForwardedPanic = "boom"
+	report.Failure.Location.FullStackTrace = "stack"
+
+	specReport := getGinkgoSpecReport(report)
+	require.NotNil(t, specReport)
+	assert.Equal(t, []string{"Books", "when loaded"}, specReport.containers)
+	assert.Equal(t, "has a title", specReport.name)
+	assert.Equal(t, []string{"fast", "books"}, specReport.labels)
+	assert.Equal(t, "/src/books_test.go", specReport.file)
+	assert.Equal(t, 42, specReport.line)
+	assert.Equal(t, "failed", specReport.state)
+	assert.Equal(t, integrations.ResultStatusFail, specReport.status())
+	assert.Equal(t, "expected a title: boom", specReport.message())
+	assert.Equal(t, "stack", specReport.failureStack)
+
+	assert.Equal(t, integrations.ResultStatusPass, (&ginkgoSpecReport{state: "passed"}).status())
+	assert.Equal(t, integrations.ResultStatusSkip, (&ginkgoSpecReport{state: "pending"}).status())
+	assert.Equal(t, integrations.ResultStatusSkip, (&ginkgoSpecReport{state: "skipped"}).status())
+	assert.Equal(t, integrations.ResultStatusFail, (&ginkgoSpecReport{state: "panicked"}).status())
+	assert.Nil(t, getGinkgoSpecReport(nil))
+	assert.Nil(t, getGinkgoSpecReport("report"))
+
+	assert.False(t, isGinkgoPanic(GinkgoError{}))
+	assert.False(t, isGinkgoPanic(errors.New("boom")))
+	assert.False(t, isGinkgoPanic(nil))
+}
+
+func TestGinkgoSpecArgs(t *testing.T) {
+	calls := 0
+	body := func() { calls++ }
+	contextBody := func(ctx context.Context) { calls++ }
+	entryBody := func(name string, values ...int) { calls += len(values) }
+	description := func(name string, values ...int) string { return name }
+	args := []any{"label", body, contextBody, entryBody, description, time.Second}
+
+	wrapped := wrapGinkgoSpecArgs(args)
+	require.Len(t, wrapped, len(args))
+	assert.Equal(t, "label", wrapped[0])
+	assert.Equal(t, time.Second, wrapped[5])
+	for idx := range args {
+		assert.IsType(t, args[idx], wrapped[idx])
+	}
+
+	// the functions returning the entry descriptions and the original arguments are untouched
+	assert.Equal(t, reflect.ValueOf(description).Pointer(), reflect.ValueOf(wrapped[4]).Pointer())
+	assert.Equal(t, reflect.ValueOf(body).Pointer(), reflect.ValueOf(args[1]).Pointer())
+	assert.NotEqual(t, reflect.ValueOf(body).Pointer(), reflect.ValueOf(wrapped[1]).Pointer())
+
+	// without a Ginkgo suite running the wrapped bodies just call the original ones
+	wrapped[1].(func())()
+	wrapped[2].(func(context.Context))(context.Background())
+	wrapped[3].(func(string, ...int))("entry", 1, 2, 3)
+	assert.Equal(t, 5, calls)
+}
+
+func TestGinkgoRetryPolicy(t *testing.T) {
+	settings := &net.SettingsResponseData{}
+	settings.EarlyFlakeDetection.SlowTestRetries.FiveS = 10
+	settings.EarlyFlakeDetection.SlowTestRetries.TenS = 5
+	settings.EarlyFlakeDetection.SlowTestRetries.ThirtyS = 3
+	settings.EarlyFlakeDetection.SlowTestRetries.FiveM = 2
+
+	// early flake detection retries depend on the duration of the first execution
+	efd := &ginkgoRetryPolicy{reason: "efd", settings: settings}
+	assert.False(t, efd.isLast(0))
+	assert.True(t, efd.next(0, integrations.ResultStatusPass, 7*time.Second))
+	assert.Equal(t, 5, efd.retries)
+	assert.False(t, efd.isLast(4))
+	assert.True(t, efd.isLast(5))
+	assert.False(t, efd.next(5, integrations.ResultStatusFail, time.Second))
+	assert.Equal(t, 0, efd.earlyFlakeDetectionRetries(10*time.Minute))
+
+	// automatic test retries only retry failures while there is budget left
+	flakyRetriesSettings := &integrations.FlakyRetriesSetting{RetryCount: 3, TotalRetryCount: 4, RemainingTotalRetryCount: 2}
+	atr := &ginkgoRetryPolicy{reason: "atr", retries: 3, settings: settings, flakyRetriesSettings: flakyRetriesSettings}
+	assert.False(t, atr.isLast(0))
+	assert.False(t, atr.next(0, integrations.ResultStatusPass, time.Second))
+	assert.True(t, atr.next(0, integrations.ResultStatusFail, time.Second))
+	assert.True(t, atr.next(1, integrations.ResultStatusFail, time.Second))
+	assert.True(t, atr.isLast(2))
+	assert.False(t, atr.next(2, integrations.ResultStatusFail, time.Second))
+	assert.True(t, atr.isLast(3))
+
+	// attempt to fix runs all the executions
+	attemptToFix := &ginkgoRetryPolicy{reason: "attempt_to_fix", retries: 2, settings: settings}
+	assert.True(t, attemptToFix.next(0, integrations.ResultStatusPass, time.Second))
+	assert.True(t, attemptToFix.next(1, integrations.ResultStatusFail, time.Second))
+	assert.True(t, attemptToFix.isLast(2))
+	assert.False(t, attemptToFix.next(2, integrations.ResultStatusPass, time.Second))
+
+	none := &ginkgoRetryPolicy{settings: settings}
+	assert.True(t, none.isLast(0))
+	assert.False(t, none.next(0, integrations.ResultStatusFail, time.Second))
+}
+
+// The following functions reproduce the advice of the Orchestrion aspects of Ginkgo (see orchestrion.yml), which is
+// injected in the bodies of `ginkgo.RunSpecs`, `ginkgo.Fail`, `ginkgo.It` and `ginkgo.DescribeTable`.
+
+func ginkgoRunSpecs(t ginkgo.GinkgoTestingT, description string) bool {
+	defer instrumentGinkgoRunSpecs(t, description, func() any { return ginkgo.CurrentSpecReport() }, ginkgo.Fail, ginkgo.Skip)()
+	ginkgo.ReportAfterEach(func(report ginkgo.SpecReport) { instrumentGinkgoReportSpec(report) })
+	return ginkgo.RunSpecs(t, description)
+}
+
+func ginkgoFail(message string) {
+	instrumentGinkgoFail(message)
+	ginkgo.Fail(message)
+}
+
+func ginkgoIt(text string, args ...any) bool {
+	return ginkgo.It(text, instrumentGinkgoSpecArgs(args)...)
+}
+
+func ginkgoDescribeTable(description string, args ...any) bool {
+	return ginkgo.DescribeTable(description, instrumentGinkgoSpecArgs(args)...)
+}
+
+// TestGinkgoSuite runs a Ginkgo suite with the instrumentation of Ginkgo, it's only run by the TestGinkgo scenario
+// (see testcontroller_test.go), which checks the reported specs and their retries. The suite fails because of the
+// "always fails" spec, but the test must not be retried as Ginkgo can't run a suite twice.
+func TestGinkgoSuite(t *testing.T) {
+	ginkgo.Describe("Books", func() {
+		ginkgoIt("passes", func() {})
+		ginkgoIt("fails once", func() {
+			if ginkgoFlakyCount < 1 {
+				ginkgoFlakyCount++
+				ginkgoFail("flaky failure")
+			}
+		})
+		ginkgoIt("always fails", func() {
+			ginkgoFail("persistent failure")
+		})
+		ginkgo.PIt("is pending", func() {})
+	})
+	ginkgoDescribeTable("Sums",
+		func(a, b, sum int) {
+			if a+b != sum {
+				ginkgoFail("wrong sum")
+			}
+		},
+		ginkgo.Entry("1+1", 1, 1, 2),
+		ginkgo.Entry("2+2", 2, 2, 4),
+	)
+
+	ginkgoRunSpecs(t, "Ginkgo suite")
+}
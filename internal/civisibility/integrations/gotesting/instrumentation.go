@@ -45,6 +45,9 @@ type (
 		allAttemptsPassed           bool              // flag to check if all attempts passed for a test marked as attempt to fix
 		allRetriesFailed            bool              // flag to check if all retries failed for a test
 		hasAdditionalFeatureWrapper bool              // flag to check if the current execution is part of an additional feature wrapper
+		isGinkgoSuite               bool              // flag to check if the test runs a Ginkgo suite (the specs are retried individually)
+		hasFailedSubtestRetries     bool              // flag to check if a subtest failed after its own retries (the test is not retried again)
+		testifyTest                 *TestifyTest      // testify suite data of the test (the parent chain of a retried test copy is not available)
 	}
 
 	// runTestWithRetryOptions contains the options for calling runTestWithRetry function
@@ -148,6 +151,32 @@ func deleteTestMetadata(tb testing.TB) {
 	delete(ciVisibilityTestMetadata, reflect.ValueOf(tb).UnsafePointer())
 }
 
+// getParentTestMetadata retrieves the CI visibility test metadata associated with the parent of a given *testing.T
+func getParentTestMetadata(t *testing.T) *testExecutionMetadata {
+	testPrivateFields := getTestPrivateFields(t)
+	if testPrivateFields == nil || testPrivateFields.parent == nil || *testPrivateFields.parent == nil {
+		return nil
+	}
+	return getTestMetadataFromPointer(*testPrivateFields.parent)
+}
+
+// propagateTestMetadata propagates the execution flags of a parent test execution metadata to a subtest execution metadata
+func propagateTestMetadata(execMeta *testExecutionMetadata, parentExecMeta *testExecutionMetadata) {
+	if parentExecMeta == nil {
+		return
+	}
+	execMeta.isANewTest = execMeta.isANewTest || parentExecMeta.isANewTest
+	execMeta.isARetry = execMeta.isARetry || parentExecMeta.isARetry
+	execMeta.isEFDExecution = execMeta.isEFDExecution || parentExecMeta.isEFDExecution
+	execMeta.isATRExecution = execMeta.isATRExecution || parentExecMeta.isATRExecution
+	execMeta.isQuarantined = execMeta.isQuarantined || parentExecMeta.isQuarantined
+	execMeta.isDisabled = execMeta.isDisabled || parentExecMeta.isDisabled
+	execMeta.isAttemptToFix = execMeta.isAttemptToFix || parentExecMeta.isAttemptToFix
+	if execMeta.testifyTest == nil {
+		execMeta.testifyTest = parentExecMeta.testifyTest
+	}
+}
+
 // checkIfCIVisibilityExitIsRequiredByPanic checks the additional features settings to decide if we allow individual tests to panic or not
 func checkIfCIVisibilityExitIsRequiredByPanic() bool {
 	// Apply additional features
@@ -281,6 +310,20 @@ func applyFlakyTestRetriesAdditionalFeature(targetFunc func(*testing.T)) (func(*
 	return targetFunc, false
 }
 
+// applySubtestFlakyTestRetries applies the flaky test retries feature to a subtest as a wrapper of a func(*testing.T).
+// The subtests of an early flake detection or test management execution are not retried, they are executed again with
+// their parent test.
+func applySubtestFlakyTestRetries(settings *net.SettingsResponseData, parentExecMeta *testExecutionMetadata, targetFunc func(*testing.T)) (func(*testing.T), bool) {
+	if !settings.FlakyTestRetriesEnabled {
+		return targetFunc, false
+	}
+	if parentExecMeta != nil && (parentExecMeta.isEFDExecution || parentExecMeta.isQuarantined ||
+		parentExecMeta.isDisabled || parentExecMeta.isAttemptToFix || parentExecMeta.isGinkgoSuite) {
+		return targetFunc, false
+	}
+	return applyFlakyTestRetriesAdditionalFeature(targetFunc)
+}
+
 // applyEarlyFlakeDetectionAdditionalFeature applies the early flake detection feature as a wrapper of a func(*testing.T)
 func applyEarlyFlakeDetectionAdditionalFeature(testInfo *commonInfo, targetFunc func(*testing.T), settings *net.SettingsResponseData) (func(*testing.T), bool) {
 	isKnown, hasKnownData := isKnownTest(testInfo)
@@ -397,10 +440,10 @@ func runTestWithRetry(options *runTestWithRetryOptions) {
 	// Set this func as a helper func of t
 	options.t.Helper()
 	for {
-		// Clear the matcher subnames map before each execution to avoid subname tests being called "parent/subname#NN" due to retries
+		// Clear the matcher subnames of the test before each execution to avoid subname tests being called "parent/subname#NN" due to retries
 		matcher := getTestContextMatcherPrivateFields(options.t)
 		if matcher != nil {
-			matcher.ClearSubNames()
+			matcher.ClearSubNamesOf(options.t.Name())
 		}
 
 		// Increment execution index
@@ -427,15 +470,7 @@ func runTestWithRetry(options *runTestWithRetryOptions) {
 		execMeta.hasAdditionalFeatureWrapper = true
 
 		// Propagate set tags from a parent wrapper
-		if originalExecMeta != nil {
-			execMeta.isANewTest = execMeta.isANewTest || originalExecMeta.isANewTest
-			execMeta.isARetry = execMeta.isARetry || originalExecMeta.isARetry
-			execMeta.isEFDExecution = execMeta.isEFDExecution || originalExecMeta.isEFDExecution
-			execMeta.isATRExecution = execMeta.isATRExecution || originalExecMeta.isATRExecution
-			execMeta.isQuarantined = execMeta.isQuarantined || originalExecMeta.isQuarantined
-			execMeta.isDisabled = execMeta.isDisabled || originalExecMeta.isDisabled
-			execMeta.isAttemptToFix = execMeta.isAttemptToFix || originalExecMeta.isAttemptToFix
-		}
+		propagateTestMetadata(execMeta, originalExecMeta)
 
 		// If we are in a retry execution, set the `isARetry` flag
 		if executionIndex > 0 {
@@ -507,8 +542,9 @@ func runTestWithRetry(options *runTestWithRetryOptions) {
 		// Update lastPtrToLocalT
 		lastPtrToLocalT = ptrToLocalT
 
-		// Decide whether to continue
-		if !options.shouldRetry(ptrToLocalT, executionIndex, retryCount) {
+		// Decide whether to continue (a Ginkgo suite can't run twice in the same process, its specs are retried instead,
+		// and a test with a subtest that failed after its own retries is not retried again)
+		if execMeta.isGinkgoSuite || execMeta.hasFailedSubtestRetries || !options.shouldRetry(ptrToLocalT, executionIndex, retryCount) {
 			break
 		}
 	}
@@ -643,15 +679,7 @@ func applyTestManagementTestsFeature(testInfo *commonInfo, targetFunc func(*test
 				execMeta.allRetriesFailed = atomic.LoadInt32(&allRetriesFailed) == 1
 
 				// Propagate any flags set in the original test metadata.
-				if originalExecMeta != nil {
-					execMeta.isANewTest = execMeta.isANewTest || originalExecMeta.isANewTest
-					execMeta.isARetry = execMeta.isARetry || originalExecMeta.isARetry
-					execMeta.isEFDExecution = execMeta.isEFDExecution || originalExecMeta.isEFDExecution
-					execMeta.isATRExecution = execMeta.isATRExecution || originalExecMeta.isATRExecution
-					execMeta.isQuarantined = execMeta.isQuarantined || originalExecMeta.isQuarantined
-					execMeta.isDisabled = execMeta.isDisabled || originalExecMeta.isDisabled
-					execMeta.isAttemptToFix = execMeta.isAttemptToFix || originalExecMeta.isAttemptToFix
-				}
+				propagateTestMetadata(execMeta, originalExecMeta)
 			},
 		})
 	}, true
@@ -22,6 +22,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/integrations"
 	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/integrations/gotesting/coverage"
 	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils"
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/utils/telemetry"
 )
 
 // ******************************************************************************************************************
@@ -122,147 +123,224 @@ func instrumentTestingTFunc(f func(*testing.T)) func(*testing.T) {
 
 	instrumentedFn := func(t *testing.T) {
 		// Check if we have testify suite data related to this test
+		testModuleName, testSuiteName, testFunc := moduleName, suiteName, originalFunc
+		parentExecMeta := getParentTestMetadata(t)
 		testifyData := getTestifyTest(t)
+		if testifyData == nil && parentExecMeta != nil {
+			// the parent could be a retried copy of the test without the parent chain, so we use the data resolved by the parent
+			testifyData = parentExecMeta.testifyTest
+		}
 		if testifyData != nil {
 			// If we have testify data, we need to extract the module and suite name from the testify suite
-			moduleName = testifyData.moduleName
-			suiteName = testifyData.suiteName
+			// and we use the method function from testify so the test source is properly set
+			testModuleName, testSuiteName, testFunc = testifyData.moduleName, testifyData.suiteName, testifyData.methodFunc
 		}
 
-		// Initialize module counters if not already present.
-		if _, ok := modulesCounters[moduleName]; !ok {
-			var v int32
-			modulesCounters[moduleName] = &v
-		}
-		// Increment the test count in the module.
-		atomic.AddInt32(modulesCounters[moduleName], 1)
+		// Get the settings response for this session
+		settings := integrations.GetSettings()
 
-		// Initialize suite counters if not already present.
-		if _, ok := suitesCounters[suiteName]; !ok {
-			var v int32
-			suitesCounters[suiteName] = &v
-		}
-		// Increment the test count in the suite.
-		atomic.AddInt32(suitesCounters[suiteName], 1)
+		// Check if the subtest is going to be skipped by ITR
+		testSkippedByITR := settings.ItrEnabled && settings.TestsSkipping &&
+			isSkippableTest(&commonInfo{moduleName: testModuleName, suiteName: testSuiteName, testName: t.Name()})
 
-		// Create or retrieve the module, suite, and test for CI visibility.
-		module := session.GetOrCreateModule(moduleName)
-		suite := module.GetOrCreateSuite(suiteName)
-		test := suite.CreateTest(t.Name())
+		executeFn := func(t *testing.T) {
+			// Initialize module counters if not already present.
+			if _, ok := modulesCounters[testModuleName]; !ok {
+				var v int32
+				modulesCounters[testModuleName] = &v
+			}
+			// Increment the test count in the module.
+			atomic.AddInt32(modulesCounters[testModuleName], 1)
 
-		// If we have testify data we use the method function from testify so the test source is properly set
-		if testifyData != nil {
-			test.SetTestFunc(testifyData.methodFunc)
-		} else {
-			// If not, let's set the original function
-			test.SetTestFunc(originalFunc)
-		}
+			// Initialize suite counters if not already present.
+			if _, ok := suitesCounters[testSuiteName]; !ok {
+				var v int32
+				suitesCounters[testSuiteName] = &v
+			}
+			// Increment the test count in the suite.
+			atomic.AddInt32(suitesCounters[testSuiteName], 1)
 
-		// Get the metadata regarding the execution (in case is already created from the additional features)
-		execMeta := getTestMetadata(t)
-		if execMeta == nil {
-			// in case there's no additional features then we create the metadata for this execution and defer the disposal
-			execMeta = createTestMetadata(t)
-			defer deleteTestMetadata(t)
-		}
+			// Create or retrieve the module, suite, and test for CI visibility.
+			module := session.GetOrCreateModule(testModuleName)
+			suite := module.GetOrCreateSuite(testSuiteName)
+			test := suite.CreateTest(t.Name())
+			test.SetTestFunc(testFunc)
 
-		// Because this is a subtest let's propagate some execution metadata from the parent test
-		testPrivateFields := getTestPrivateFields(t)
-		if testPrivateFields != nil && testPrivateFields.parent != nil {
-			parentExecMeta := getTestMetadataFromPointer(*testPrivateFields.parent)
-			if parentExecMeta != nil {
-				execMeta.isANewTest = execMeta.isANewTest || parentExecMeta.isANewTest
-				execMeta.isARetry = execMeta.isARetry || parentExecMeta.isARetry
-				execMeta.isEFDExecution = execMeta.isEFDExecution || parentExecMeta.isEFDExecution
-				execMeta.isATRExecution = execMeta.isATRExecution || parentExecMeta.isATRExecution
-				execMeta.isQuarantined = execMeta.isQuarantined || parentExecMeta.isQuarantined
-				execMeta.isDisabled = execMeta.isDisabled || parentExecMeta.isDisabled
-				execMeta.isAttemptToFix = execMeta.isAttemptToFix || parentExecMeta.isAttemptToFix
+			// Get the metadata regarding the execution (in case is already created from the additional features)
+			execMeta := getTestMetadata(t)
+			if execMeta == nil {
+				// in case there's no additional features then we create the metadata for this execution and defer the disposal
+				execMeta = createTestMetadata(t)
+				defer deleteTestMetadata(t)
 			}
-		}
 
-		// Set the CI visibility test.
-		execMeta.test = test
+			// Because this is a subtest let's propagate some execution metadata from the parent test
+			propagateTestMetadata(execMeta, getParentTestMetadata(t))
 
-		// If the execution is for a new test we tag the test event from early flake detection
-		if execMeta.isANewTest {
-			// Set the is new test tag
-			test.SetTag(constants.TestIsNew, "true")
-		}
+			// Set the CI visibility test and the testify data for the subtests.
+			execMeta.test = test
+			execMeta.testifyTest = testifyData
 
-		// If the execution is a retry we tag the test event
-		if execMeta.isARetry {
-			// Set the retry tag
-			test.SetTag(constants.TestIsRetry, "true")
-
-			// If the execution is an EFD execution we tag the test event reason
-			if execMeta.isEFDExecution {
-				// Set the EFD as the retry reason
-				test.SetTag(constants.TestRetryReason, "efd")
-			} else if execMeta.isATRExecution {
-				// Set the ATR as the retry reason
-				test.SetTag(constants.TestRetryReason, "atr")
-			} else if execMeta.isAttemptToFix {
-				// Set the attempt to fix as the retry reason
-				test.SetTag(constants.TestRetryReason, "attempt_to_fix")
+			// If the execution is for a new test we tag the test event from early flake detection
+			if execMeta.isANewTest {
+				// Set the is new test tag
+				test.SetTag(constants.TestIsNew, "true")
 			}
-		}
 
-		// If the test is an attempt to fix we tag the test event
-		if execMeta.isAttemptToFix {
-			test.SetTag(constants.TestIsAttempToFix, "true")
-		}
+			// If the execution is a retry we tag the test event
+			if execMeta.isARetry {
+				// Set the retry tag
+				test.SetTag(constants.TestIsRetry, "true")
+
+				// If the execution is an EFD execution we tag the test event reason
+				if execMeta.isEFDExecution {
+					// Set the EFD as the retry reason
+					test.SetTag(constants.TestRetryReason, "efd")
+				} else if execMeta.isATRExecution {
+					// Set the ATR as the retry reason
+					test.SetTag(constants.TestRetryReason, "atr")
+				} else if execMeta.isAttemptToFix {
+					// Set the attempt to fix as the retry reason
+					test.SetTag(constants.TestRetryReason, "attempt_to_fix")
+				}
+			}
 
-		// If the test is quarantined we tag the test event
-		if execMeta.isQuarantined {
-			test.SetTag(constants.TestIsQuarantined, "true")
-		}
+			// If the test is an attempt to fix we tag the test event
+			if execMeta.isAttemptToFix {
+				test.SetTag(constants.TestIsAttempToFix, "true")
+			}
 
-		// If the test is disabled we tag the test event
-		if execMeta.isDisabled {
-			test.SetTag(constants.TestIsDisabled, "true")
-		}
+			// If the test is quarantined we tag the test event
+			if execMeta.isQuarantined {
+				test.SetTag(constants.TestIsQuarantined, "true")
+			}
+
+			// If the test is disabled we tag the test event
+			if execMeta.isDisabled {
+				test.SetTag(constants.TestIsDisabled, "true")
+			}
 
-		defer func() {
-			if r := recover(); r != nil {
-				// Handle panic and set error information.
-				if execMeta.isARetry && execMeta.isLastRetry && execMeta.allRetriesFailed {
-					test.SetTag(constants.TestHasFailedAllRetries, "true")
+			// Check if the subtest needs to be skipped by ITR
+			if testSkippedByITR {
+				// check if the subtest was marked as unskippable
+				if test.Context().Value(constants.TestUnskippable) != true {
+					test.SetTag(constants.TestSkippedByITR, "true")
+					test.Close(integrations.ResultStatusSkip, integrations.WithTestSkipReason(constants.SkippedByITRReason))
+					telemetry.ITRSkipped(telemetry.TestEventType)
+					session.SetTag(constants.ITRTestsSkipped, "true")
+					session.SetTag(constants.ITRTestsSkippingCount, numOfTestsSkipped.Add(1))
+					checkModuleAndSuite(module, suite)
+					t.Skip(constants.SkippedByITRReason)
+					return
 				}
-				test.SetError(integrations.WithErrorInfo("panic", fmt.Sprint(r), utils.GetStacktrace(1)))
-				test.Close(integrations.ResultStatusFail)
-				checkModuleAndSuite(module, suite)
-				// this is not an internal test. Retries are not applied to subtest (because the parent internal test is going to be retried)
-				// so for this case we avoid closing CI Visibility, but we don't stop the panic from happening.
-				// it will be handled by `t.Run`
-				if checkIfCIVisibilityExitIsRequiredByPanic() {
-					integrations.ExitCiVisibility()
+				test.SetTag(constants.TestForcedToRun, "true")
+				telemetry.ITRForcedRun(telemetry.TestEventType)
+			}
+
+			// Check if the coverage is enabled
+			var tCoverage coverage.TestCoverage
+			var tParentOldBarrier chan bool
+			if settings.CodeCoverage && coverage.CanCollect() {
+				// set the subtest coverage collector
+				testFile, _ := testFunc.FileLine(testFunc.Entry())
+				tCoverage = coverage.NewTestCoverage(
+					session.SessionID(),
+					module.ModuleID(),
+					suite.SuiteID(),
+					test.TestID(),
+					testFile)
+
+				// now we need to disable parallelism for the subtest in order to collect the test coverage
+				tParent := getTestParentPrivateFields(t)
+				if tParent != nil && tParent.barrier != nil {
+					tParentOldBarrier = *tParent.barrier
+					*tParent.barrier = nil
 				}
-				panic(r)
 			}
-			// Normal finalization: determine the test result based on its state.
-			if t.Failed() {
-				if execMeta.isARetry && execMeta.isLastRetry && execMeta.allRetriesFailed {
-					test.SetTag(constants.TestHasFailedAllRetries, "true")
+
+			defer func() {
+				if tCoverage != nil {
+					// Collect coverage after subtest execution so we can calculate the diff comparing to the baseline.
+					tCoverage.CollectCoverageAfterTestExecution()
+
+					// now we restore the original parent barrier
+					tParent := getTestParentPrivateFields(t)
+					if tParent != nil && tParent.barrier != nil {
+						*tParent.barrier = tParentOldBarrier
+					}
 				}
-				test.SetTag(ext.Error, true)
-				suite.SetTag(ext.Error, true)
-				module.SetTag(ext.Error, true)
-				test.Close(integrations.ResultStatusFail)
-			} else if t.Skipped() {
-				test.Close(integrations.ResultStatusSkip)
-			} else {
-				if execMeta.isARetry && execMeta.isLastRetry && execMeta.allAttemptsPassed {
-					test.SetTag(constants.TestAttemptToFixPassed, "true")
+
+				if r := recover(); r != nil {
+					// Handle panic and set error information.
+					execMeta.panicData = r
+					execMeta.panicStacktrace = utils.GetStacktrace(1)
+					if execMeta.isARetry && execMeta.isLastRetry && execMeta.allRetriesFailed {
+						test.SetTag(constants.TestHasFailedAllRetries, "true")
+					}
+					test.SetError(integrations.WithErrorInfo("panic", fmt.Sprint(r), execMeta.panicStacktrace))
+					test.Close(integrations.ResultStatusFail)
+					checkModuleAndSuite(module, suite)
+					if execMeta.hasAdditionalFeatureWrapper {
+						// the subtest is retried, so we let the retry wrapper handle the panic
+						return
+					}
+					// this subtest is not retried, and the parent internal test may be retried
+					// so for this case we avoid closing CI Visibility, but we don't stop the panic from happening.
+					// it will be handled by `t.Run`
+					if checkIfCIVisibilityExitIsRequiredByPanic() {
+						integrations.ExitCiVisibility()
+					}
+					panic(r)
+				}
+				// Normal finalization: determine the test result based on its state.
+				if t.Failed() {
+					if execMeta.isARetry && execMeta.isLastRetry && execMeta.allRetriesFailed {
+						test.SetTag(constants.TestHasFailedAllRetries, "true")
+					}
+					test.SetTag(ext.Error, true)
+					suite.SetTag(ext.Error, true)
+					module.SetTag(ext.Error, true)
+					test.Close(integrations.ResultStatusFail)
+				} else if t.Skipped() {
+					test.Close(integrations.ResultStatusSkip)
+				} else {
+					if execMeta.isARetry && execMeta.isLastRetry && execMeta.allAttemptsPassed {
+						test.SetTag(constants.TestAttemptToFixPassed, "true")
+					}
+					test.Close(integrations.ResultStatusPass)
 				}
-				test.Close(integrations.ResultStatusPass)
+				checkModuleAndSuite(module, suite)
+			}()
+
+			if tCoverage != nil {
+				// Collect coverage before subtest execution so we can register a baseline.
+				tCoverage.CollectCoverageBeforeTestExecution()
 			}
-			checkModuleAndSuite(module, suite)
 
-		}()
+			// Execute the original test function.
+			f(t)
+		}
+
+		// If the subtest is going to be skipped by ITR then we don't apply the flaky test retries
+		if !testSkippedByITR {
+			if retryFn, ok := applySubtestFlakyTestRetries(settings, parentExecMeta, executeFn); ok {
+				// Create the metadata of the subtest to propagate the parent execution metadata to the retries
+				execMeta := createTestMetadata(t)
+				defer deleteTestMetadata(t)
+				execMeta.testifyTest = testifyData
+				propagateTestMetadata(execMeta, parentExecMeta)
+
+				retryFn(t)
+
+				// The subtest failed after its own retries, so the parent test must not be retried again
+				if t.Failed() && parentExecMeta != nil {
+					parentExecMeta.hasFailedSubtestRetries = true
+				}
+				return
+			}
+		}
 
-		// Execute the original test function.
-		f(t)
+		executeFn(t)
 	}
 
 	setInstrumentationMetadata(runtime.FuncForPC(reflect.Indirect(reflect.ValueOf(instrumentedFn)).Pointer()), &instrumentationMetadata{IsInternal: true})
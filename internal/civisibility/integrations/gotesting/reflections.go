@@ -10,6 +10,7 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -205,8 +206,8 @@ type contextMatcher struct {
 	subNames *map[string]int32
 }
 
-// ClearSubNames clears the subname map used for creating unique names for subtests
-func (c *contextMatcher) ClearSubNames() {
+// ClearSubNamesOf clears the subnames of a test from the map used for creating unique names for subtests
+func (c *contextMatcher) ClearSubNamesOf(testName string) {
 	if c.mu == nil {
 		return
 	}
@@ -215,7 +216,12 @@ func (c *contextMatcher) ClearSubNames() {
 	if c.subNames == nil || *c.subNames == nil {
 		return
 	}
-	clear(*c.subNames)
+	prefix := testName + "/"
+	for name := range *c.subNames {
+		if strings.HasPrefix(name, prefix) {
+			delete(*c.subNames, name)
+		}
+	}
 }
 
 // getTestContextMatcherPrivateFields is a method to retrieve all required privates field from
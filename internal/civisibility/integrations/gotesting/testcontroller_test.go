@@ -7,6 +7,7 @@ package gotesting
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
@@ -33,7 +34,10 @@ func TestMain(m *testing.M) {
 	log.SetLevel(log.LevelDebug)
 
 	// We need to spawn separated test process for each scenario
-	scenarios := []string{"TestFlakyTestRetries", "TestEarlyFlakeDetection", "TestFlakyTestRetriesAndEarlyFlakeDetection", "TestIntelligentTestRunner", "TestManagementTests"}
+	scenarios := []string{"TestFlakyTestRetries", "TestEarlyFlakeDetection", "TestFlakyTestRetriesAndEarlyFlakeDetection", "TestIntelligentTestRunner", "TestManagementTests", "TestGinkgo"}
+
+	// The Ginkgo suite fails on purpose, so it's only run by its own scenario
+	flag.Set("test.skip", "^TestGinkgoSuite$")
 
 	if internal.BoolEnv(scenarios[0], false) {
 		fmt.Printf("Scenario %s started.\n", scenarios[0])
@@ -50,6 +54,9 @@ func TestMain(m *testing.M) {
 	} else if internal.BoolEnv(scenarios[4], false) {
 		fmt.Printf("Scenario %s started.\n", scenarios[4])
 		runTestManagementTests(m)
+	} else if internal.BoolEnv(scenarios[5], false) {
+		fmt.Printf("Scenario %s started.\n", scenarios[5])
+		runGinkgoTests(m)
 	} else {
 		fmt.Println("Starting tests...")
 		for _, v := range scenarios {
@@ -100,6 +107,8 @@ func runFlakyTestRetriesTests(m *testing.M) {
 	currentM = m
 	mTracer = integrations.InitializeCIVisibilityMock()
 
+	testSubtestRetryWithFailCount = 2 // this makes TestSubtestRetryWithFail/sub01 to fail twice before passing
+
 	// execute the tests, we are expecting some tests to fail and check the assertion later
 	exitCode := RunM(m)
 	if exitCode != 0 {
@@ -111,14 +120,16 @@ func runFlakyTestRetriesTests(m *testing.M) {
 
 	// 1 session span
 	// 1 module span
-	// 4 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite and reflections_test.go)
+	// 5 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite, reflections_test.go and ginkgo_test.go)
 	// 5 tests from reflections_test.go
+	// 3 tests from ginkgo_test.go
 	// 1 TestMyTest01
 	// 1 TestMyTest02 + 2 subtests
 	// 1 Test_Foo + 3 subtests
 	// 1 TestSkip
 	// 1 TestRetryWithPanic + 3 retry tests from testing_test.go
 	// 1 TestRetryWithFail + 3 retry tests from testing_test.go
+	// 1 TestSubtestRetryWithFail + 1 subtest + 2 subtest retries
 	// 1 TestNormalPassingAfterRetryAlwaysFail
 	// 1 TestEarlyFlakeDetection
 	// 3 tests from testify_test.go and testify_test.go/MySuite
@@ -140,6 +151,8 @@ func runFlakyTestRetriesTests(m *testing.M) {
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSkip", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestRetryWithPanic", 4)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestRetryWithFail", 4)
+	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSubtestRetryWithFail", 1)
+	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSubtestRetryWithFail/sub01", 3)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestNormalPassingAfterRetryAlwaysFail", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestEarlyFlakeDetection", 1)
 	checkSpansByResourceName(finishedSpans, "testify_test.go.TestTestifyLikeTest", 1)
@@ -152,22 +165,22 @@ func runFlakyTestRetriesTests(m *testing.M) {
 	}
 
 	// check spans by tag
-	checkSpansByTagName(finishedSpans, constants.TestIsRetry, 6)
-	trrSpan := checkSpansByTagName(finishedSpans, constants.TestRetryReason, 6)[0]
+	checkSpansByTagName(finishedSpans, constants.TestIsRetry, 8)
+	trrSpan := checkSpansByTagName(finishedSpans, constants.TestRetryReason, 8)[0]
 	if trrSpan.Tag(constants.TestRetryReason) != "atr" {
 		panic(fmt.Sprintf("expected retry reason to be %s, got %s", "atr", trrSpan.Tag(constants.TestRetryReason)))
 	}
 
 	// check the test is new tag
-	checkSpansByTagName(finishedSpans, constants.TestIsNew, 22)
+	checkSpansByTagName(finishedSpans, constants.TestIsNew, 29)
 
 	// check spans by type
 	checkSpansByType(finishedSpans,
-		28,
+		36,
 		1,
 		1,
-		4,
-		27,
+		5,
+		34,
 		0)
 
 	// check capabilities tags
@@ -211,14 +224,16 @@ func runEarlyFlakyTestDetectionTests(m *testing.M) {
 
 	// 1 session span
 	// 1 module span
-	// 4 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite and reflections_test.go)
+	// 5 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite, reflections_test.go and ginkgo_test.go)
 	// 5 tests from reflections_test.go
+	// 33 tests from ginkgo_test.go (3 new tests with 10 EFD retries)
 	// 11 TestMyTest01
 	// 11 TestMyTest02 + 22 subtests
 	// 11 Test_Foo + 33 subtests
 	// 11 TestSkip
 	// 11 TestRetryWithPanic
 	// 11 TestRetryWithFail
+	// 11 TestSubtestRetryWithFail + 11 subtests
 	// 11 TestNormalPassingAfterRetryAlwaysFail
 	// 11 TestEarlyFlakeDetection
 	// 22 normal spans from testing_test.go
@@ -253,20 +268,20 @@ func runEarlyFlakyTestDetectionTests(m *testing.M) {
 	}
 
 	// check spans by tag
-	checkSpansByTagName(finishedSpans, constants.TestIsNew, 176)
-	checkSpansByTagName(finishedSpans, constants.TestIsRetry, 160)
-	trrSpan := checkSpansByTagName(finishedSpans, constants.TestRetryReason, 160)[0]
+	checkSpansByTagName(finishedSpans, constants.TestIsNew, 231)
+	checkSpansByTagName(finishedSpans, constants.TestIsRetry, 210)
+	trrSpan := checkSpansByTagName(finishedSpans, constants.TestRetryReason, 210)[0]
 	if trrSpan.Tag(constants.TestRetryReason) != "efd" {
 		panic(fmt.Sprintf("expected retry reason to be %s, got %s", "efd", trrSpan.Tag(constants.TestRetryReason)))
 	}
 
 	// check spans by type
 	checkSpansByType(finishedSpans,
-		152,
+		208,
 		1,
 		1,
-		4,
-		181,
+		5,
+		236,
 		0)
 
 	// check capabilities tags
@@ -297,6 +312,7 @@ func runFlakyTestRetriesWithEarlyFlakyTestDetectionTests(m *testing.M) {
 					"TestRetryWithPanic",
 					"TestRetryWithFail",
 					"TestRetryAlwaysFail",
+					"TestSubtestRetryWithFail",
 					"TestNormalPassingAfterRetryAlwaysFail",
 				},
 				"testify_test.go": []string{
@@ -320,6 +336,8 @@ func runFlakyTestRetriesWithEarlyFlakyTestDetectionTests(m *testing.M) {
 	currentM = m
 	mTracer = integrations.InitializeCIVisibilityMock()
 
+	testSubtestRetryWithFailCount = 2 // this makes TestSubtestRetryWithFail/sub01 to fail twice before passing
+
 	// execute the tests, we are expecting some tests to fail and check the assertion later
 	exitCode := RunM(m)
 	if exitCode != 0 {
@@ -331,8 +349,9 @@ func runFlakyTestRetriesWithEarlyFlakyTestDetectionTests(m *testing.M) {
 
 	// 1 session span
 	// 1 module span
-	// 4 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite and reflections_test.go)
+	// 5 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite, reflections_test.go and ginkgo_test.go)
 	// 5 tests from reflections_test.go
+	// 3 tests from ginkgo_test.go + 30 EFD retries
 	// 1 TestMyTest01
 	// 1 TestMyTest02 + 2 subtests
 	// 1 Test_Foo + 3 subtests
@@ -340,6 +359,7 @@ func runFlakyTestRetriesWithEarlyFlakyTestDetectionTests(m *testing.M) {
 	// 1 TestSkip
 	// 1 TestRetryWithPanic + 3 retry tests from testing_test.go
 	// 1 TestRetryWithFail + 3 retry tests from testing_test.go
+	// 1 TestSubtestRetryWithFail + 1 subtest + 2 subtest retries
 	// 1 TestNormalPassingAfterRetryAlwaysFail
 	// 1 TestEarlyFlakeDetection + 10 EFD retries
 	// 2 normal spans from testing_test.go
@@ -362,6 +382,8 @@ func runFlakyTestRetriesWithEarlyFlakyTestDetectionTests(m *testing.M) {
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSkip", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestRetryWithPanic", 4)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestRetryWithFail", 4)
+	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSubtestRetryWithFail", 1)
+	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSubtestRetryWithFail/sub01", 3)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestNormalPassingAfterRetryAlwaysFail", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestEarlyFlakeDetection", 11)
 	checkSpansByResourceName(finishedSpans, "testify_test.go.TestTestifyLikeTest", 1)
@@ -374,16 +396,16 @@ func runFlakyTestRetriesWithEarlyFlakyTestDetectionTests(m *testing.M) {
 	}
 
 	// check spans by tag
-	checkSpansByTagName(finishedSpans, constants.TestIsNew, 11)
-	checkSpansByTagName(finishedSpans, constants.TestIsRetry, 16)
+	checkSpansByTagName(finishedSpans, constants.TestIsNew, 44)
+	checkSpansByTagName(finishedSpans, constants.TestIsRetry, 48)
 
 	// check spans by type
 	checkSpansByType(finishedSpans,
-		38,
+		76,
 		1,
 		1,
-		4,
-		37,
+		5,
+		74,
 		0)
 
 	// check capabilities tags
@@ -424,6 +446,10 @@ func runIntelligentTestRunnerTests(m *testing.M) {
 			Suite: "testing_test.go",
 			Name:  "TestNormalPassingAfterRetryAlwaysFail",
 		},
+		{
+			Suite: "testing_test.go",
+			Name:  "TestSubtestRetryWithFail/sub01",
+		},
 	},
 		false, nil)
 	defer server.Close()
@@ -443,8 +469,9 @@ func runIntelligentTestRunnerTests(m *testing.M) {
 
 	// 1 session span
 	// 1 module span
-	// 4 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite and reflections_test.go)
+	// 5 suite span (testing_test.go, testify_test.go, testify_test.go/MySuite, reflections_test.go and ginkgo_test.go)
 	// 5 tests from reflections_test.go
+	// 3 tests from ginkgo_test.go
 	// 1 TestMyTest01
 	// 1 TestMyTest02
 	// 1 Test_Foo
@@ -452,6 +479,7 @@ func runIntelligentTestRunnerTests(m *testing.M) {
 	// 1 TestRetryWithPanic
 	// 1 TestRetryWithFail
 	// 1 TestRetryAlwaysFail
+	// 1 TestSubtestRetryWithFail + 1 subtest
 	// 1 TestNormalPassingAfterRetryAlwaysFail
 	// 1 TestEarlyFlakeDetection
 	// 3 tests from testify_test.go and testify_test.go/MySuite
@@ -478,6 +506,8 @@ func runIntelligentTestRunnerTests(m *testing.M) {
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSkip", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestRetryWithPanic", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestRetryWithFail", 1)
+	checkSpansByResourceName(finishedSpans, "testing_test.go.TestSubtestRetryWithFail", 1)
+	testSubtestRetryWithFailSub01 := checkSpansByResourceName(finishedSpans, "testing_test.go.TestSubtestRetryWithFail/sub01", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestNormalPassingAfterRetryAlwaysFail", 1)
 	checkSpansByResourceName(finishedSpans, "testing_test.go.TestEarlyFlakeDetection", 1)
 	checkSpansByResourceName(finishedSpans, "testify_test.go.TestTestifyLikeTest", 1)
@@ -490,9 +520,10 @@ func runIntelligentTestRunnerTests(m *testing.M) {
 	}
 
 	// check ITR spans
-	// 5 tests skipped by ITR and 1 normal skipped test
-	checkSpansByTagValue(finishedSpans, constants.TestStatus, constants.TestStatusSkip, 6)
-	checkSpansByTagValue(finishedSpans, constants.TestSkipReason, constants.SkippedByITRReason, 5)
+	// 5 tests and 1 subtest skipped by ITR and 1 normal skipped test
+	checkSpansByTagValue(finishedSpans, constants.TestStatus, constants.TestStatusSkip, 7)
+	checkSpansByTagValue(finishedSpans, constants.TestSkipReason, constants.SkippedByITRReason, 6)
+	checkSpansByTagValue(testSubtestRetryWithFailSub01, constants.TestSkippedByITR, "true", 1)
 
 	// check unskippable tests
 	// 5 tests from unskippable suite in reflections_test.go and 2 unskippable tests from testing_test.go
@@ -501,11 +532,11 @@ func runIntelligentTestRunnerTests(m *testing.M) {
 
 	// check spans by type
 	checkSpansByType(finishedSpans,
-		17,
+		23,
 		1,
 		1,
-		4,
-		16,
+		5,
+		21,
 		0)
 
 	// check capabilities tags
@@ -638,6 +669,87 @@ func runTestManagementTests(m *testing.M) {
 	os.Exit(0)
 }
 
+func runGinkgoTests(m *testing.M) {
+	// mock the settings api to enable automatic test retries
+	server := setUpHTTPServer(true, false, false, nil, false, nil, false, nil)
+	defer server.Close()
+
+	// set a custom retry count
+	os.Setenv(constants.CIVisibilityFlakyRetryCountEnvironmentVariable, "3")
+
+	// only run the Ginkgo suite
+	flag.Set("test.skip", "")
+	flag.Set("test.run", "^TestGinkgoSuite$")
+
+	// initialize the mock tracer for doing assertions on the finished spans
+	currentM = m
+	mTracer = integrations.InitializeCIVisibilityMock()
+
+	// execute the tests, the Ginkgo suite fails because of the "always fails" spec
+	exitCode := RunM(m)
+	if exitCode != 1 {
+		panic("expected the exit code to be 1. Got exit code: " + fmt.Sprintf("%d", exitCode))
+	}
+
+	// get all finished spans
+	finishedSpans := mTracer.FinishedSpans()
+
+	// 1 session span
+	// 1 module span
+	// 3 suite span (ginkgo_test.go, Books and Sums)
+	// 1 TestGinkgoSuite, not retried
+	// 1 Books passes
+	// 1 Books fails once + 1 retry
+	// 1 Books always fails + 3 retries
+	// 1 Books is pending
+	// 2 Sums entries
+
+	// check spans by resource name
+	checkSpansByResourceName(finishedSpans, "github.com/DataDog/dd-trace-go/v2/internal/civisibility/integrations/gotesting", 1)
+	checkSpansByResourceName(finishedSpans, "ginkgo_test.go", 1)
+	checkSpansByResourceName(finishedSpans, "Books", 1)
+	checkSpansByResourceName(finishedSpans, "Sums", 1)
+	suiteTest := checkSpansByResourceName(finishedSpans, "ginkgo_test.go.TestGinkgoSuite", 1)[0]
+	passes := checkSpansByResourceName(finishedSpans, "Books.passes", 1)
+	failsOnce := checkSpansByResourceName(finishedSpans, "Books.fails once", 2)
+	alwaysFails := checkSpansByResourceName(finishedSpans, "Books.always fails", 4)
+	pending := checkSpansByResourceName(finishedSpans, "Books.is pending", 1)
+	sums := append(checkSpansByResourceName(finishedSpans, "Sums.1+1", 1), checkSpansByResourceName(finishedSpans, "Sums.2+2", 1)...)
+
+	// the test running the Ginkgo suite is reported as failed, the specs are retried instead of it
+	if suiteTest.Tag(constants.TestStatus) != constants.TestStatusFail || suiteTest.Tag(constants.TestIsRetry) != nil {
+		panic(fmt.Sprintf("expected TestGinkgoSuite to fail without being retried, got status %v", suiteTest.Tag(constants.TestStatus)))
+	}
+	checkSpansByTagValue(passes, constants.TestFramework, ginkgoFramework, 1)
+	checkSpansByTagValue(failsOnce, constants.TestStatus, constants.TestStatusFail, 1)
+	checkSpansByTagValue(failsOnce, constants.TestStatus, constants.TestStatusPass, 1)
+	checkSpansByTagValue(failsOnce, constants.TestIsRetry, "true", 1)
+	checkSpansByTagValue(alwaysFails, constants.TestStatus, constants.TestStatusFail, 4)
+	checkSpansByTagValue(alwaysFails, constants.TestHasFailedAllRetries, "true", 1)
+	checkSpansByTagValue(alwaysFails, ext.ErrorMsg, "persistent failure", 4)
+	checkSpansByTagValue(pending, constants.TestSkipReason, ginkgoPendingReason, 1)
+	checkSpansByTagValue(sums, constants.TestStatus, constants.TestStatusPass, 2)
+
+	// check spans by tag
+	checkSpansByTagName(finishedSpans, constants.TestIsRetry, 4)
+	trrSpan := checkSpansByTagName(finishedSpans, constants.TestRetryReason, 4)[0]
+	if trrSpan.Tag(constants.TestRetryReason) != "atr" {
+		panic(fmt.Sprintf("expected retry reason to be %s, got %s", "atr", trrSpan.Tag(constants.TestRetryReason)))
+	}
+
+	// check spans by type
+	checkSpansByType(finishedSpans,
+		16,
+		1,
+		1,
+		3,
+		11,
+		0)
+
+	fmt.Println("All tests passed.")
+	os.Exit(0)
+}
+
 func checkSpansByType(finishedSpans []*mocktracer.Span,
 	totalFinishedSpansCount int, sessionSpansCount int, moduleSpansCount int,
 	suiteSpansCount int, testSpansCount int, normalSpansCount int) {
@@ -174,15 +174,7 @@ func (ddm *M) executeInternalTest(testInfo *testingTInfo) func(*testing.T) {
 
 	// Check if the test is going to be skipped by ITR
 	if settings.ItrEnabled && settings.TestsSkipping {
-		// Check if the test is going to be skipped by ITR
-		skippableTests := integrations.GetSkippableTests()
-		if skippableTests != nil {
-			if suitesMap, ok := skippableTests[testInfo.suiteName]; ok {
-				if _, ok := suitesMap[testInfo.testName]; ok {
-					testSkippedByITR = true
-				}
-			}
-		}
+		testSkippedByITR = isSkippableTest(&testInfo.commonInfo)
 	}
 
 	// Check if the test is known
@@ -617,6 +609,16 @@ func isKnownTest(testInfo *commonInfo) (isKnown bool, hasKnownData bool) {
 	return false, false
 }
 
+// isSkippableTest checks if a test is in the skippable tests of the Intelligent Test Runner
+func isSkippableTest(testInfo *commonInfo) bool {
+	skippableTests := integrations.GetSkippableTests()
+	if suitesMap, ok := skippableTests[testInfo.suiteName]; ok {
+		_, ok := suitesMap[testInfo.testName]
+		return ok
+	}
+	return false
+}
+
 // getTestManagementData retrieves the test management data for a test
 func getTestManagementData(testInfo *commonInfo) (data *net.TestManagementTestsResponseDataTestPropertiesAttributes, hasTestManagementData bool) {
 	testManagementData := integrations.GetTestManagementTestsData()
@@ -117,6 +117,19 @@ func TestRetryWithFail(t *testing.T) {
 	}
 }
 
+// number of failed executions of the subtest before passing (set by the flaky test retries scenarios)
+var testSubtestRetryWithFailCount = 0
+
+func TestSubtestRetryWithFail(gt *testing.T) {
+	t := (*T)(gt)
+	t.Run("sub01", func(t *testing.T) {
+		if testSubtestRetryWithFailCount > 0 {
+			testSubtestRetryWithFailCount--
+			t.Fatal("Failed due the wrong execution number")
+		}
+	})
+}
+
 //dd:test.unskippable
 func TestNormalPassingAfterRetryAlwaysFail(_ *testing.T) {}
 
@@ -124,6 +124,12 @@ func NewClientWithServiceNameAndSubdomain(serviceName, subdomain string) Client
 		}
 	}
 
+	// in offline mode, the client reads the local files instead of sending requests
+	if offlineDir := os.Getenv(constants.CIVisibilityOfflineDirEnvironmentVariable); offlineDir != "" {
+		log.Debug("ciVisibilityHttpClient: new offline client created [dir: %v, serviceName: %v, subdomain: %v]", offlineDir, serviceName, subdomain)
+		return newOfflineClient(offlineDir, getTestConfigurations(ciTags, customConfiguration))
+	}
+
 	// create default http headers and get base url
 	defaultHeaders := map[string]string{}
 	var baseURL string
@@ -241,26 +247,31 @@ func NewClientWithServiceNameAndSubdomain(serviceName, subdomain string) Client
 	}
 
 	return &client{
-		id:               id,
-		agentless:        agentlessEnabled,
-		baseURL:          baseURL,
-		environment:      environment,
-		serviceName:      serviceName,
-		workingDirectory: ciTags[constants.CIWorkspacePath],
-		repositoryURL:    ciTags[constants.GitRepositoryURL],
-		commitSha:        ciTags[constants.GitCommitSHA],
-		commitMessage:    ciTags[constants.GitCommitMessage],
-		branchName:       bName,
-		testConfigurations: testConfigurations{
-			OsPlatform:     ciTags[constants.OSPlatform],
-			OsVersion:      ciTags[constants.OSVersion],
-			OsArchitecture: ciTags[constants.OSArchitecture],
-			RuntimeName:    ciTags[constants.RuntimeName],
-			RuntimeVersion: ciTags[constants.RuntimeVersion],
-			Custom:         customConfiguration,
-		},
-		headers: defaultHeaders,
-		handler: requestHandler,
+		id:                 id,
+		agentless:          agentlessEnabled,
+		baseURL:            baseURL,
+		environment:        environment,
+		serviceName:        serviceName,
+		workingDirectory:   ciTags[constants.CIWorkspacePath],
+		repositoryURL:      ciTags[constants.GitRepositoryURL],
+		commitSha:          ciTags[constants.GitCommitSHA],
+		commitMessage:      ciTags[constants.GitCommitMessage],
+		branchName:         bName,
+		testConfigurations: getTestConfigurations(ciTags, customConfiguration),
+		headers:            defaultHeaders,
+		handler:            requestHandler,
+	}
+}
+
+// getTestConfigurations returns the test configurations of the given CI tags.
+func getTestConfigurations(ciTags map[string]string, customConfiguration map[string]string) testConfigurations {
+	return testConfigurations{
+		OsPlatform:     ciTags[constants.OSPlatform],
+		OsVersion:      ciTags[constants.OSVersion],
+		OsArchitecture: ciTags[constants.OSArchitecture],
+		RuntimeName:    ciTags[constants.RuntimeName],
+		RuntimeVersion: ciTags[constants.RuntimeVersion],
+		Custom:         customConfiguration,
 	}
 }
 
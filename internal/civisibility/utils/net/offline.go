@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package net
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/globalconfig"
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+const (
+	// OfflineSettingsFile is the file of the offline directory holding the settings, with the schema of the settings API response.
+	OfflineSettingsFile = "settings.json"
+	// OfflineKnownTestsFile is the file of the offline directory holding the known tests, with the schema of the known tests API response.
+	OfflineKnownTestsFile = "known_tests.json"
+	// OfflineSkippableTestsFile is the file of the offline directory holding the skippable tests, with the schema of the skippable tests API response.
+	OfflineSkippableTestsFile = "skippable_tests.json"
+	// OfflineTestManagementTestsFile is the file of the offline directory holding the test management tests (quarantined,
+	// disabled and attempt to fix tests), with the schema of the test management tests API response.
+	OfflineTestManagementTestsFile = "test_management_tests.json"
+)
+
+var _ Client = &offlineClient{}
+
+// offlineClient is a client that reads the backend responses from the local files of a directory and writes the
+// payloads to it, for the CI environments without network access.
+type offlineClient struct {
+	dir                string
+	testConfigurations testConfigurations
+	payloadCount       atomic.Uint64
+}
+
+// newOfflineClient creates a new offline client for the given directory.
+func newOfflineClient(dir string, testConfigurations testConfigurations) *offlineClient {
+	return &offlineClient{
+		dir:                dir,
+		testConfigurations: testConfigurations,
+	}
+}
+
+// readFile unmarshals the given file of the offline directory into v, and reports whether it exists.
+func (c *offlineClient) readFile(name string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		log.Debug("civisibility.offline: %s not found, the related features are disabled", name)
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading offline file: %s", err.Error())
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("unmarshalling offline file %s: %s", name, err.Error())
+	}
+	return true, nil
+}
+
+// GetSettings reads the settings from the offline directory, and disables every feature when there are none.
+func (c *offlineClient) GetSettings() (*SettingsResponseData, error) {
+	var responseObject settingsResponse
+	if _, err := c.readFile(OfflineSettingsFile, &responseObject); err != nil {
+		return nil, err
+	}
+	// the git metadata can't be uploaded anyway
+	responseObject.Data.Attributes.RequireGit = false
+	return &responseObject.Data.Attributes, nil
+}
+
+// GetKnownTests reads the known tests from the offline directory.
+func (c *offlineClient) GetKnownTests() (*KnownTestsResponseData, error) {
+	var responseObject knownTestsResponse
+	if _, err := c.readFile(OfflineKnownTestsFile, &responseObject); err != nil {
+		return nil, err
+	}
+	return &responseObject.Data.Attributes, nil
+}
+
+// GetSkippableTests reads the skippable tests from the offline directory.
+func (c *offlineClient) GetSkippableTests() (correlationID string, skippables map[string]map[string][]SkippableResponseDataAttributes, err error) {
+	var responseObject skippableResponse
+	if _, err = c.readFile(OfflineSkippableTestsFile, &responseObject); err != nil {
+		return "", nil, err
+	}
+	return responseObject.Meta.CorrelationID, groupSkippableTests(responseObject.Data, c.testConfigurations), nil
+}
+
+// GetTestManagementTests reads the test management tests from the offline directory.
+func (c *offlineClient) GetTestManagementTests() (*TestManagementTestsResponseDataModules, error) {
+	var responseObject testManagementTestsResponse
+	if _, err := c.readFile(OfflineTestManagementTestsFile, &responseObject); err != nil {
+		return nil, err
+	}
+	return &responseObject.Data.Attributes, nil
+}
+
+// GetCommits reports every local commit as already known, so that no pack file is created.
+func (c *offlineClient) GetCommits(localCommits []string) ([]string, error) {
+	return localCommits, nil
+}
+
+// SendPackFiles does nothing, as the pack files can't be uploaded offline.
+func (c *offlineClient) SendPackFiles(_ string, _ []string) (bytes int64, err error) {
+	return 0, nil
+}
+
+// SendCoveragePayload writes a code coverage payload to the offline directory.
+func (c *offlineClient) SendCoveragePayload(ciTestCovPayload io.Reader) error {
+	return c.SendCoveragePayloadWithFormat(ciTestCovPayload, FormatMessagePack)
+}
+
+// SendCoveragePayloadWithFormat writes a code coverage payload to the offline directory, to be uploaded later to the
+// coverage intake.
+func (c *offlineClient) SendCoveragePayloadWithFormat(ciTestCovPayload io.Reader, format string) error {
+	if ciTestCovPayload == nil {
+		return errors.New("coverage payload is nil")
+	}
+	name := fmt.Sprintf("citestcov-%s-%d.%s", globalconfig.RuntimeID(), c.payloadCount.Add(1), format)
+	f, err := os.OpenFile(filepath.Join(c.dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("creating coverage payload file: %s", err.Error())
+	}
+	if _, err := io.Copy(f, ciTestCovPayload); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing coverage payload file: %s", err.Error())
+	}
+	return f.Close()
+}
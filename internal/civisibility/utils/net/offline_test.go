@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package net
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/civisibility/constants"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineClient(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(constants.CIVisibilityOfflineDirEnvironmentVariable, dir)
+
+	writeFile := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	writeFile(OfflineSettingsFile, `{"data":{"id":"1","type":"ci_app_tracers_test_service_settings","attributes":{
+		"code_coverage":true,"known_tests_enabled":true,"require_git":true,"tests_skipping":true,
+		"test_management":{"enabled":true,"attempt_to_fix_retries":3}}}}`)
+	writeFile(OfflineKnownTestsFile, `{"data":{"attributes":{"tests":{"module":{"suite":["TestA","TestB"]}}}}}`)
+	writeFile(OfflineSkippableTestsFile, `{"meta":{"correlation_id":"abc"},"data":[
+		{"type":"test","attributes":{"suite":"suite","name":"TestA"}},
+		{"type":"test","attributes":{"suite":"suite","name":"TestB","configurations":{"os.platform":"other"}}}]}`)
+	writeFile(OfflineTestManagementTestsFile, `{"data":{"attributes":{"modules":{"module":{"suites":{"suite":{"tests":{
+		"TestA":{"properties":{"quarantined":true}}}}}}}}}}`)
+
+	c := NewClient()
+	require.IsType(t, &offlineClient{}, c)
+	c.(*offlineClient).testConfigurations.OsPlatform = "linux"
+
+	settings, err := c.GetSettings()
+	require.NoError(t, err)
+	assert.True(t, settings.CodeCoverage)
+	assert.True(t, settings.KnownTestsEnabled)
+	assert.True(t, settings.TestsSkipping)
+	assert.False(t, settings.RequireGit)
+	assert.True(t, settings.TestManagement.Enabled)
+	assert.Equal(t, 3, settings.TestManagement.AttemptToFixRetries)
+
+	knownTests, err := c.GetKnownTests()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TestA", "TestB"}, knownTests.Tests["module"]["suite"])
+
+	correlationID, skippables, err := c.GetSkippableTests()
+	require.NoError(t, err)
+	assert.Equal(t, "abc", correlationID)
+	assert.Len(t, skippables["suite"], 1)
+	assert.Contains(t, skippables["suite"], "TestA")
+
+	testManagementTests, err := c.GetTestManagementTests()
+	require.NoError(t, err)
+	assert.True(t, testManagementTests.Modules["module"].Suites["suite"].Tests["TestA"].Properties.Quarantined)
+
+	commits, err := c.GetCommits([]string{"sha1", "sha2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sha1", "sha2"}, commits)
+
+	require.NoError(t, c.SendCoveragePayload(bytes.NewBufferString("coverage")))
+	files, err := filepath.Glob(filepath.Join(dir, "citestcov-*.msgpack"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, "coverage", string(content))
+}
+
+func TestOfflineClientMissingFiles(t *testing.T) {
+	t.Setenv(constants.CIVisibilityOfflineDirEnvironmentVariable, t.TempDir())
+	c := NewClient()
+
+	settings, err := c.GetSettings()
+	require.NoError(t, err)
+	assert.Equal(t, SettingsResponseData{}, *settings)
+
+	knownTests, err := c.GetKnownTests()
+	require.NoError(t, err)
+	assert.Empty(t, knownTests.Tests)
+
+	_, skippables, err := c.GetSkippableTests()
+	require.NoError(t, err)
+	assert.Empty(t, skippables)
+}
+
+func TestOfflineClientInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(constants.CIVisibilityOfflineDirEnvironmentVariable, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, OfflineSettingsFile), []byte("{"), 0o644))
+
+	_, err := NewClient().GetSettings()
+	assert.ErrorContains(t, err, OfflineSettingsFile)
+}
@@ -112,32 +112,37 @@ func (c *client) GetSkippableTests() (correlationID string, skippables map[strin
 	}
 
 	telemetry.ITRSkippableTestsResponseTests(float64(len(responseObject.Data)))
+	return responseObject.Meta.CorrelationID, groupSkippableTests(responseObject.Data, c.testConfigurations), nil
+}
+
+// groupSkippableTests groups the skippable tests matching the given test configurations by suite and name.
+func groupSkippableTests(skippables []skippableResponseData, configurations testConfigurations) map[string]map[string][]SkippableResponseDataAttributes {
 	skippableTestsMap := map[string]map[string][]SkippableResponseDataAttributes{}
-	for _, data := range responseObject.Data {
+	for _, data := range skippables {
 
 		// Filter out the tests that do not match the test configurations
-		if data.Attributes.Configurations.OsPlatform != "" && c.testConfigurations.OsPlatform != "" &&
-			data.Attributes.Configurations.OsPlatform != c.testConfigurations.OsPlatform {
+		if data.Attributes.Configurations.OsPlatform != "" && configurations.OsPlatform != "" &&
+			data.Attributes.Configurations.OsPlatform != configurations.OsPlatform {
 			continue
 		}
-		if data.Attributes.Configurations.OsArchitecture != "" && c.testConfigurations.OsArchitecture != "" &&
-			data.Attributes.Configurations.OsArchitecture != c.testConfigurations.OsArchitecture {
+		if data.Attributes.Configurations.OsArchitecture != "" && configurations.OsArchitecture != "" &&
+			data.Attributes.Configurations.OsArchitecture != configurations.OsArchitecture {
 			continue
 		}
-		if data.Attributes.Configurations.OsVersion != "" && c.testConfigurations.OsVersion != "" &&
-			data.Attributes.Configurations.OsVersion != c.testConfigurations.OsVersion {
+		if data.Attributes.Configurations.OsVersion != "" && configurations.OsVersion != "" &&
+			data.Attributes.Configurations.OsVersion != configurations.OsVersion {
 			continue
 		}
-		if data.Attributes.Configurations.RuntimeName != "" && c.testConfigurations.RuntimeName != "" &&
-			data.Attributes.Configurations.RuntimeName != c.testConfigurations.RuntimeName {
+		if data.Attributes.Configurations.RuntimeName != "" && configurations.RuntimeName != "" &&
+			data.Attributes.Configurations.RuntimeName != configurations.RuntimeName {
 			continue
 		}
-		if data.Attributes.Configurations.RuntimeArchitecture != "" && c.testConfigurations.RuntimeArchitecture != "" &&
-			data.Attributes.Configurations.RuntimeArchitecture != c.testConfigurations.RuntimeArchitecture {
+		if data.Attributes.Configurations.RuntimeArchitecture != "" && configurations.RuntimeArchitecture != "" &&
+			data.Attributes.Configurations.RuntimeArchitecture != configurations.RuntimeArchitecture {
 			continue
 		}
-		if data.Attributes.Configurations.RuntimeVersion != "" && c.testConfigurations.RuntimeVersion != "" &&
-			data.Attributes.Configurations.RuntimeVersion != c.testConfigurations.RuntimeVersion {
+		if data.Attributes.Configurations.RuntimeVersion != "" && configurations.RuntimeVersion != "" &&
+			data.Attributes.Configurations.RuntimeVersion != configurations.RuntimeVersion {
 			continue
 		}
 
@@ -155,5 +160,5 @@ func (c *client) GetSkippableTests() (correlationID string, skippables map[strin
 		}
 	}
 
-	return responseObject.Meta.CorrelationID, skippableTestsMap, nil
+	return skippableTestsMap
 }
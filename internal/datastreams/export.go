@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+)
+
+// EnvExportOutput is the local sink where the flushed stats payloads are exported as JSON lines, in addition to
+// being sent to the agent. It is either "stdout", "stderr", "log" to write them with the tracer logger, or the path
+// of a file.
+const EnvExportOutput = "DD_DATA_STREAMS_EXPORT_OUTPUT"
+
+// exporter writes the flushed stats payloads as JSON lines to its writer, so that the pathways can be verified
+// locally without an agent.
+type exporter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// newExporter returns the exporter of the given output, as documented by [EnvExportOutput], or nil when the export
+// is disabled.
+func newExporter(output string) (*exporter, error) {
+	switch output {
+	case "":
+		return nil, nil
+	case "stdout":
+		return &exporter{w: os.Stdout}, nil
+	case "stderr":
+		return &exporter{w: os.Stderr}, nil
+	case "log":
+		return &exporter{w: logWriter{}}, nil
+	default:
+		f, err := os.OpenFile(output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("could not open the export file: %w", err)
+		}
+		return &exporter{w: f, closer: f}, nil
+	}
+}
+
+// export writes every payload as a single JSON line.
+func (e *exporter) export(payloads map[string]StatsPayload) {
+	if len(payloads) == 0 {
+		return
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, payload := range payloads {
+		if err := enc.Encode(payload); err != nil {
+			log.Error("datastreams: could not encode the exported stats payload: %v", err.Error())
+			return
+		}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.w.Write(buf.Bytes()); err != nil {
+		log.Error("datastreams: could not export the stats payloads: %v", err.Error())
+	}
+}
+
+func (e *exporter) close() {
+	if e.closer == nil {
+		return
+	}
+	if err := e.closer.Close(); err != nil {
+		log.Error("datastreams: could not close the export file: %v", err.Error())
+	}
+}
+
+// logWriter writes every line it is given with the tracer logger.
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	for _, line := range bytes.Split(bytes.TrimRight(p, "\n"), []byte("\n")) {
+		log.Info("datastreams: exported stats payload: %s", line)
+	}
+	return len(p), nil
+}
+
+// SetExportWriter exports the flushed stats payloads to w as JSON lines, in addition to sending them to the agent.
+// It takes precedence over [EnvExportOutput] and must be called before [Processor.Start].
+func (p *Processor) SetExportWriter(w io.Writer) {
+	if p.exporter != nil {
+		p.exporter.close()
+	}
+	p.exporter = &exporter{w: w}
+}
@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	p := NewProcessor(nil, "env", "service", "v1", &url.URL{Scheme: "http", Host: "agent-address"}, nil)
+	var buf bytes.Buffer
+	p.SetExportWriter(&buf)
+	tp1 := time.Now().Truncate(bucketDuration)
+	p.add(statsPoint{
+		edgeTags:       []string{"type:kafka", "topic:topic1"},
+		hash:           2,
+		parentHash:     1,
+		timestamp:      tp1.UnixNano(),
+		pathwayLatency: time.Second.Nanoseconds(),
+		edgeLatency:    time.Second.Nanoseconds(),
+		serviceName:    "service",
+	})
+	p.exporter.export(p.flush(tp1.Add(bucketDuration)))
+
+	var payload StatsPayload
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &payload))
+	assert.Equal(t, "service", payload.Service)
+	assert.Equal(t, "env", payload.Env)
+	// the point is aggregated in both the current and the origin buckets
+	require.Len(t, payload.Stats, 2)
+	require.Len(t, payload.Stats[0].Stats, 1)
+	point := payload.Stats[0].Stats[0]
+	assert.Equal(t, []string{"type:kafka", "topic:topic1"}, point.EdgeTags)
+	assert.Equal(t, uint64(2), point.Hash)
+	assert.Equal(t, uint64(1), point.ParentHash)
+}
+
+func TestExportOutput(t *testing.T) {
+	e, err := newExporter("")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	path := filepath.Join(t.TempDir(), "dsm.json")
+	t.Setenv(EnvExportOutput, path)
+	p := NewProcessor(nil, "env", "service", "v1", &url.URL{Scheme: "http", Host: "agent-address"}, nil)
+	require.NotNil(t, p.exporter)
+	p.exporter.export(map[string]StatsPayload{"service": {Service: "service"}})
+	p.exporter.close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Env":"","Service":"service","Stats":null,"TracerVersion":"","Lang":"","Version":""}`, string(data))
+}
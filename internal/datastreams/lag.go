@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"fmt"
+	"time"
+)
+
+// consumerLagExpiry is the duration after which the offsets of a partition which are not updated anymore are
+// dropped, e.g. after the partition was revoked from the consumer by a rebalance. The offsets of a stalled consumer are
+// not updated either, as the high watermark is only read when consuming: the expiry is long enough for its lag to keep
+// being reported while it is investigated.
+const consumerLagExpiry = time.Hour
+
+// lagOffset is an offset tracked for the consumer lag, with the time of its last update in nanoseconds.
+type lagOffset struct {
+	offset  int64
+	updated int64
+}
+
+// consumerLag keeps the latest offsets of the Kafka partitions across the stats buckets, so that the consumer lag
+// can be computed without the Data Streams backend. It is only accessed by the run loop.
+type consumerLag struct {
+	// endOffsets are the offsets of the end of the partitions, either the high watermark or the offset following
+	// the last produced message, whichever is the highest.
+	endOffsets    map[partitionKey]lagOffset
+	commitOffsets map[partitionConsumerKey]lagOffset
+}
+
+func newConsumerLag() *consumerLag {
+	return &consumerLag{
+		endOffsets:    make(map[partitionKey]lagOffset),
+		commitOffsets: make(map[partitionConsumerKey]lagOffset),
+	}
+}
+
+func (l *consumerLag) track(o kafkaOffset) {
+	switch o.offsetType {
+	case produceOffset, highWatermarkOffset:
+		end := o.offset
+		if o.offsetType == produceOffset {
+			// the high watermark is the offset of the next message to be produced
+			end++
+		}
+		key := partitionKey{partition: o.partition, topic: o.topic}
+		cur := l.endOffsets[key]
+		l.endOffsets[key] = lagOffset{offset: max(end, cur.offset), updated: max(o.timestamp, cur.updated)}
+	case commitOffset:
+		key := partitionConsumerKey{partition: o.partition, topic: o.topic, group: o.group}
+		l.commitOffsets[key] = lagOffset{offset: o.offset, updated: max(o.timestamp, l.commitOffsets[key].updated)}
+	}
+}
+
+// expire drops the offsets which were not updated since consumerLagExpiry, so that no lag is reported anymore for
+// the partitions the process stopped producing to or consuming from.
+func (l *consumerLag) expire(now time.Time) {
+	limit := now.Add(-consumerLagExpiry).UnixNano()
+	for key, o := range l.endOffsets {
+		if o.updated < limit {
+			delete(l.endOffsets, key)
+		}
+	}
+	for key, o := range l.commitOffsets {
+		if o.updated < limit {
+			delete(l.commitOffsets, key)
+		}
+	}
+}
+
+// lags returns the lag of every consumer group partition whose end offset is known, which is the number of
+// messages between the commit offset of the group and the end of the partition.
+func (l *consumerLag) lags() map[partitionConsumerKey]int64 {
+	lags := make(map[partitionConsumerKey]int64, len(l.commitOffsets))
+	for key, commit := range l.commitOffsets {
+		end, ok := l.endOffsets[partitionKey{partition: key.partition, topic: key.topic}]
+		if !ok {
+			continue
+		}
+		lags[key] = max(end.offset-commit.offset, 0)
+	}
+	return lags
+}
+
+// reportConsumerLag reports the lag of every consumer group partition as a gauge, for the services that do not use
+// the Data Streams backend.
+func (p *Processor) reportConsumerLag() {
+	p.consumerLag.expire(p.time())
+	for key, lag := range p.consumerLag.lags() {
+		tags := []string{
+			"consumer_group:" + key.group,
+			"topic:" + key.topic,
+			fmt.Sprintf("partition:%d", key.partition),
+		}
+		p.statsd.Gauge("datadog.datastreams.kafka.consumer_lag", float64(lag), tags, 1)
+	}
+}
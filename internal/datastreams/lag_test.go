@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/statsdtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerLag(t *testing.T) {
+	l := newConsumerLag()
+	l.track(kafkaOffset{offsetType: commitOffset, group: "group1", topic: "topic1", partition: 0, offset: 5})
+	l.track(kafkaOffset{offsetType: commitOffset, group: "group2", topic: "topic1", partition: 0, offset: 12})
+	// the end offset of the partition is unknown
+	l.track(kafkaOffset{offsetType: commitOffset, group: "group1", topic: "topic2", partition: 0, offset: 5})
+	l.track(kafkaOffset{offsetType: produceOffset, topic: "topic1", partition: 0, offset: 9})
+	assert.Equal(t, map[partitionConsumerKey]int64{
+		{partition: 0, topic: "topic1", group: "group1"}: 5,
+		{partition: 0, topic: "topic1", group: "group2"}: 0,
+	}, l.lags())
+
+	// the highest end offset wins
+	l.track(kafkaOffset{offsetType: highWatermarkOffset, topic: "topic1", partition: 0, offset: 20})
+	l.track(kafkaOffset{offsetType: produceOffset, topic: "topic1", partition: 0, offset: 15})
+	assert.Equal(t, map[partitionConsumerKey]int64{
+		{partition: 0, topic: "topic1", group: "group1"}: 15,
+		{partition: 0, topic: "topic1", group: "group2"}: 8,
+	}, l.lags())
+}
+
+func TestConsumerLagExpiry(t *testing.T) {
+	now := time.Now()
+	l := newConsumerLag()
+	l.track(kafkaOffset{offsetType: highWatermarkOffset, topic: "topic1", partition: 0, offset: 10, timestamp: now.UnixNano()})
+	l.track(kafkaOffset{offsetType: commitOffset, group: "group1", topic: "topic1", partition: 0, offset: 4, timestamp: now.UnixNano()})
+	// the partition 1 is revoked from the consumer after this commit
+	l.track(kafkaOffset{offsetType: highWatermarkOffset, topic: "topic1", partition: 1, offset: 10, timestamp: now.UnixNano()})
+	l.track(kafkaOffset{offsetType: commitOffset, group: "group1", topic: "topic1", partition: 1, offset: 6, timestamp: now.UnixNano()})
+
+	later := now.Add(consumerLagExpiry)
+	l.track(kafkaOffset{offsetType: highWatermarkOffset, topic: "topic1", partition: 0, offset: 12, timestamp: later.UnixNano()})
+	l.track(kafkaOffset{offsetType: commitOffset, group: "group1", topic: "topic1", partition: 0, offset: 11, timestamp: later.UnixNano()})
+
+	l.expire(later)
+	assert.Equal(t, map[partitionConsumerKey]int64{
+		{partition: 0, topic: "topic1", group: "group1"}: 1,
+		{partition: 1, topic: "topic1", group: "group1"}: 4,
+	}, l.lags())
+
+	l.expire(later.Add(time.Nanosecond))
+	assert.Equal(t, map[partitionConsumerKey]int64{
+		{partition: 0, topic: "topic1", group: "group1"}: 1,
+	}, l.lags())
+	assert.Len(t, l.endOffsets, 1)
+	assert.Len(t, l.commitOffsets, 1)
+}
+
+func TestConsumerLagStalled(t *testing.T) {
+	now := time.Now()
+	l := newConsumerLag()
+	l.track(kafkaOffset{offsetType: highWatermarkOffset, topic: "topic1", partition: 0, offset: 10, timestamp: now.UnixNano()})
+	l.track(kafkaOffset{offsetType: commitOffset, group: "group1", topic: "topic1", partition: 0, offset: 4, timestamp: now.UnixNano()})
+
+	// the consumer stops receiving messages, so that none of the offsets of the partition are updated anymore
+	for elapsed := bucketDuration; elapsed < consumerLagExpiry; elapsed += 30 * bucketDuration {
+		l.expire(now.Add(elapsed))
+		assert.Equal(t, map[partitionConsumerKey]int64{
+			{partition: 0, topic: "topic1", group: "group1"}: 6,
+		}, l.lags(), "elapsed=%s", elapsed)
+	}
+}
+
+func TestReportConsumerLag(t *testing.T) {
+	var tg statsdtest.TestStatsdClient
+	p := NewProcessor(&tg, "env", "service", "v1", &url.URL{Scheme: "http", Host: "agent-address"}, nil)
+	p.addKafkaOffset(kafkaOffset{offsetType: highWatermarkOffset, topic: "topic1", partition: 1, offset: 10, timestamp: p.time().UnixNano()})
+	p.addKafkaOffset(kafkaOffset{offsetType: commitOffset, group: "group1", topic: "topic1", partition: 1, offset: 7, timestamp: p.time().UnixNano()})
+	p.reportConsumerLag()
+
+	calls := tg.GetCallsByName("datadog.datastreams.kafka.consumer_lag")
+	require.Len(t, calls, 1)
+	assert.Equal(t, 3.0, calls[0].FloatVal())
+	assert.ElementsMatch(t, []string{"consumer_group:group1", "topic:topic1", "partition:1"}, calls[0].Tags())
+
+	t.Run("expired", func(t *testing.T) {
+		tg.Reset()
+		p.timeSource = func() time.Time { return time.Now().Add(consumerLagExpiry + time.Second) }
+		p.reportConsumerLag()
+		assert.Empty(t, tg.GetCallsByName("datadog.datastreams.kafka.consumer_lag"))
+	})
+}
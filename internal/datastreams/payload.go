@@ -44,6 +44,36 @@ type Backlog struct {
 	Value int64
 }
 
+// SchemaUsage represents the sampled usage of a schema to serialize or deserialize the payloads of a topic.
+type SchemaUsage struct {
+	// Topic is the topic of the payloads
+	Topic string
+	// Operation is either serialization or deserialization
+	Operation string
+	// ID, Name, Type and Definition identify the schema
+	ID         string
+	Name       string
+	Type       string
+	Definition string
+	// Weight is the number of schema usages accounted for by the samples
+	Weight int64
+	// Changed is true when the schema differs from the one previously sampled for the topic and operation
+	Changed bool
+}
+
+// Transaction represents a checkpoint of an individual business transaction, such as an order, which allows
+// following it across services that do not share a trace context.
+type Transaction struct {
+	// ID identifies the transaction
+	ID string
+	// Checkpoint is the name of the checkpoint the transaction went through
+	Checkpoint string
+	// Timestamp is the time of the checkpoint in unix nanoseconds
+	Timestamp int64
+	// PathwayHash is the hash of the pathway of the context of the checkpoint, if any
+	PathwayHash uint64
+}
+
 // StatsBucket specifies a set of stats computed over a duration.
 type StatsBucket struct {
 	// Start specifies the beginning of this bucket in unix nanoseconds.
@@ -54,6 +84,10 @@ type StatsBucket struct {
 	Stats []StatsPoint
 	// Backlogs store information used to compute queue backlog
 	Backlogs []Backlog
+	// Schemas store the sampled usage of the schemas of the payloads of each topic
+	Schemas []SchemaUsage
+	// Transactions store the checkpoints of the business transactions tracked during the bucket
+	Transactions []Transaction
 }
 
 // TimestampType can be either current or origin.
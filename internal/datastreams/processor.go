@@ -11,6 +11,9 @@ import (
 	"math"
 	"net/http"
 	"net/url"
+	"os"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -61,6 +64,9 @@ type bucket struct {
 	latestCommitOffsets        map[partitionConsumerKey]int64
 	latestProduceOffsets       map[partitionKey]int64
 	latestHighWatermarkOffsets map[partitionKey]int64
+	latestBacklogs             map[string]Backlog
+	schemas                    map[schemaBucketKey]SchemaUsage
+	transactions               []Transaction
 	start                      uint64
 	duration                   uint64
 }
@@ -71,6 +77,8 @@ func newBucket(start, duration uint64) bucket {
 		latestCommitOffsets:        make(map[partitionConsumerKey]int64),
 		latestProduceOffsets:       make(map[partitionKey]int64),
 		latestHighWatermarkOffsets: make(map[partitionKey]int64),
+		latestBacklogs:             make(map[string]Backlog),
+		schemas:                    make(map[schemaBucketKey]SchemaUsage),
 		start:                      start,
 		duration:                   duration,
 	}
@@ -105,10 +113,11 @@ func (b bucket) export(timestampType TimestampType) StatsBucket {
 		})
 	}
 	exported := StatsBucket{
-		Start:    b.start,
-		Duration: b.duration,
-		Stats:    stats,
-		Backlogs: make([]Backlog, 0, len(b.latestCommitOffsets)+len(b.latestProduceOffsets)+len(b.latestHighWatermarkOffsets)),
+		Start:        b.start,
+		Duration:     b.duration,
+		Stats:        stats,
+		Transactions: b.transactions,
+		Backlogs:     make([]Backlog, 0, len(b.latestCommitOffsets)+len(b.latestProduceOffsets)+len(b.latestHighWatermarkOffsets)+len(b.latestBacklogs)),
 	}
 	for key, offset := range b.latestProduceOffsets {
 		exported.Backlogs = append(exported.Backlogs, Backlog{Tags: []string{fmt.Sprintf("partition:%d", key.partition), fmt.Sprintf("topic:%s", key.topic), "type:kafka_produce"}, Value: offset})
@@ -119,6 +128,15 @@ func (b bucket) export(timestampType TimestampType) StatsBucket {
 	for key, offset := range b.latestHighWatermarkOffsets {
 		exported.Backlogs = append(exported.Backlogs, Backlog{Tags: []string{fmt.Sprintf("partition:%d", key.partition), fmt.Sprintf("topic:%s", key.topic), "type:kafka_high_watermark"}, Value: offset})
 	}
+	for _, backlog := range b.latestBacklogs {
+		exported.Backlogs = append(exported.Backlogs, backlog)
+	}
+	if len(b.schemas) > 0 {
+		exported.Schemas = make([]SchemaUsage, 0, len(b.schemas))
+		for _, usage := range b.schemas {
+			exported.Schemas = append(exported.Schemas, usage)
+		}
+	}
 	return exported
 }
 
@@ -127,11 +145,17 @@ type pointType int
 const (
 	pointTypeStats pointType = iota
 	pointTypeKafkaOffset
+	pointTypeBacklog
+	pointTypeSchema
+	pointTypeTransaction
 )
 
 type processorInput struct {
 	point       statsPoint
 	kafkaOffset kafkaOffset
+	backlog     backlog
+	schema      schemaPoint
+	transaction Transaction
 	typ         pointType
 	queuePos    int64
 }
@@ -142,6 +166,8 @@ type processorStats struct {
 	flushedBuckets  int64
 	flushErrors     int64
 	dropped         int64
+	// droppedTransactions counts the transaction checkpoints dropped because of a full buffer
+	droppedTransactions int64
 }
 
 type partitionKey struct {
@@ -172,6 +198,14 @@ type kafkaOffset struct {
 	timestamp  int64
 }
 
+// backlog is the latest value of a queue backlog other than the Kafka offsets, such as the number of messages
+// waiting in a queue or how far behind a stream consumer is.
+type backlog struct {
+	tags      []string
+	value     int64
+	timestamp int64
+}
+
 type bucketKey struct {
 	serviceName string
 	btime       int64
@@ -180,6 +214,8 @@ type bucketKey struct {
 type Processor struct {
 	in                   *fastQueue
 	hashCache            *hashCache
+	schemaSampler        *schemaSampler
+	consumerLag          *consumerLag
 	inKafka              chan kafkaOffset
 	tsTypeCurrentBuckets map[bucketKey]bucket
 	tsTypeOriginBuckets  map[bucketKey]bucket
@@ -188,12 +224,15 @@ type Processor struct {
 	stop                 chan struct{} // closing this channel triggers shutdown
 	flushRequest         chan chan<- struct{}
 	stats                processorStats
-	transport            *httpTransport
-	statsd               internal.StatsdClient
-	env                  string
-	primaryTag           string
-	service              string
-	version              string
+	// pendingTransactions is the number of buffered transaction checkpoints, only accessed by the run loop
+	pendingTransactions int
+	transport           *httpTransport
+	exporter            *exporter // exports the flushed stats payloads locally, if set
+	statsd              internal.StatsdClient
+	env                 string
+	primaryTag          string
+	service             string
+	version             string
 	// used for tests
 	timeSource func() time.Time
 }
@@ -213,6 +252,8 @@ func NewProcessor(statsd internal.StatsdClient, env, service, version string, ag
 		tsTypeCurrentBuckets: make(map[bucketKey]bucket),
 		tsTypeOriginBuckets:  make(map[bucketKey]bucket),
 		hashCache:            newHashCache(),
+		schemaSampler:        newSchemaSampler(),
+		consumerLag:          newConsumerLag(),
 		in:                   newFastQueue(),
 		stopped:              1,
 		statsd:               statsd,
@@ -222,6 +263,11 @@ func NewProcessor(statsd internal.StatsdClient, env, service, version string, ag
 		transport:            newHTTPTransport(agentURL, httpClient),
 		timeSource:           time.Now,
 	}
+	exporter, err := newExporter(os.Getenv(EnvExportOutput))
+	if err != nil {
+		log.Error("datastreams: ignoring %s: %v", EnvExportOutput, err.Error())
+	}
+	p.exporter = exporter
 	return p
 }
 
@@ -272,6 +318,7 @@ func (p *Processor) add(point statsPoint) {
 }
 
 func (p *Processor) addKafkaOffset(o kafkaOffset) {
+	p.consumerLag.track(o)
 	btime := alignTs(o.timestamp, bucketDuration.Nanoseconds())
 	b := p.getBucket(btime, p.service, p.tsTypeCurrentBuckets)
 	if o.offsetType == produceOffset {
@@ -295,12 +342,24 @@ func (p *Processor) addKafkaOffset(o kafkaOffset) {
 	}] = o.offset
 }
 
+func (p *Processor) addBacklog(b backlog) {
+	btime := alignTs(b.timestamp, bucketDuration.Nanoseconds())
+	bucket := p.getBucket(btime, p.service, p.tsTypeCurrentBuckets)
+	bucket.latestBacklogs[strings.Join(b.tags, ",")] = Backlog{Tags: b.tags, Value: b.value}
+}
+
 func (p *Processor) processInput(in *processorInput) {
 	atomic.AddInt64(&p.stats.payloadsIn, 1)
 	if in.typ == pointTypeStats {
 		p.add(in.point)
 	} else if in.typ == pointTypeKafkaOffset {
 		p.addKafkaOffset(in.kafkaOffset)
+	} else if in.typ == pointTypeBacklog {
+		p.addBacklog(in.backlog)
+	} else if in.typ == pointTypeSchema {
+		p.addSchema(in.schema)
+	} else if in.typ == pointTypeTransaction {
+		p.addTransaction(in.transaction)
 	}
 }
 
@@ -323,6 +382,7 @@ func (p *Processor) run(tick <-chan time.Time) {
 			return
 		case now := <-tick:
 			p.sendToAgent(p.flush(now))
+			p.reportConsumerLag()
 		case done := <-p.flushRequest:
 			p.flushInput()
 			p.sendToAgent(p.flush(time.Now().Add(bucketDuration * 10)))
@@ -379,6 +439,9 @@ func (p *Processor) Stop() {
 	}
 	close(p.stop)
 	p.wg.Wait()
+	if p.exporter != nil {
+		p.exporter.close()
+	}
 }
 
 func (p *Processor) reportStats() {
@@ -395,12 +458,14 @@ func (p *Processor) reportStats() {
 		p.statsd.Count("datadog.datastreams.processor.flushed_buckets", atomic.SwapInt64(&p.stats.flushedBuckets, 0), nil, 1)
 		p.statsd.Count("datadog.datastreams.processor.flush_errors", atomic.SwapInt64(&p.stats.flushErrors, 0), nil, 1)
 		p.statsd.Count("datadog.datastreams.processor.dropped_payloads", atomic.SwapInt64(&p.stats.dropped, 0), nil, 1)
+		p.statsd.Count("datadog.datastreams.processor.dropped_transactions", atomic.SwapInt64(&p.stats.droppedTransactions, 0), nil, 1)
 	}
 }
 
 func (p *Processor) flushBucket(buckets map[bucketKey]bucket, bucketKey bucketKey, timestampType TimestampType) StatsBucket {
 	bucket := buckets[bucketKey]
 	delete(buckets, bucketKey)
+	p.pendingTransactions -= len(bucket.transactions)
 	return bucket.export(timestampType)
 }
 
@@ -440,6 +505,9 @@ func (p *Processor) flush(now time.Time) map[string]StatsPayload {
 }
 
 func (p *Processor) sendToAgent(payloads map[string]StatsPayload) {
+	if p.exporter != nil {
+		p.exporter.export(payloads)
+	}
 	for _, payload := range payloads {
 		atomic.AddInt64(&p.stats.flushedPayloads, 1)
 		atomic.AddInt64(&p.stats.flushedBuckets, int64(len(payload.Stats)))
@@ -529,3 +597,19 @@ func (p *Processor) TrackKafkaHighWatermarkOffset(_ string, topic string, partit
 		atomic.AddInt64(&p.stats.dropped, 1)
 	}
 }
+
+// TrackBacklog tracks the latest value of the queue backlog identified by the given tags, such as the approximate
+// number of messages in a queue, or the delay of a stream consumer. The tags must include the backlog type.
+func (p *Processor) TrackBacklog(value int64, tags ...string) {
+	sorted := make([]string, len(tags))
+	copy(sorted, tags)
+	sort.Strings(sorted)
+	dropped := p.in.push(&processorInput{typ: pointTypeBacklog, backlog: backlog{
+		tags:      sorted,
+		value:     value,
+		timestamp: p.time().UnixNano(),
+	}})
+	if dropped {
+		atomic.AddInt64(&p.stats.dropped, 1)
+	}
+}
@@ -356,6 +356,21 @@ func TestKafkaLag(t *testing.T) {
 	assert.Equal(t, expectedBacklogs, payloads["service"].Stats[0].Backlogs)
 }
 
+func TestBacklog(t *testing.T) {
+	p := NewProcessor(nil, "env", "service", "v1", &url.URL{Scheme: "http", Host: "agent-address"}, nil)
+	tp1 := time.Now()
+	p.addBacklog(backlog{tags: []string{"queue:queue1", "type:sqs_approximate_messages"}, value: 3})
+	p.addBacklog(backlog{tags: []string{"queue:queue1", "type:sqs_approximate_messages"}, value: 7})
+	payloads := sortedPayloads(p.flush(tp1.Add(bucketDuration * 2)))
+	expectedBacklogs := []Backlog{
+		{
+			Tags:  []string{"queue:queue1", "type:sqs_approximate_messages"},
+			Value: 7,
+		},
+	}
+	assert.Equal(t, expectedBacklogs, payloads["service"].Stats[0].Backlogs)
+}
+
 type noOpTransport struct{}
 
 // RoundTrip does nothing and returns a dummy response.
@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
+)
+
+// schemaSampleInterval is the minimum interval between two samples of the schema usage of a topic and operation,
+// unless the schema changes.
+const schemaSampleInterval = 30 * time.Second
+
+type schemaSamplerKey struct {
+	topic     string
+	operation options.SchemaOperation
+}
+
+type schemaSamplerState struct {
+	lastID     string
+	lastSample time.Time
+	weight     int64
+}
+
+// schemaSampler bounds the overhead of the schema tracking by sampling the schema usage of each topic and operation
+// at most once per schemaSampleInterval, and right away when the schema changes. The samples are weighted by the
+// number of schema usages they account for.
+type schemaSampler struct {
+	mu     sync.Mutex
+	states map[schemaSamplerKey]*schemaSamplerState
+}
+
+func newSchemaSampler() *schemaSampler {
+	return &schemaSampler{states: make(map[schemaSamplerKey]*schemaSamplerState)}
+}
+
+// sample records a usage of the schema identified by id, and returns the weight of the sample and whether the
+// schema changed since the previous sample, or ok=false when the usage is not sampled.
+func (s *schemaSampler) sample(topic string, operation options.SchemaOperation, id string, now time.Time) (weight int64, changed bool, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := schemaSamplerKey{topic: topic, operation: operation}
+	state, found := s.states[key]
+	if !found {
+		state = &schemaSamplerState{}
+		s.states[key] = state
+	}
+	state.weight++
+	changed = found && state.lastID != id
+	if found && !changed && now.Sub(state.lastSample) < schemaSampleInterval {
+		return 0, false, false
+	}
+	weight = state.weight
+	state.weight = 0
+	state.lastID = id
+	state.lastSample = now
+	return weight, changed, true
+}
+
+type schemaBucketKey struct {
+	topic     string
+	operation string
+	id        string
+}
+
+// schemaPoint is a sampled schema usage.
+type schemaPoint struct {
+	topic     string
+	operation options.SchemaOperation
+	schema    options.Schema
+	weight    int64
+	changed   bool
+	timestamp int64
+}
+
+func (p *Processor) addSchema(point schemaPoint) {
+	btime := alignTs(point.timestamp, bucketDuration.Nanoseconds())
+	b := p.getBucket(btime, p.service, p.tsTypeCurrentBuckets)
+	key := schemaBucketKey{topic: point.topic, operation: string(point.operation), id: point.schema.ID}
+	usage, ok := b.schemas[key]
+	if !ok {
+		usage = SchemaUsage{
+			Topic:      point.topic,
+			Operation:  string(point.operation),
+			ID:         point.schema.ID,
+			Name:       point.schema.Name,
+			Type:       point.schema.Type,
+			Definition: point.schema.Definition,
+		}
+	}
+	usage.Weight += point.weight
+	usage.Changed = usage.Changed || point.changed
+	b.schemas[key] = usage
+}
+
+// TrackSchema tracks the usage of a schema to serialize or deserialize a payload of the given topic. The usages
+// are sampled to bound the overhead, so that the processor aggregates the schema usages per topic and detects
+// the schema changes.
+func (p *Processor) TrackSchema(topic string, operation options.SchemaOperation, schema options.Schema) {
+	now := p.time()
+	weight, changed, ok := p.schemaSampler.sample(topic, operation, schema.ID, now)
+	if !ok {
+		return
+	}
+	dropped := p.in.push(&processorInput{typ: pointTypeSchema, schema: schemaPoint{
+		topic:     topic,
+		operation: operation,
+		schema:    schema,
+		weight:    weight,
+		changed:   changed,
+		timestamp: now.UnixNano(),
+	}})
+	if dropped {
+		atomic.AddInt64(&p.stats.dropped, 1)
+	}
+}
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/dd-trace-go/v2/datastreams/options"
+)
+
+func TestSchemaSampler(t *testing.T) {
+	s := newSchemaSampler()
+	now := time.Now()
+	sample := func(id string, now time.Time) (int64, bool, bool) {
+		return s.sample("topic", options.SchemaOperationSerialization, id, now)
+	}
+
+	weight, changed, ok := sample("1", now)
+	assert.True(t, ok, "the first usage should be sampled")
+	assert.Equal(t, int64(1), weight)
+	assert.False(t, changed)
+
+	for i := 0; i < 3; i++ {
+		_, _, ok = sample("1", now.Add(time.Second))
+		assert.False(t, ok, "the usages within the sampling interval should not be sampled")
+	}
+
+	weight, changed, ok = sample("1", now.Add(schemaSampleInterval))
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), weight)
+	assert.False(t, changed)
+
+	weight, changed, ok = sample("2", now.Add(schemaSampleInterval+time.Second))
+	assert.True(t, ok, "a schema change should be sampled right away")
+	assert.Equal(t, int64(1), weight)
+	assert.True(t, changed)
+
+	_, changed, ok = s.sample("topic", options.SchemaOperationDeserialization, "1", now.Add(schemaSampleInterval+time.Second))
+	assert.True(t, ok, "the operations should be sampled independently")
+	assert.False(t, changed)
+}
+
+func TestSchemaUsage(t *testing.T) {
+	p := NewProcessor(nil, "env", "service", "v1", &url.URL{Scheme: "http", Host: "agent-address"}, nil)
+	tp1 := time.Now()
+	schema := options.Schema{ID: "1", Name: "User", Type: "avro"}
+	p.addSchema(schemaPoint{topic: "topic1", operation: options.SchemaOperationSerialization, schema: schema, weight: 1})
+	p.addSchema(schemaPoint{topic: "topic1", operation: options.SchemaOperationSerialization, schema: schema, weight: 4})
+	p.addSchema(schemaPoint{topic: "topic1", operation: options.SchemaOperationSerialization, schema: options.Schema{ID: "2", Name: "User", Type: "avro"}, weight: 1, changed: true})
+	payloads := sortedPayloads(p.flush(tp1.Add(bucketDuration * 2)))
+	assert.ElementsMatch(t, []SchemaUsage{
+		{Topic: "topic1", Operation: "serialization", ID: "1", Name: "User", Type: "avro", Weight: 5},
+		{Topic: "topic1", Operation: "serialization", ID: "2", Name: "User", Type: "avro", Weight: 1, Changed: true},
+	}, payloads["service"].Stats[0].Schemas)
+}
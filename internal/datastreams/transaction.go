@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+const (
+	// maxPendingTransactions bounds the number of transaction checkpoints buffered until they are flushed.
+	maxPendingTransactions = 10000
+	// maxTransactionIDLength bounds the length of the transaction IDs, which are truncated beyond it.
+	maxTransactionIDLength = 256
+)
+
+func (p *Processor) addTransaction(t Transaction) {
+	if p.pendingTransactions >= maxPendingTransactions {
+		atomic.AddInt64(&p.stats.droppedTransactions, 1)
+		return
+	}
+	btime := alignTs(t.Timestamp, bucketDuration.Nanoseconds())
+	b := p.getBucket(btime, p.service, p.tsTypeCurrentBuckets)
+	b.transactions = append(b.transactions, t)
+	// the bucket is stored by value, so it must be stored back once its slice grew
+	p.tsTypeCurrentBuckets[bucketKey{serviceName: p.service, btime: btime}] = b
+	p.pendingTransactions++
+}
+
+// TrackTransaction tracks a checkpoint of the business transaction identified by transactionID, such as an order
+// ID, so that it can be followed across services that do not share a trace context. The checkpoints are batched
+// with the stats buckets, and dropped when too many of them are waiting to be flushed.
+func (p *Processor) TrackTransaction(ctx context.Context, transactionID, checkpointName string) {
+	if transactionID == "" {
+		return
+	}
+	if len(transactionID) > maxTransactionIDLength {
+		transactionID = transactionID[:maxTransactionIDLength]
+	}
+	t := Transaction{
+		ID:         transactionID,
+		Checkpoint: checkpointName,
+		Timestamp:  p.time().UnixNano(),
+	}
+	if pathway, ok := PathwayFromContext(ctx); ok {
+		t.PathwayHash = pathway.GetHash()
+	}
+	dropped := p.in.push(&processorInput{typ: pointTypeTransaction, transaction: t})
+	if dropped {
+		atomic.AddInt64(&p.stats.droppedTransactions, 1)
+	}
+}
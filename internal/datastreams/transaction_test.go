@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package datastreams
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackTransaction(t *testing.T) {
+	p := NewProcessor(nil, "env", "service", "v1", &url.URL{Scheme: "http", Host: "agent-address"}, nil)
+	tp1 := time.Now().Truncate(bucketDuration)
+	p.timeSource = func() time.Time { return tp1 }
+
+	ctx := p.SetCheckpoint(context.Background(), "direction:out", "type:kafka", "topic:topic1")
+	pathway, _ := PathwayFromContext(ctx)
+	p.TrackTransaction(ctx, "order-1", "order-received")
+	p.TrackTransaction(context.Background(), strings.Repeat("x", maxTransactionIDLength+1), "order-shipped")
+	p.TrackTransaction(context.Background(), "", "ignored")
+	p.flushInput()
+
+	payloads := sortedPayloads(p.flush(tp1.Add(bucketDuration * 2)))
+	require.NotEmpty(t, payloads["service"].Stats)
+	assert.Equal(t, []Transaction{
+		{ID: "order-1", Checkpoint: "order-received", Timestamp: tp1.UnixNano(), PathwayHash: pathway.GetHash()},
+		{ID: strings.Repeat("x", maxTransactionIDLength), Checkpoint: "order-shipped", Timestamp: tp1.UnixNano()},
+	}, payloads["service"].Stats[0].Transactions)
+	assert.Zero(t, p.pendingTransactions)
+}
+
+func TestTrackTransactionBuffer(t *testing.T) {
+	p := NewProcessor(nil, "env", "service", "v1", &url.URL{Scheme: "http", Host: "agent-address"}, nil)
+	tp1 := time.Now()
+	for i := 0; i < maxPendingTransactions+10; i++ {
+		p.addTransaction(Transaction{ID: "order", Checkpoint: "checkpoint", Timestamp: tp1.UnixNano()})
+	}
+	assert.Equal(t, maxPendingTransactions, p.pendingTransactions)
+	assert.Equal(t, int64(10), p.stats.droppedTransactions)
+
+	payloads := p.flush(tp1.Add(bucketDuration * 2))
+	assert.Len(t, payloads["service"].Stats[0].Transactions, maxPendingTransactions)
+	assert.Zero(t, p.pendingTransactions)
+}
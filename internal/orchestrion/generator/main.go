@@ -46,6 +46,13 @@ var (
 	//go:embed go.mod.tmpl
 	goModTemplateText string
 	goModTemplate     = template.Must(template.New("go.mod").Parse(goModTemplateText))
+
+	// optIn lists the packages, relative to the repository root, whose
+	// integration must be explicitly imported by the application's
+	// orchestrion.tool.go file, and is thus not part of the root configuration.
+	optIn = []string{
+		"instrumentation/appsec/iast",
+	}
 )
 
 func main() {
@@ -94,6 +101,10 @@ func generateRootConfig(rootDir string) (map[string]string, error) {
 			return nil
 		}
 
+		if slices.Contains(optIn, filepath.ToSlash(rel)) {
+			return nil
+		}
+
 		pkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName | packages.NeedModule, Dir: rootDir}, "./"+rel)
 		if err != nil {
 			log.Fatalln(err)
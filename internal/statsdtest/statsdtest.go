@@ -58,6 +58,10 @@ func (t TestStatsdCall) Tags() []string {
 	return t.tags
 }
 
+func (t TestStatsdCall) FloatVal() float64 {
+	return t.floatVal
+}
+
 func (t TestStatsdCall) IntVal() int64 {
 	return t.intVal
 }
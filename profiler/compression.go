@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression specifies how pprof profiles are compressed before they are
+// uploaded.
+type Compression string
+
+const (
+	// GzipCompression uploads pprof profiles as they are produced by the Go
+	// runtime, i.e. gzip compressed. This is the default.
+	GzipCompression Compression = "gzip"
+	// ZstdCompression re-compresses pprof profiles with zstd before they are
+	// uploaded. This usually results in noticeably smaller uploads at the
+	// cost of some CPU time.
+	ZstdCompression Compression = "zstd"
+)
+
+// valid returns whether c is a supported compression.
+func (c Compression) valid() bool {
+	return c == GzipCompression || c == ZstdCompression
+}
+
+// isZstdData returns whether data starts with the zstd frame magic number.
+func isZstdData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte{0x28, 0xb5, 0x2f, 0xfd})
+}
+
+var (
+	// zstdEncoder is shared by all profilers, EncodeAll is safe for
+	// concurrent use.
+	zstdEncoder     *zstd.Encoder
+	zstdDecoder     *zstd.Decoder
+	zstdInitOnce    sync.Once
+	errZstdInitOnce error
+)
+
+func initZstd() error {
+	zstdInitOnce.Do(func() {
+		zstdEncoder, errZstdInitOnce = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		if errZstdInitOnce != nil {
+			return
+		}
+		zstdDecoder, errZstdInitOnce = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	})
+	return errZstdInitOnce
+}
+
+// decompress returns the uncompressed form of gzip or zstd compressed data.
+// Uncompressed data is returned as-is.
+func decompress(data []byte) ([]byte, error) {
+	switch {
+	case isGzipData(data):
+		gzr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(gzr)
+	case isZstdData(data):
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdDecoder.DecodeAll(data, nil)
+	}
+	return data, nil
+}
+
+// compress compresses the given uncompressed data with c.
+func compress(c Compression, data []byte) ([]byte, error) {
+	if c == ZstdCompression {
+		if err := initZstd(); err != nil {
+			return nil, err
+		}
+		return zstdEncoder.EncodeAll(data, nil), nil
+	}
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	if _, err := gzw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// recompress returns data compressed with c. Only gzip compressed data, i.e.
+// pprof profiles, is re-compressed. Other data, like metrics.json or execution
+// traces, is returned as-is.
+func recompress(c Compression, data []byte) ([]byte, error) {
+	if c == GzipCompression || !isGzipData(data) {
+		return data, nil
+	}
+	raw, err := decompress(data)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing profile: %v", err)
+	}
+	return compress(c, raw)
+}
@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"testing"
+
+	pprofile "github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecompress(t *testing.T) {
+	gz := pgoTestCPUProfile(t, "main;foo 1\nmain;bar 2\n")
+
+	t.Run("zstd", func(t *testing.T) {
+		data, err := recompress(ZstdCompression, gz)
+		require.NoError(t, err)
+		require.True(t, isZstdData(data))
+		raw, err := decompress(data)
+		require.NoError(t, err)
+		prof, err := pprofile.ParseUncompressed(raw)
+		require.NoError(t, err)
+		assert.Len(t, prof.Sample, 2)
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		data, err := recompress(GzipCompression, gz)
+		require.NoError(t, err)
+		assert.Equal(t, gz, data)
+	})
+
+	t.Run("not-pprof", func(t *testing.T) {
+		data, err := recompress(ZstdCompression, []byte(`{"metrics":[]}`))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(`{"metrics":[]}`), data)
+	})
+}
+
+func TestUploadCompression(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		p, err := unstartedProfiler()
+		require.NoError(t, err)
+		assert.Equal(t, GzipCompression, p.cfg.compression)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("DD_PROFILING_UPLOAD_COMPRESSION", "ZSTD")
+		p, err := unstartedProfiler()
+		require.NoError(t, err)
+		assert.Equal(t, ZstdCompression, p.cfg.compression)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := unstartedProfiler(WithUploadCompression("lz4"))
+		assert.Error(t, err)
+	})
+
+	t.Run("upload", func(t *testing.T) {
+		p, err := unstartedProfiler(WithUploadCompression(ZstdCompression))
+		require.NoError(t, err)
+		gz := pgoTestCPUProfile(t, "main;foo 1\n")
+		bat := batch{profiles: []*profile{
+			{name: "cpu.pprof", pt: CPUProfile, data: gz},
+			{name: "metrics.json", pt: MetricsProfile, data: []byte("[]")},
+		}}
+		up := p.prepareUpload(bat)
+		assert.True(t, isZstdData(up.profiles[0].data))
+		assert.Equal(t, []byte("[]"), up.profiles[1].data)
+		// the original batch is left untouched
+		assert.True(t, bytes.Equal(gz, bat.profiles[0].data))
+	})
+}
@@ -0,0 +1,323 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pprofile "github.com/google/pprof/profile"
+)
+
+// The Go runtime doesn't record pprof labels for the samples of the mutex and
+// block profiles, so contention can't be attributed to the spans and endpoints
+// experiencing it. Mutex and RWMutex work around this: they measure how long
+// acquiring the lock takes when it is contended, and record it in the labeled
+// contention profile along with the pprof labels found in the given context,
+// which include the span ID and endpoint labels applied by the tracer.
+
+const (
+	// contentionMaxStackDepth is the maximum number of frames recorded for a
+	// contention sample.
+	contentionMaxStackDepth = 32
+	// contentionMaxSamples bounds the number of distinct (stack, labels)
+	// combinations kept in memory during a profiling period.
+	contentionMaxSamples = 10000
+)
+
+// contention is the global recorder used by Mutex and RWMutex.
+var contention contentionRecorder
+
+// contentionRecorder aggregates sampled lock contention events by stack and
+// pprof labels.
+type contentionRecorder struct {
+	// rate is the sampling rate, on average 1/rate contention events are
+	// recorded. A rate <= 0 disables recording.
+	rate atomic.Int64
+	// dropped counts events which couldn't be recorded because
+	// contentionMaxSamples was reached.
+	dropped atomic.Int64
+
+	mu      sync.Mutex
+	samples map[contentionKey]*contentionSample
+}
+
+type contentionKey struct {
+	stack  [contentionMaxStackDepth]uintptr
+	labels string
+}
+
+type contentionSample struct {
+	stack       []uintptr
+	labels      map[string][]string
+	contentions int64
+	delay       int64
+}
+
+// setRate enables recording with the given sampling rate, or disables it if
+// rate <= 0. Recorded samples are discarded.
+func (c *contentionRecorder) setRate(rate int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samples = nil
+	c.dropped.Store(0)
+	c.rate.Store(int64(rate))
+}
+
+// sample returns whether a contention event should be recorded, and the rate
+// it should be scaled by.
+func (c *contentionRecorder) sample() (int64, bool) {
+	rate := c.rate.Load()
+	if rate <= 0 {
+		return 0, false
+	}
+	return rate, rate == 1 || rand.Int64N(rate) == 0
+}
+
+// record adds a contention event which delayed the caller by d. skip is the
+// number of stack frames to omit, relative to the caller of record.
+func (c *contentionRecorder) record(ctx context.Context, d time.Duration, rate int64, skip int) {
+	var key contentionKey
+	n := runtime.Callers(skip+2, key.stack[:])
+	var labels map[string][]string
+	if ctx != nil {
+		var kvs []string
+		pprof.ForLabels(ctx, func(k, v string) bool {
+			if labels == nil {
+				labels = make(map[string][]string)
+			}
+			labels[k] = []string{v}
+			kvs = append(kvs, k+"="+v)
+			return true
+		})
+		sort.Strings(kvs)
+		key.labels = strings.Join(kvs, "\x00")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rate.Load() <= 0 {
+		// disabled while we were waiting for the lock
+		return
+	}
+	if c.samples == nil {
+		c.samples = make(map[contentionKey]*contentionSample)
+	}
+	s, ok := c.samples[key]
+	if !ok {
+		if len(c.samples) >= contentionMaxSamples {
+			c.dropped.Add(1)
+			return
+		}
+		s = &contentionSample{stack: append([]uintptr(nil), key.stack[:n]...), labels: labels}
+		c.samples[key] = s
+	}
+	s.contentions += rate
+	s.delay += int64(d) * rate
+}
+
+// flush returns the samples recorded since the last flush as a gzipped pprof
+// profile, and resets the recorder.
+func (c *contentionRecorder) flush(start, end time.Time) ([]byte, error) {
+	c.mu.Lock()
+	samples := c.samples
+	c.samples = nil
+	dropped := c.dropped.Swap(0)
+	c.mu.Unlock()
+
+	prof := &pprofile.Profile{
+		SampleType: []*pprofile.ValueType{
+			{Type: "contentions", Unit: "count"},
+			{Type: "delay", Unit: "nanoseconds"},
+		},
+		PeriodType:    &pprofile.ValueType{Type: "contentions", Unit: "count"},
+		Period:        1,
+		TimeNanos:     start.UnixNano(),
+		DurationNanos: end.Sub(start).Nanoseconds(),
+	}
+	if dropped > 0 {
+		prof.Comments = append(prof.Comments, fmt.Sprintf("dropped contention events: %d", dropped))
+	}
+	m := &pprofile.Mapping{ID: 1, HasFunctions: true}
+	prof.Mapping = []*pprofile.Mapping{m}
+	functions := make(map[string]*pprofile.Function)
+	locations := make(map[uintptr]*pprofile.Location)
+	for _, s := range samples {
+		sample := &pprofile.Sample{
+			Value: []int64{s.contentions, s.delay},
+			Label: s.labels,
+		}
+		for _, pc := range s.stack {
+			loc, ok := locations[pc]
+			if !ok {
+				loc = &pprofile.Location{ID: uint64(len(prof.Location) + 1), Mapping: m, Address: uint64(pc)}
+				frames := runtime.CallersFrames([]uintptr{pc})
+				for {
+					frame, more := frames.Next()
+					fn, ok := functions[frame.Function]
+					if !ok {
+						fn = &pprofile.Function{
+							ID:       uint64(len(prof.Function) + 1),
+							Name:     frame.Function,
+							Filename: frame.File,
+						}
+						functions[frame.Function] = fn
+						prof.Function = append(prof.Function, fn)
+					}
+					// pprof expects inlined frames first, which is the
+					// order CallersFrames returns them in.
+					loc.Line = append(loc.Line, pprofile.Line{Function: fn, Line: int64(frame.Line)})
+					if !more {
+						break
+					}
+				}
+				locations[pc] = loc
+				prof.Location = append(prof.Location, loc)
+			}
+			sample.Location = append(sample.Location, loc)
+		}
+		prof.Sample = append(prof.Sample, sample)
+	}
+	var buf bytes.Buffer
+	if err := prof.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Mutex is a drop-in replacement for sync.Mutex which records lock contention
+// in the LabeledContentionProfile, attributed to the pprof labels of the
+// context given to LockContext. When the profile is not enabled, the overhead
+// compared to a sync.Mutex is a failed TryLock on contended acquisitions.
+//
+// A Mutex must not be copied after first use.
+type Mutex struct {
+	mu sync.Mutex
+}
+
+// Lock locks m. Contention is recorded without labels.
+func (m *Mutex) Lock() {
+	m.lockContext(nil)
+}
+
+// LockContext locks m. If the lock is contended, the time spent waiting for
+// it is recorded along with the pprof labels found in ctx, e.g. the span ID
+// and endpoint labels of the context returned by tracer.StartSpanFromContext.
+func (m *Mutex) LockContext(ctx context.Context) {
+	m.lockContext(ctx)
+}
+
+func (m *Mutex) lockContext(ctx context.Context) {
+	if m.mu.TryLock() {
+		return
+	}
+	rate, ok := contention.sample()
+	if !ok {
+		m.mu.Lock()
+		return
+	}
+	start := time.Now()
+	m.mu.Lock()
+	// skip lockContext and its caller, Lock or LockContext
+	contention.record(ctx, time.Since(start), rate, 2)
+}
+
+// TryLock tries to lock m and reports whether it succeeded.
+func (m *Mutex) TryLock() bool {
+	return m.mu.TryLock()
+}
+
+// Unlock unlocks m.
+func (m *Mutex) Unlock() {
+	m.mu.Unlock()
+}
+
+// RWMutex is a drop-in replacement for sync.RWMutex which records lock
+// contention like Mutex does.
+//
+// A RWMutex must not be copied after first use.
+type RWMutex struct {
+	mu sync.RWMutex
+}
+
+// Lock locks rw for writing. Contention is recorded without labels.
+func (rw *RWMutex) Lock() {
+	rw.lockContext(nil)
+}
+
+// LockContext locks rw for writing, recording contention with the pprof
+// labels found in ctx.
+func (rw *RWMutex) LockContext(ctx context.Context) {
+	rw.lockContext(ctx)
+}
+
+func (rw *RWMutex) lockContext(ctx context.Context) {
+	if rw.mu.TryLock() {
+		return
+	}
+	rate, ok := contention.sample()
+	if !ok {
+		rw.mu.Lock()
+		return
+	}
+	start := time.Now()
+	rw.mu.Lock()
+	contention.record(ctx, time.Since(start), rate, 2)
+}
+
+// RLock locks rw for reading. Contention is recorded without labels.
+func (rw *RWMutex) RLock() {
+	rw.rlockContext(nil)
+}
+
+// RLockContext locks rw for reading, recording contention with the pprof
+// labels found in ctx.
+func (rw *RWMutex) RLockContext(ctx context.Context) {
+	rw.rlockContext(ctx)
+}
+
+func (rw *RWMutex) rlockContext(ctx context.Context) {
+	if rw.mu.TryRLock() {
+		return
+	}
+	rate, ok := contention.sample()
+	if !ok {
+		rw.mu.RLock()
+		return
+	}
+	start := time.Now()
+	rw.mu.RLock()
+	contention.record(ctx, time.Since(start), rate, 2)
+}
+
+// TryLock tries to lock rw for writing and reports whether it succeeded.
+func (rw *RWMutex) TryLock() bool {
+	return rw.mu.TryLock()
+}
+
+// TryRLock tries to lock rw for reading and reports whether it succeeded.
+func (rw *RWMutex) TryRLock() bool {
+	return rw.mu.TryRLock()
+}
+
+// Unlock unlocks rw for writing.
+func (rw *RWMutex) Unlock() {
+	rw.mu.Unlock()
+}
+
+// RUnlock undoes a single RLock call.
+func (rw *RWMutex) RUnlock() {
+	rw.mu.RUnlock()
+}
@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+
+	pprofile "github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contend makes lock block for roughly d by holding locker in another
+// goroutine, and calls unlock after lock returned.
+func contend(locker sync.Locker, lock, unlock func(), d time.Duration) {
+	locker.Lock()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(d)
+		locker.Unlock()
+	}()
+	lock()
+	wg.Wait()
+	unlock()
+}
+
+func TestLabeledContention(t *testing.T) {
+	contention.setRate(1)
+	defer contention.setRate(0)
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels(
+		traceprof.SpanID, "123",
+		traceprof.TraceEndpoint, "GET /foo",
+	))
+	var mu Mutex
+	contend(&mu, func() { mu.LockContext(ctx) }, mu.Unlock, 10*time.Millisecond)
+	var rw RWMutex
+	contend(&rw, func() { rw.RLockContext(ctx) }, rw.RUnlock, 10*time.Millisecond)
+	// unlabeled contention is recorded as well
+	contend(&mu, mu.Lock, mu.Unlock, 10*time.Millisecond)
+
+	start := time.Now()
+	data, err := contention.flush(start, start.Add(time.Minute))
+	require.NoError(t, err)
+	prof, err := pprofile.ParseData(data)
+	require.NoError(t, err)
+	require.NoError(t, prof.CheckValid())
+	require.Len(t, prof.Sample, 3)
+
+	var labeled int
+	for _, s := range prof.Sample {
+		assert.Equal(t, int64(1), s.Value[0])
+		assert.GreaterOrEqual(t, s.Value[1], (5 * time.Millisecond).Nanoseconds())
+		var stack []string
+		for _, loc := range s.Location {
+			for _, line := range loc.Line {
+				stack = append(stack, line.Function.Name)
+			}
+		}
+		assert.Contains(t, strings.Join(stack, ";"), "TestLabeledContention")
+		if len(s.Label) > 0 {
+			labeled++
+			assert.Equal(t, []string{"123"}, s.Label[traceprof.SpanID])
+			assert.Equal(t, []string{"GET /foo"}, s.Label[traceprof.TraceEndpoint])
+		}
+	}
+	assert.Equal(t, 2, labeled)
+
+	// flushing resets the recorder
+	data, err = contention.flush(start, start.Add(time.Minute))
+	require.NoError(t, err)
+	prof, err = pprofile.ParseData(data)
+	require.NoError(t, err)
+	assert.Empty(t, prof.Sample)
+}
+
+func TestLabeledContentionDisabled(t *testing.T) {
+	var mu Mutex
+	contend(&mu, mu.Lock, mu.Unlock, time.Millisecond)
+	contention.mu.Lock()
+	defer contention.mu.Unlock()
+	assert.Empty(t, contention.samples)
+}
+
+func TestLabeledContentionProfileEnabled(t *testing.T) {
+	p, err := unstartedProfiler(
+		WithProfileTypes(LabeledContentionProfile),
+		MutexProfileFraction(5),
+		WithPeriod(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	p.run()
+	assert.Equal(t, int64(5), contention.rate.Load())
+	p.stop()
+	assert.Equal(t, int64(0), contention.rate.Load())
+}
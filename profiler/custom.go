@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+)
+
+// firstCustomProfileType is the ProfileType assigned to the first custom
+// profile. It leaves plenty of room for built-in profile types.
+const firstCustomProfileType ProfileType = 1 << 16
+
+// ValueType describes a sample value of a profile in pprof format, e.g.
+// {Type: "alloc_space", Unit: "bytes"}.
+type ValueType struct {
+	Type string
+	Unit string
+}
+
+// CustomProfile describes a profile type provided by the application, see
+// RegisterCustomProfile.
+type CustomProfile struct {
+	// Name identifies the profile type. If Collect is nil, the profile is
+	// collected with pprof.Lookup(Name), so Name must refer to a
+	// runtime/pprof.Profile created with pprof.NewProfile.
+	Name string
+	// Filename is the filename used when uploading the profile. It defaults
+	// to Name + ".pprof". Delta profiles are prefixed with "delta-"
+	// automatically.
+	Filename string
+	// Collect, if not nil, is called at the end of every profiling period
+	// and must return the profile in pprof format (optionally gzipped).
+	Collect func() ([]byte, error)
+	// DeltaValues identifies which sample values are cumulative and should
+	// be turned into deltas between profiling periods when delta profiles
+	// are enabled, using the same algorithm as for the heap profile. Empty
+	// DeltaValues means the profile is uploaded as-is, which is what you
+	// want for a pprof.Profile since it tracks live objects.
+	DeltaValues []ValueType
+}
+
+var (
+	// customProfilesMu guards customProfileTypes.
+	customProfilesMu   sync.RWMutex
+	customProfileTypes = map[ProfileType]profileType{}
+)
+
+// RegisterCustomProfile registers a profile type provided by the application,
+// for example a runtime/pprof.Profile tracking open connections or cache
+// entries. The returned ProfileType must be passed to WithProfileTypes for the
+// profile to be collected and uploaded alongside the built-in profile types.
+//
+// An error is returned if the name is empty or already used by another
+// profile type.
+func RegisterCustomProfile(cp CustomProfile) (ProfileType, error) {
+	if cp.Name == "" {
+		return 0, errors.New("custom profile name must not be empty")
+	}
+	if cp.Filename == "" {
+		cp.Filename = cp.Name + ".pprof"
+	}
+	customProfilesMu.Lock()
+	defer customProfilesMu.Unlock()
+	for _, t := range profileTypes {
+		if t.Name == cp.Name || t.Filename == cp.Filename {
+			return 0, fmt.Errorf("custom profile %q conflicts with built-in profile type %s", cp.Name, t.Name)
+		}
+	}
+	for _, t := range customProfileTypes {
+		if t.Name == cp.Name || t.Filename == cp.Filename {
+			return 0, fmt.Errorf("custom profile %q is already registered", cp.Name)
+		}
+	}
+	pt := firstCustomProfileType + ProfileType(len(customProfileTypes))
+	t := profileType{
+		Name:     cp.Name,
+		Filename: cp.Filename,
+		Collect:  collectGenericProfile(cp.Name, pt),
+	}
+	if cp.Collect != nil {
+		t.Collect = collectCustomProfile(cp.Collect, pt)
+	}
+	for _, v := range cp.DeltaValues {
+		t.DeltaValues = append(t.DeltaValues, pprofutils.ValueType{Type: v.Type, Unit: v.Unit})
+	}
+	customProfileTypes[pt] = t
+	return pt, nil
+}
+
+// lookupCustomProfile returns the custom profile type registered as pt.
+func lookupCustomProfile(pt ProfileType) (profileType, bool) {
+	customProfilesMu.RLock()
+	defer customProfilesMu.RUnlock()
+	t, ok := customProfileTypes[pt]
+	return t, ok
+}
+
+// collectCustomProfile returns a Collect function which calls collect at the
+// end of the profiling period, and computes a delta profile if needed.
+func collectCustomProfile(collect func() ([]byte, error), pt ProfileType) func(p *profiler) ([]byte, error) {
+	return func(p *profiler) ([]byte, error) {
+		p.interruptibleSleep(p.cfg.period)
+
+		data, err := collect()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.deltas[pt]; !ok || !p.cfg.deltaProfiles {
+			return data, nil
+		}
+		return p.deltaProfile(pt, data)
+	}
+}
+
+// known returns whether pt is a built-in or registered custom profile type.
+func (pt ProfileType) known() bool {
+	if _, ok := profileTypes[pt]; ok {
+		return true
+	}
+	_, ok := lookupCustomProfile(pt)
+	return ok
+}
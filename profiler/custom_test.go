@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	pprofile "github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConnectionsProfile is registered once, as pprof.NewProfile panics if a
+// profile name is used twice.
+var testConnectionsProfile = pprof.NewProfile("dd-trace-go.test.connections")
+
+// resetCustomProfiles unregisters all custom profiles when the test ends.
+func resetCustomProfiles(t *testing.T) {
+	t.Cleanup(func() {
+		customProfilesMu.Lock()
+		defer customProfilesMu.Unlock()
+		clear(customProfileTypes)
+	})
+}
+
+func TestRegisterCustomProfile(t *testing.T) {
+	resetCustomProfiles(t)
+
+	pt, err := RegisterCustomProfile(CustomProfile{Name: "connections"})
+	require.NoError(t, err)
+	assert.Equal(t, "connections", pt.String())
+	assert.Equal(t, "connections.pprof", pt.Filename())
+	assert.Equal(t, "profile_type:connections", pt.Tag())
+
+	pt2, err := RegisterCustomProfile(CustomProfile{Name: "cache", Filename: "cache-entries.pprof"})
+	require.NoError(t, err)
+	assert.NotEqual(t, pt, pt2)
+	assert.Equal(t, "cache-entries.pprof", pt2.Filename())
+
+	_, err = RegisterCustomProfile(CustomProfile{})
+	assert.Error(t, err)
+	_, err = RegisterCustomProfile(CustomProfile{Name: "connections"})
+	assert.Error(t, err)
+	_, err = RegisterCustomProfile(CustomProfile{Name: "heap"})
+	assert.Error(t, err)
+
+	p, err := unstartedProfiler(WithProfileTypes(CPUProfile, pt2, pt))
+	require.NoError(t, err)
+	assert.Equal(t, []ProfileType{CPUProfile, MetricsProfile, pt, pt2}, p.enabledProfileTypes())
+}
+
+func TestCustomProfileLookup(t *testing.T) {
+	resetCustomProfiles(t)
+
+	prof := testConnectionsProfile
+	conn := new(int)
+	prof.Add(conn, 0)
+	defer prof.Remove(conn)
+
+	pt, err := RegisterCustomProfile(CustomProfile{Name: prof.Name(), Filename: "connections.pprof"})
+	require.NoError(t, err)
+	p, err := unstartedProfiler(WithProfileTypes(pt), WithPeriod(10*time.Millisecond))
+	require.NoError(t, err)
+
+	profs, err := p.runProfile(pt)
+	require.NoError(t, err)
+	require.Len(t, profs, 1)
+	assert.Equal(t, "connections.pprof", profs[0].name)
+	parsed, err := pprofile.ParseData(profs[0].data)
+	require.NoError(t, err)
+	require.Len(t, parsed.Sample, 1)
+	assert.Equal(t, int64(1), parsed.Sample[0].Value[0])
+}
+
+func TestCustomProfileCollect(t *testing.T) {
+	resetCustomProfiles(t)
+
+	var total int
+	pt, err := RegisterCustomProfile(CustomProfile{
+		Name: "cache-misses",
+		Collect: func() ([]byte, error) {
+			total += 10
+			var buf bytes.Buffer
+			prof := &pprofile.Profile{
+				SampleType: []*pprofile.ValueType{{Type: "misses", Unit: "count"}},
+				PeriodType: &pprofile.ValueType{},
+				Sample:     []*pprofile.Sample{{Value: []int64{int64(total)}}},
+			}
+			err := prof.Write(&buf)
+			return buf.Bytes(), err
+		},
+		DeltaValues: []ValueType{{Type: "misses", Unit: "count"}},
+	})
+	require.NoError(t, err)
+	p, err := unstartedProfiler(WithProfileTypes(pt), WithPeriod(10*time.Millisecond))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		profs, err := p.runProfile(pt)
+		require.NoError(t, err)
+		require.Len(t, profs, 1)
+		assert.Equal(t, "delta-cache-misses.pprof", profs[0].name)
+		parsed, err := pprofile.ParseData(profs[0].data)
+		require.NoError(t, err)
+		require.Len(t, parsed.Sample, 1)
+		assert.Equal(t, int64(10), parsed.Sample[0].Value[0])
+	}
+}
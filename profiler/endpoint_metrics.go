@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+
+	pprofile "github.com/google/pprof/profile"
+)
+
+// distributionClient is implemented by statsd clients which support
+// distribution metrics, such as the datadog-go statsd client. It is not part of
+// StatsdClient in order to not break existing implementations.
+type distributionClient interface {
+	// Distribution tracks the statistical distribution of a set of values.
+	Distribution(event string, value float64, tags []string, rate float64) error
+}
+
+// endpointMetric describes which sample value of a profile type is aggregated
+// per endpoint, and the name of the metric it is reported as.
+type endpointMetric struct {
+	Metric string
+	Value  pprofile.ValueType
+}
+
+// endpointMetrics maps the profile types from which per-endpoint metrics are
+// computed to the metrics they produce. Only profiles which carry the
+// "trace endpoint" pprof label yield metrics. Per-endpoint allocations are not
+// supported: the Go runtime does not record pprof labels in heap samples.
+var endpointMetrics = map[ProfileType]endpointMetric{
+	CPUProfile: {
+		Metric: "datadog.profiling.go.endpoint.cpu_time",
+		Value:  pprofile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+	},
+}
+
+// endpointValues sums the sample values of type vt in the given pprof data by
+// the value of their "trace endpoint" label. Samples without the label are
+// ignored.
+func endpointValues(data []byte, vt pprofile.ValueType) (map[string]int64, error) {
+	prof, err := pprofile.ParseData(data)
+	if err != nil {
+		return nil, err
+	}
+	idx := -1
+	for i, st := range prof.SampleType {
+		if st.Type == vt.Type && st.Unit == vt.Unit {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, fmt.Errorf("sample type %s/%s not found", vt.Type, vt.Unit)
+	}
+	values := make(map[string]int64)
+	for _, s := range prof.Sample {
+		endpoints := s.Label[traceprof.TraceEndpoint]
+		if len(endpoints) == 0 {
+			continue
+		}
+		values[endpoints[0]] += s.Value[idx]
+	}
+	return values, nil
+}
+
+// reportEndpointMetrics computes the CPU time per endpoint from the profiles in
+// bat, and reports them as statsd distributions tagged by resource. If endpoint
+// counts are available, the CPU time per request is reported as well.
+func (p *profiler) reportEndpointMetrics(bat batch) {
+	if !p.cfg.endpointMetricsEnabled {
+		return
+	}
+	client, ok := p.cfg.statsd.(distributionClient)
+	if !ok {
+		return
+	}
+	for _, prof := range bat.profiles {
+		em, ok := endpointMetrics[prof.pt]
+		if !ok {
+			continue
+		}
+		values, err := endpointValues(prof.data, em.Value)
+		if err != nil {
+			log.Debug("profiler: computing endpoint metrics for %s profile: %v", prof.pt, err)
+			continue
+		}
+		for endpoint, v := range values {
+			tags := append(p.cfg.tags.Slice(), "resource:"+endpoint)
+			client.Distribution(em.Metric, float64(v), tags, 1)
+			if prof.pt != CPUProfile {
+				continue
+			}
+			if perReq, ok := cpuTimePerRequest(v, bat.endpointCounts[endpoint], p.cfg.cpuDuration, bat.end.Sub(bat.start)); ok {
+				client.Distribution("datadog.profiling.go.endpoint.cpu_time_per_request", perReq, tags, 1)
+			}
+		}
+	}
+}
+
+// cpuTimePerRequest returns the CPU time in nanoseconds spent per hit of an
+// endpoint. The hits are counted over the whole profiling period, so they are
+// scaled down to the part of the period covered by the CPU profile.
+func cpuTimePerRequest(cpuTime int64, hits uint64, cpuDuration, period time.Duration) (float64, bool) {
+	if hits == 0 || period <= 0 {
+		return 0, false
+	}
+	scaledHits := float64(hits)
+	if cpuDuration < period {
+		scaledHits *= float64(cpuDuration) / float64(period)
+	}
+	if scaledHits <= 0 {
+		return 0, false
+	}
+	return float64(cpuTime) / scaledHits, true
+}
@@ -0,0 +1,129 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/traceprof"
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type distributionCall struct {
+	name  string
+	value float64
+	tags  []string
+}
+
+// distributionStatsd is a StatsdClient which records distribution calls.
+type distributionStatsd struct {
+	mu    sync.Mutex
+	calls []distributionCall
+}
+
+func (*distributionStatsd) Count(_ string, _ int64, _ []string, _ float64) error { return nil }
+
+func (*distributionStatsd) Timing(_ string, _ time.Duration, _ []string, _ float64) error {
+	return nil
+}
+
+func (d *distributionStatsd) Distribution(name string, value float64, tags []string, _ float64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls = append(d.calls, distributionCall{name: name, value: value, tags: tags})
+	return nil
+}
+
+// byResource returns the values reported for the given metric by resource tag.
+func (d *distributionStatsd) byResource(name string) map[string]float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	m := make(map[string]float64)
+	for _, c := range d.calls {
+		if c.name != name {
+			continue
+		}
+		for _, tag := range c.tags {
+			if r, ok := strings.CutPrefix(tag, "resource:"); ok {
+				m[r] = c.value
+			}
+		}
+	}
+	return m
+}
+
+// endpointTestProfile returns pprof data for the given folded text. The
+// samples are labeled with the endpoints given in order, an empty endpoint
+// leaves the sample unlabeled.
+func endpointTestProfile(t *testing.T, text string, endpoints ...string) []byte {
+	prof, err := pprofutils.Text{}.Convert(strings.NewReader(text))
+	require.NoError(t, err)
+	require.Len(t, prof.Sample, len(endpoints))
+	for i, s := range prof.Sample {
+		if endpoints[i] != "" {
+			s.Label = map[string][]string{traceprof.TraceEndpoint: {endpoints[i]}}
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, prof.Write(&buf))
+	return buf.Bytes()
+}
+
+func TestEndpointMetrics(t *testing.T) {
+	client := &distributionStatsd{}
+	p, err := unstartedProfiler(
+		WithEndpointMetrics(true),
+		WithStatsd(client),
+		WithPeriod(time.Minute),
+		CPUDuration(30*time.Second),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+	bat := batch{
+		start: start,
+		end:   start.Add(time.Minute),
+		profiles: []*profile{
+			{
+				name: "cpu.pprof",
+				pt:   CPUProfile,
+				data: endpointTestProfile(t, "samples/count cpu/nanoseconds\nmain;a 1 100\nmain;b 2 200\nmain;c 3 300\nmain;d 4 400\n",
+					"GET /foo", "GET /foo", "GET /bar", ""),
+			},
+			{
+				name: "delta-heap.pprof",
+				pt:   HeapProfile,
+				data: endpointTestProfile(t, "alloc_objects/count alloc_space/bytes\nmain;a 1 1024\nmain;b 2 2048\n",
+					"GET /foo", "GET /bar"),
+			},
+		},
+		endpointCounts: map[string]uint64{"GET /foo": 6},
+	}
+	p.reportEndpointMetrics(bat)
+
+	assert.Equal(t, map[string]float64{"GET /foo": 300, "GET /bar": 300},
+		client.byResource("datadog.profiling.go.endpoint.cpu_time"))
+	// heap profiles are ignored, as the runtime does not label their samples
+	assert.Empty(t, client.byResource("datadog.profiling.go.endpoint.alloc_bytes"))
+	// 6 hits over the full period, of which half is covered by the CPU profile.
+	assert.Equal(t, map[string]float64{"GET /foo": 100},
+		client.byResource("datadog.profiling.go.endpoint.cpu_time_per_request"))
+
+	t.Run("disabled", func(t *testing.T) {
+		client := &distributionStatsd{}
+		p, err := unstartedProfiler(WithStatsd(client))
+		require.NoError(t, err)
+		p.reportEndpointMetrics(bat)
+		assert.Empty(t, client.calls)
+	})
+}
@@ -111,8 +111,13 @@ type config struct {
 	logStartup           bool
 	traceConfig          executionTraceConfig
 	endpointCountEnabled bool
-	enabled              bool
-	flushOnExit          bool
+	// endpointMetricsEnabled enables per-endpoint CPU metrics
+	endpointMetricsEnabled bool
+	enabled                bool
+	flushOnExit            bool
+	compression            Compression
+	uploadSizeLimit        int // uploadSizeLimit is the max batch size in bytes, 0 means no limit
+	pgo                    pgoConfig
 }
 
 // logStartup records the configuration to the configured logger in JSON format
@@ -147,9 +152,15 @@ func logStartup(c *config) {
 		"execution_trace_period":     c.traceConfig.Period.String(),
 		"execution_trace_size_limit": c.traceConfig.Limit,
 		"endpoint_count_enabled":     c.endpointCountEnabled,
+		"endpoint_metrics_enabled":   c.endpointMetricsEnabled,
 		"custom_profiler_label_keys": c.customProfilerLabels,
 		"enabled":                    c.enabled,
 		"flush_on_exit":              c.flushOnExit,
+		"upload_compression":         c.compression,
+		"upload_size_limit":          c.uploadSizeLimit,
+		"pgo_enabled":                c.pgo.Enabled,
+		"pgo_path":                   c.pgo.Path,
+		"pgo_window":                 c.pgo.Window.String(),
 	}
 	b, err := json.Marshal(info)
 	if err != nil {
@@ -187,19 +198,22 @@ func (c *config) addProfileType(t ProfileType) {
 
 func defaultConfig() (*config, error) {
 	c := config{
-		apiURL:               defaultAPIURL,
-		service:              filepath.Base(os.Args[0]),
-		statsd:               &statsd.NoOpClient{},
-		httpClient:           defaultClient,
-		period:               DefaultPeriod,
-		cpuDuration:          DefaultDuration,
-		blockRate:            DefaultBlockRate,
-		mutexFraction:        DefaultMutexFraction,
-		uploadTimeout:        DefaultUploadTimeout,
-		maxGoroutinesWait:    1000, // arbitrary value, should limit STW to ~30ms
-		deltaProfiles:        internal.BoolEnv("DD_PROFILING_DELTA", true),
-		logStartup:           internal.BoolEnv("DD_TRACE_STARTUP_LOGS", true),
-		endpointCountEnabled: internal.BoolEnv(traceprof.EndpointCountEnvVar, false),
+		apiURL:                 defaultAPIURL,
+		service:                filepath.Base(os.Args[0]),
+		statsd:                 &statsd.NoOpClient{},
+		httpClient:             defaultClient,
+		period:                 DefaultPeriod,
+		cpuDuration:            DefaultDuration,
+		blockRate:              DefaultBlockRate,
+		mutexFraction:          DefaultMutexFraction,
+		uploadTimeout:          DefaultUploadTimeout,
+		maxGoroutinesWait:      1000, // arbitrary value, should limit STW to ~30ms
+		deltaProfiles:          internal.BoolEnv("DD_PROFILING_DELTA", true),
+		logStartup:             internal.BoolEnv("DD_TRACE_STARTUP_LOGS", true),
+		endpointCountEnabled:   internal.BoolEnv(traceprof.EndpointCountEnvVar, false),
+		endpointMetricsEnabled: internal.BoolEnv("DD_PROFILING_ENDPOINT_METRICS_ENABLED", false),
+		compression:            GzipCompression,
+		uploadSizeLimit:        internal.IntEnv("DD_PROFILING_UPLOAD_SIZE_LIMIT", 0),
 	}
 	c.tags = c.tags.Append(fmt.Sprintf("process_id:%d", os.Getpid()))
 	for _, t := range defaultProfileTypes {
@@ -243,6 +257,12 @@ func defaultConfig() (*config, error) {
 		WithVersion(v)(&c)
 	}
 	c.flushOnExit = internal.BoolEnv("DD_PROFILING_FLUSH_ON_EXIT", false)
+	if v := os.Getenv("DD_PROFILING_UPLOAD_COMPRESSION"); v != "" {
+		WithUploadCompression(Compression(strings.ToLower(v)))(&c)
+	}
+	if v := os.Getenv("DD_PROFILING_PGO_PATH"); v != "" {
+		WithPGOProfile(v, internal.DurationEnv("DD_PROFILING_PGO_WINDOW", DefaultPGOWindow))(&c)
+	}
 
 	tags := make(map[string]string)
 	if v := os.Getenv("DD_TAGS"); v != "" {
@@ -368,7 +388,8 @@ func BlockProfileRate(rate int) Option {
 	}
 }
 
-// WithProfileTypes specifies the profile types to be collected by the profiler.
+// WithProfileTypes specifies the profile types to be collected by the profiler,
+// including custom profile types returned by RegisterCustomProfile.
 func WithProfileTypes(types ...ProfileType) Option {
 	return func(cfg *config) {
 		// reset the types and only use what the user has specified
@@ -562,3 +583,61 @@ func WithCustomProfilerLabelKeys(keys ...string) Option {
 		cfg.customProfilerLabels = append(cfg.customProfilerLabels, keys...)
 	}
 }
+
+// WithPGOProfile enables the generation of a profile suitable for
+// profile-guided optimization (PGO). The CPU profiles collected during the
+// last window are merged, stripped of their labels, and written to path after
+// every profiling period. Pointing path to default.pgo in the main package
+// directory allows "go build -pgo=auto" to pick it up. If path is empty, the
+// profile is only made available via PGOHandler. A window <= 0 will cause an
+// error when starting the profiler. The CPU profile must be enabled.
+//
+// This option can also be set with the DD_PROFILING_PGO_PATH and
+// DD_PROFILING_PGO_WINDOW environment variables. The default window is
+// DefaultPGOWindow.
+func WithPGOProfile(path string, window time.Duration) Option {
+	return func(cfg *config) {
+		cfg.pgo = pgoConfig{Enabled: true, Path: path, Window: window}
+	}
+}
+
+// WithEndpointMetrics enables reporting the CPU time per endpoint as statsd
+// distributions after every profiling period. The metrics are computed from
+// the "trace endpoint" pprof labels, which requires the tracer's
+// WithProfilerEndpoints option (enabled by default), and are tagged with the
+// endpoint's resource name. If endpoint counting is enabled as well
+// (DD_PROFILING_ENDPOINT_COUNT_ENABLED), the CPU time per request is reported
+// too. Allocations per endpoint are not reported, as the Go runtime does not
+// record pprof labels in heap profiles.
+//
+// The statsd client given to WithStatsd must support distributions, like the
+// github.com/DataDog/datadog-go/v5/statsd client does. This option can also be
+// set with the DD_PROFILING_ENDPOINT_METRICS_ENABLED environment variable.
+func WithEndpointMetrics(enabled bool) Option {
+	return func(cfg *config) {
+		cfg.endpointMetricsEnabled = enabled
+	}
+}
+
+// WithUploadCompression specifies how pprof profiles are compressed before
+// they are uploaded. The default is GzipCompression. Using an unknown value
+// will cause an error when starting the profiler. This option can also be set
+// with the DD_PROFILING_UPLOAD_COMPRESSION environment variable.
+func WithUploadCompression(c Compression) Option {
+	return func(cfg *config) {
+		cfg.compression = c
+	}
+}
+
+// WithUploadSizeLimit specifies the maximum size in bytes of the profiles
+// uploaded after each profiling period. If a batch of profiles exceeds the
+// limit, the lowest priority profiles (execution traces, then goroutine, block,
+// mutex, heap and CPU profiles) are downsampled by keeping their heaviest
+// samples, or dropped if that isn't enough. A limit of 0, the default, disables
+// this behavior. This option can also be set with the
+// DD_PROFILING_UPLOAD_SIZE_LIMIT environment variable.
+func WithUploadSizeLimit(bytes int) Option {
+	return func(cfg *config) {
+		cfg.uploadSizeLimit = bytes
+	}
+}
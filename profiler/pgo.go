@@ -6,10 +6,22 @@
 package profiler
 
 import (
+	"bytes"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"runtime/debug"
+	"sync"
+	"time"
+
+	pprofile "github.com/google/pprof/profile"
 )
 
+// DefaultPGOWindow specifies the default amount of time over which CPU
+// profiles are merged into a PGO profile, see WithPGOProfile.
+const DefaultPGOWindow = time.Hour
+
 // pgoTag returns a tag indicating whether the program was built with
 // profile-guided optimization.
 func pgoTag() string {
@@ -29,3 +41,145 @@ func pgoEnabled() bool {
 	}
 	return false
 }
+
+// pgoConfig configures the generation of PGO profiles from collected CPU
+// profiles.
+type pgoConfig struct {
+	// Enabled indicates whether PGO profiles should be generated.
+	Enabled bool
+	// Path is the file the merged profile is written to. If empty, the
+	// profile is only served by PGOHandler.
+	Path string
+	// Window is the amount of time over which CPU profiles are merged.
+	Window time.Duration
+}
+
+// pgoCollector merges the CPU profiles collected during a sliding time window
+// into a single profile suitable for "go build -pgo".
+type pgoCollector struct {
+	path   string
+	window time.Duration
+
+	mu       sync.Mutex
+	profiles []pgoEntry // CPU profiles within the window, oldest first
+	merged   []byte     // latest merged profile, gzipped protobuf
+}
+
+// pgoEntry is a label-free CPU profile and the time it was collected at.
+type pgoEntry struct {
+	at   time.Time
+	prof *pprofile.Profile
+}
+
+func newPGOCollector(cfg pgoConfig) *pgoCollector {
+	return &pgoCollector{path: cfg.Path, window: cfg.Window}
+}
+
+// add records the given CPU profile (in pprof format) collected at time t,
+// evicts profiles which fell out of the window and regenerates the merged
+// PGO profile.
+func (c *pgoCollector) add(data []byte, t time.Time) error {
+	prof, err := pprofile.ParseData(data)
+	if err != nil {
+		return fmt.Errorf("parsing CPU profile: %v", err)
+	}
+	if len(prof.Sample) == 0 {
+		return nil
+	}
+	stripLabels(prof)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.profiles = append(c.profiles, pgoEntry{at: t, prof: prof})
+	var i int
+	for i < len(c.profiles)-1 && t.Sub(c.profiles[i].at) > c.window {
+		i++
+	}
+	clear(c.profiles[:i])
+	c.profiles = c.profiles[i:]
+
+	profs := make([]*pprofile.Profile, len(c.profiles))
+	for i, e := range c.profiles {
+		profs[i] = e.prof
+	}
+	merged, err := pprofile.Merge(profs)
+	if err != nil {
+		return fmt.Errorf("merging CPU profiles: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := merged.Write(&buf); err != nil {
+		return fmt.Errorf("encoding PGO profile: %v", err)
+	}
+	c.merged = buf.Bytes()
+	if c.path == "" {
+		return nil
+	}
+	return writeFileAtomic(c.path, c.merged)
+}
+
+// profile returns the latest merged PGO profile, or nil if no CPU profile has
+// been collected yet.
+func (c *pgoCollector) profile() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.merged
+}
+
+// stripLabels removes all pprof labels from the samples of prof. Labels are
+// not used by the compiler, and dropping them allows samples with identical
+// stacks to be aggregated, which considerably reduces the size of the merged
+// profile.
+func stripLabels(prof *pprofile.Profile) {
+	for _, s := range prof.Sample {
+		s.Label = nil
+		s.NumLabel = nil
+		s.NumUnit = nil
+	}
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path, so
+// that builds reading the file concurrently never see a partial profile.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	// 0644 is what touch does, should be reasonable for the use cases here.
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// PGOHandler returns an http.Handler serving the PGO profile generated by the
+// running profiler. The response can be saved as default.pgo in the main
+// package directory and used with "go build -pgo=auto". The handler responds
+// with 404 Not Found if the profiler is not running with WithPGOProfile, or if
+// no CPU profile has been collected yet.
+func PGOHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		var data []byte
+		if activeProfiler != nil && activeProfiler.pgo != nil {
+			data = activeProfiler.pgo.profile()
+		}
+		mu.Unlock()
+		if data == nil {
+			http.Error(w, "no PGO profile available", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="default.pgo"`)
+		w.Write(data)
+	})
+}
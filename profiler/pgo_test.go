@@ -12,6 +12,9 @@ import (
 	"go/parser"
 	"go/printer"
 	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,7 +22,11 @@ import (
 	"strings"
 	"testing"
 	"text/template"
+	"time"
 
+	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
+
+	pprofile "github.com/google/pprof/profile"
 	"github.com/stretchr/testify/require"
 )
 
@@ -114,3 +121,87 @@ func (pgoTestHelper) goRun(t *testing.T, src string, cpuProfile []byte) string {
 	require.NoError(t, cmd.Run(), "out=%s", out.String())
 	return strings.TrimSpace(out.String())
 }
+
+// pgoTestCPUProfile returns a profile in pprof format for the given folded
+// text, with a label attached to every sample.
+func pgoTestCPUProfile(t *testing.T, text string) []byte {
+	prof, err := pprofutils.Text{}.Convert(strings.NewReader(text))
+	require.NoError(t, err)
+	for _, s := range prof.Sample {
+		s.Label = map[string][]string{"trace endpoint": {"GET /foo"}}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, prof.Write(&buf))
+	return buf.Bytes()
+}
+
+func TestPGOCollector(t *testing.T) {
+	cpuProfile := pgoTestCPUProfile
+	readProfile := func(t *testing.T, data []byte) string {
+		prof, err := pprofile.ParseData(data)
+		require.NoError(t, err)
+		for _, s := range prof.Sample {
+			require.Empty(t, s.Label)
+		}
+		var buf bytes.Buffer
+		require.NoError(t, pprofutils.Protobuf{SampleTypes: true}.Convert(prof, &buf))
+		return buf.String()
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "default.pgo")
+		c := newPGOCollector(pgoConfig{Enabled: true, Path: path, Window: time.Hour})
+		start := time.Now()
+		require.NoError(t, c.add(cpuProfile(t, "main;foo 1\nmain;bar 2\n"), start))
+		require.NoError(t, c.add(cpuProfile(t, "main;foo 3\n"), start.Add(time.Minute)))
+
+		want := "samples/count\nmain;foo 4\nmain;bar 2\n"
+		require.Equal(t, want, readProfile(t, c.profile()))
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, want, readProfile(t, data))
+	})
+
+	t.Run("window", func(t *testing.T) {
+		c := newPGOCollector(pgoConfig{Enabled: true, Window: time.Hour})
+		start := time.Now()
+		require.NoError(t, c.add(cpuProfile(t, "main;foo 1\n"), start))
+		require.NoError(t, c.add(cpuProfile(t, "main;bar 2\n"), start.Add(30*time.Minute)))
+		require.NoError(t, c.add(cpuProfile(t, "main;bar 3\n"), start.Add(90*time.Minute)))
+		require.Equal(t, "samples/count\nmain;bar 5\n", readProfile(t, c.profile()))
+	})
+}
+
+func TestPGOHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	PGOHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	p, err := unstartedProfiler(
+		WithPGOProfile("", time.Hour),
+		WithPeriod(10*time.Millisecond),
+		CPUDuration(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	p.testHooks.startCPUProfile = func(w io.Writer) error {
+		_, err := w.Write(pgoTestCPUProfile(t, "main;foo 1\n"))
+		return err
+	}
+	p.testHooks.stopCPUProfile = func() {}
+	_, err = p.runProfile(CPUProfile)
+	require.NoError(t, err)
+
+	mu.Lock()
+	activeProfiler = p
+	mu.Unlock()
+	defer func() {
+		mu.Lock()
+		activeProfiler = nil
+		mu.Unlock()
+	}()
+	rec = httptest.NewRecorder()
+	PGOHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, err = pprofile.ParseData(rec.Body.Bytes())
+	require.NoError(t, err)
+}
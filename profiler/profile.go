@@ -16,6 +16,7 @@ import (
 	"runtime/trace"
 	"time"
 
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
 	"github.com/DataDog/dd-trace-go/v2/profiler/internal/fastdelta"
 	"github.com/DataDog/dd-trace-go/v2/profiler/internal/pprofutils"
 
@@ -55,6 +56,13 @@ const (
 	// This is private, as this trace requires special explicit configuration and
 	// shouldn't just be added to WithProfileTypes
 	executionTrace
+
+	// LabeledContentionProfile reports lock contention on profiler.Mutex and
+	// profiler.RWMutex, along with the pprof labels (span ID, endpoint) of the
+	// goroutines experiencing it. Unlike MutexProfile, it only covers locks
+	// using these types. On average, 1/rate contention events are recorded,
+	// with rate set by MutexProfileFraction. It is not enabled by default.
+	LabeledContentionProfile
 )
 
 // profileType holds the implementation details of a ProfileType.
@@ -185,6 +193,15 @@ var profileTypes = map[ProfileType]profileType{
 			return buf.Bytes(), err
 		},
 	},
+	LabeledContentionProfile: {
+		Name:     "labeled-contention",
+		Filename: "labeled-contention.pprof",
+		Collect: func(p *profiler) ([]byte, error) {
+			start := now()
+			p.interruptibleSleep(p.cfg.period)
+			return contention.flush(start, now())
+		},
+	},
 	executionTrace: {
 		Name:     "execution-trace",
 		Filename: "go.trace",
@@ -266,25 +283,32 @@ func collectGenericProfile(name string, pt ProfileType) func(p *profiler) ([]byt
 		var buf bytes.Buffer
 		err := p.lookupProfile(name, &buf, 0)
 		data := buf.Bytes()
-		dp, ok := p.deltas[pt]
-		if !ok || !p.cfg.deltaProfiles {
+		if _, ok := p.deltas[pt]; !ok || !p.cfg.deltaProfiles {
 			return data, err
 		}
+		return p.deltaProfile(pt, data)
+	}
+}
 
-		start := time.Now()
-		delta, err := dp.Delta(data)
-		tags := append(p.cfg.tags.Slice(), fmt.Sprintf("profile_type:%s", name))
-		p.cfg.statsd.Timing("datadog.profiling.go.delta_time", time.Since(start), tags, 1)
-		if err != nil {
-			return nil, fmt.Errorf("delta profile error: %s", err)
-		}
-		return delta, err
+// deltaProfile computes the delta between data and the previous profile of
+// type pt. The profiler must have a delta profiler for pt.
+func (p *profiler) deltaProfile(pt ProfileType, data []byte) ([]byte, error) {
+	start := time.Now()
+	delta, err := p.deltas[pt].Delta(data)
+	tags := append(p.cfg.tags.Slice(), pt.Tag())
+	p.cfg.statsd.Timing("datadog.profiling.go.delta_time", time.Since(start), tags, 1)
+	if err != nil {
+		return nil, fmt.Errorf("delta profile error: %s", err)
 	}
+	return delta, err
 }
 
 // lookup returns t's profileType implementation.
 func (t ProfileType) lookup() profileType {
 	c, ok := profileTypes[t]
+	if !ok {
+		c, ok = lookupCustomProfile(t)
+	}
 	if ok {
 		c.Type = t
 		return c
@@ -357,6 +381,11 @@ func (p *profiler) runProfile(pt ProfileType) ([]*profile, error) {
 		filename = "delta-" + filename
 	}
 	p.cfg.statsd.Timing("datadog.profiling.go.collect_time", end.Sub(start), tags, 1)
+	if pt == CPUProfile && p.pgo != nil {
+		if err := p.pgo.add(data, end); err != nil {
+			log.Error("Failed to generate PGO profile: %v", err)
+		}
+	}
 	return []*profile{{name: filename, pt: pt, data: data}}, nil
 }
 
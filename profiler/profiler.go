@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -93,6 +94,7 @@ type profiler struct {
 	wg              sync.WaitGroup    // wg waits for all goroutines to exit when stopping.
 	met             *metrics          // metric collector state
 	deltas          map[ProfileType]*fastDeltaProfiler
+	pgo             *pgoCollector  // pgo merges CPU profiles if WithPGOProfile is used
 	seq             uint64         // seq is the value of the profile_seq tag
 	pendingProfiles sync.WaitGroup // signal that profile collection is done, for stopping CPU profiling
 
@@ -203,13 +205,32 @@ func newProfiler(opts ...Option) (*profiler, error) {
 		return nil, fmt.Errorf("invalid upload timeout, must be > 0: %s", cfg.uploadTimeout)
 	}
 	for pt := range cfg.types {
-		if _, ok := profileTypes[pt]; !ok {
+		if !pt.known() {
 			return nil, fmt.Errorf("unknown profile type: %d", pt)
 		}
 	}
 	if cfg.cpuDuration > cfg.period {
 		cfg.cpuDuration = cfg.period
 	}
+	if !cfg.compression.valid() {
+		return nil, fmt.Errorf("unknown upload compression: %q", cfg.compression)
+	}
+	if cfg.uploadSizeLimit < 0 {
+		return nil, fmt.Errorf("invalid upload size limit, must be >= 0: %d", cfg.uploadSizeLimit)
+	}
+	if cfg.endpointMetricsEnabled {
+		if _, ok := cfg.statsd.(distributionClient); !ok {
+			log.Warn("Endpoint metrics require a statsd client supporting distributions, see WithStatsd. No endpoint metrics will be reported.")
+		}
+	}
+	if cfg.pgo.Enabled {
+		if _, ok := cfg.types[CPUProfile]; !ok {
+			log.Warn("PGO profile generation requires the CPU profile to be enabled, no PGO profile will be written.")
+		}
+		if cfg.pgo.Window <= 0 {
+			return nil, fmt.Errorf("invalid PGO window, must be > 0: %s", cfg.pgo.Window)
+		}
+	}
 	if cfg.logStartup {
 		logStartup(cfg)
 	}
@@ -242,10 +263,13 @@ func newProfiler(opts ...Option) (*profiler, error) {
 		deltas: make(map[ProfileType]*fastDeltaProfiler),
 	}
 	for pt := range cfg.types {
-		if d := profileTypes[pt].DeltaValues; len(d) > 0 {
+		if d := pt.lookup().DeltaValues; len(d) > 0 {
 			p.deltas[pt] = newFastDeltaProfiler(d...)
 		}
 	}
+	if cfg.pgo.Enabled {
+		p.pgo = newPGOCollector(cfg.pgo)
+	}
 	p.uploadFunc = p.upload
 	return &p, nil
 }
@@ -262,6 +286,9 @@ func (p *profiler) run() {
 	if profileEnabled(BlockProfile) {
 		runtime.SetBlockProfileRate(p.cfg.blockRate)
 	}
+	if profileEnabled(LabeledContentionProfile) {
+		contention.setRate(p.cfg.mutexFraction)
+	}
 	startTelemetry(p.cfg)
 	p.wg.Add(1)
 	go func() {
@@ -427,6 +454,7 @@ func (p *profiler) enabledProfileTypes() []ProfileType {
 		HeapProfile,
 		BlockProfile,
 		MutexProfile,
+		LabeledContentionProfile,
 		GoroutineProfile,
 		expGoroutineWaitProfile,
 		MetricsProfile,
@@ -438,7 +466,15 @@ func (p *profiler) enabledProfileTypes() []ProfileType {
 			enabled = append(enabled, t)
 		}
 	}
-	return enabled
+	// Custom profile types come last, in registration order.
+	var custom []ProfileType
+	for t := range p.cfg.types {
+		if t >= firstCustomProfileType {
+			custom = append(custom, t)
+		}
+	}
+	slices.Sort(custom)
+	return append(enabled, custom...)
 }
 
 // enqueueUpload pushes a batch of profiles onto the queue to be uploaded. If there is no room, it will
@@ -475,6 +511,7 @@ func (p *profiler) send() {
 			if !ok {
 				return
 			}
+			p.reportEndpointMetrics(bat)
 			if err := p.outputDir(bat); err != nil {
 				log.Error("Failed to output profile to dir: %v", err)
 			}
@@ -525,6 +562,9 @@ func (p *profiler) stop() {
 		close(p.exit)
 	})
 	p.wg.Wait()
+	if _, ok := p.cfg.types[LabeledContentionProfile]; ok {
+		contention.setRate(0)
+	}
 	if p.cfg.logStartup {
 		log.Info("Profiling stopped")
 	}
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/DataDog/dd-trace-go/v2/internal/log"
+
+	pprofile "github.com/google/pprof/profile"
+)
+
+// sizeLimitOrder lists the profile types in the order in which they are
+// downsampled or dropped when a batch exceeds the upload size limit, lowest
+// priority first. The metrics profile is tiny and never dropped.
+var sizeLimitOrder = []ProfileType{
+	executionTrace,
+	expGoroutineWaitProfile,
+	GoroutineProfile,
+	BlockProfile,
+	LabeledContentionProfile,
+	MutexProfile,
+	HeapProfile,
+	CPUProfile,
+}
+
+// batchSize returns the total size in bytes of the profiles in bat.
+func batchSize(bat batch) int {
+	var n int
+	for _, prof := range bat.profiles {
+		n += len(prof.data)
+	}
+	return n
+}
+
+// applySizeLimit makes sure the total size of the profiles in bat doesn't
+// exceed limit bytes. Profiles are considered in sizeLimitOrder. pprof profiles
+// are downsampled to fit the remaining budget if possible, and dropped
+// otherwise. The profiles of bat are modified in place.
+func (p *profiler) applySizeLimit(bat *batch, limit int) {
+	total := batchSize(*bat)
+	for _, pt := range sizeLimitOrder {
+		if total <= limit {
+			return
+		}
+		for i := 0; i < len(bat.profiles) && total > limit; i++ {
+			prof := bat.profiles[i]
+			if prof.pt != pt {
+				continue
+			}
+			tags := append(p.cfg.tags.Slice(), pt.Tag())
+			target := len(prof.data) - (total - limit)
+			if data, ok := p.downsample(prof.data, target); ok {
+				log.Warn("Profile batch exceeds upload size limit of %d bytes, downsampled %s profile from %d to %d bytes.", limit, pt, len(prof.data), len(data))
+				p.cfg.statsd.Count("datadog.profiling.go.upload_profile_downsampled", 1, tags, 1)
+				total -= len(prof.data) - len(data)
+				bat.profiles[i] = &profile{name: prof.name, pt: prof.pt, data: data}
+				continue
+			}
+			log.Warn("Profile batch exceeds upload size limit of %d bytes, dropped %s profile of %d bytes.", limit, pt, len(prof.data))
+			p.cfg.statsd.Count("datadog.profiling.go.upload_profile_dropped", 1, tags, 1)
+			total -= len(prof.data)
+			bat.profiles = append(bat.profiles[:i], bat.profiles[i+1:]...)
+			i--
+		}
+	}
+}
+
+// downsample tries to shrink the given compressed pprof profile to at most
+// target bytes by repeatedly halving its samples, keeping the heaviest ones.
+// It returns false if data is not a pprof profile or can't be shrunk enough.
+func (p *profiler) downsample(data []byte, target int) ([]byte, bool) {
+	if target <= 0 || !(isGzipData(data) || isZstdData(data)) {
+		return nil, false
+	}
+	raw, err := decompress(data)
+	if err != nil {
+		return nil, false
+	}
+	prof, err := pprofile.ParseUncompressed(raw)
+	if err != nil || len(prof.SampleType) == 0 {
+		return nil, false
+	}
+	// Sort by the last sample value, which is the most meaningful one for
+	// all runtime profiles (e.g. cpu/nanoseconds, delay/nanoseconds,
+	// inuse_space/bytes).
+	vi := len(prof.SampleType) - 1
+	sort.SliceStable(prof.Sample, func(i, j int) bool {
+		return prof.Sample[i].Value[vi] > prof.Sample[j].Value[vi]
+	})
+	n := len(prof.Sample)
+	for keep := n / 2; keep > 0; keep /= 2 {
+		prof.Sample = prof.Sample[:keep]
+		small := prof.Compact()
+		small.Comments = append(small.Comments, fmt.Sprintf("downsampled: kept %d of %d samples", keep, n))
+		var buf bytes.Buffer
+		if err := small.WriteUncompressed(&buf); err != nil {
+			return nil, false
+		}
+		compressed, err := compress(p.cfg.compression, buf.Bytes())
+		if err != nil {
+			return nil, false
+		}
+		if len(compressed) <= target {
+			return compressed, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025 Datadog, Inc.
+
+package profiler
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	pprofile "github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sizeLimitTestProfile returns a gzipped pprof profile with n samples of
+// distinct stacks, the i-th sample having the value i+1.
+func sizeLimitTestProfile(t *testing.T, n int) []byte {
+	var text strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&text, "main;func%d %d\n", i, i+1)
+	}
+	return pgoTestCPUProfile(t, text.String())
+}
+
+func TestApplySizeLimit(t *testing.T) {
+	newBatch := func() batch {
+		return batch{profiles: []*profile{
+			{name: "cpu.pprof", pt: CPUProfile, data: sizeLimitTestProfile(t, 10)},
+			{name: "goroutines.pprof", pt: GoroutineProfile, data: sizeLimitTestProfile(t, 1000)},
+			{name: "go.trace", pt: executionTrace, data: make([]byte, 1024)},
+			{name: "metrics.json", pt: MetricsProfile, data: []byte("[]")},
+		}}
+	}
+	names := func(bat batch) []string {
+		var names []string
+		for _, prof := range bat.profiles {
+			names = append(names, prof.name)
+		}
+		return names
+	}
+
+	t.Run("under-limit", func(t *testing.T) {
+		p, err := unstartedProfiler()
+		require.NoError(t, err)
+		bat := newBatch()
+		p.applySizeLimit(&bat, batchSize(bat))
+		assert.Equal(t, []string{"cpu.pprof", "goroutines.pprof", "go.trace", "metrics.json"}, names(bat))
+	})
+
+	t.Run("drop-trace", func(t *testing.T) {
+		p, err := unstartedProfiler()
+		require.NoError(t, err)
+		bat := newBatch()
+		p.applySizeLimit(&bat, batchSize(bat)-1)
+		assert.Equal(t, []string{"cpu.pprof", "goroutines.pprof", "metrics.json"}, names(bat))
+	})
+
+	t.Run("downsample", func(t *testing.T) {
+		p, err := unstartedProfiler()
+		require.NoError(t, err)
+		bat := newBatch()
+		goroutines := bat.profiles[1]
+		limit := batchSize(bat) - 1024 - len(goroutines.data)/2
+		p.applySizeLimit(&bat, limit)
+		require.Equal(t, []string{"cpu.pprof", "goroutines.pprof", "metrics.json"}, names(bat))
+		assert.LessOrEqual(t, batchSize(bat), limit)
+
+		prof, err := pprofile.ParseData(bat.profiles[1].data)
+		require.NoError(t, err)
+		require.NotEmpty(t, prof.Sample)
+		assert.Less(t, len(prof.Sample), 1000)
+		// the heaviest samples are kept
+		assert.Equal(t, int64(1000), prof.Sample[0].Value[0])
+	})
+
+	t.Run("drop-all-but-metrics", func(t *testing.T) {
+		p, err := unstartedProfiler()
+		require.NoError(t, err)
+		bat := newBatch()
+		p.applySizeLimit(&bat, 2)
+		assert.Equal(t, []string{"metrics.json"}, names(bat))
+	})
+}
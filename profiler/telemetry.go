@@ -40,6 +40,7 @@ func startTelemetry(c *config) {
 			{Name: "heap_profile_enabled", Value: profileEnabled(HeapProfile)},
 			{Name: "block_profile_enabled", Value: profileEnabled(BlockProfile)},
 			{Name: "mutex_profile_enabled", Value: profileEnabled(MutexProfile)},
+			{Name: "labeled_contention_profile_enabled", Value: profileEnabled(LabeledContentionProfile)},
 			{Name: "goroutine_profile_enabled", Value: profileEnabled(GoroutineProfile)},
 			{Name: "goroutine_wait_profile_enabled", Value: profileEnabled(expGoroutineWaitProfile)},
 			{Name: "upload_timeout", Value: c.uploadTimeout.String()},
@@ -47,9 +48,13 @@ func startTelemetry(c *config) {
 			{Name: "execution_trace_period", Value: c.traceConfig.Period.String()},
 			{Name: "execution_trace_size_limit", Value: c.traceConfig.Limit},
 			{Name: "endpoint_count_enabled", Value: c.endpointCountEnabled},
+			{Name: "endpoint_metrics_enabled", Value: c.endpointMetricsEnabled},
 			{Name: "num_custom_profiler_label_keys", Value: len(c.customProfilerLabels)},
 			{Name: "enabled", Value: c.enabled},
 			{Name: "flush_on_exit", Value: c.flushOnExit},
+			{Name: "upload_compression", Value: string(c.compression)},
+			{Name: "upload_size_limit", Value: c.uploadSizeLimit},
+			{Name: "pgo_enabled", Value: c.pgo.Enabled},
 		}...,
 	)
 	if telemetry.GlobalClient() == nil {
@@ -22,6 +22,7 @@ import (
 
 	"github.com/DataDog/dd-trace-go/v2/internal/log"
 	"github.com/DataDog/dd-trace-go/v2/internal/orchestrion"
+	"github.com/DataDog/dd-trace-go/v2/internal/telemetry"
 )
 
 // maxRetries specifies the maximum number of retries to have when an error occurs.
@@ -33,6 +34,7 @@ var errOldAgent = errors.New("Datadog Agent is not accepting profiles. Agent-bas
 // upload tries to upload a batch of profiles. It has retry and backoff mechanisms.
 func (p *profiler) upload(bat batch) error {
 	statsd := p.cfg.statsd
+	bat = p.prepareUpload(bat)
 	var err error
 	for i := 0; i < maxRetries; i++ {
 		select {
@@ -58,6 +60,7 @@ func (p *profiler) upload(bat batch) error {
 			var b int64
 			for _, p := range bat.profiles {
 				b += int64(len(p.data))
+				telemetry.Count(telemetry.NamespaceProfilers, "uploaded_bytes", []string{p.pt.Tag()}).Submit(float64(len(p.data)))
 			}
 			statsd.Count("datadog.profiling.go.uploaded_profile_bytes", b, nil, 1)
 		}
@@ -66,6 +69,29 @@ func (p *profiler) upload(bat batch) error {
 	return fmt.Errorf("failed after %d retries, last error was: %v", maxRetries, err)
 }
 
+// prepareUpload returns a copy of bat with its profiles re-compressed and
+// limited in size according to the configuration. The original batch is left
+// untouched, as it may still be written to the output directory.
+func (p *profiler) prepareUpload(bat batch) batch {
+	if p.cfg.compression == GzipCompression && p.cfg.uploadSizeLimit == 0 {
+		return bat
+	}
+	profiles := make([]*profile, 0, len(bat.profiles))
+	for _, prof := range bat.profiles {
+		data, err := recompress(p.cfg.compression, prof.data)
+		if err != nil {
+			log.Error("Failed to compress %s profile, uploading it as-is: %v", prof.pt, err)
+			data = prof.data
+		}
+		profiles = append(profiles, &profile{name: prof.name, pt: prof.pt, data: data})
+	}
+	bat.profiles = profiles
+	if p.cfg.uploadSizeLimit > 0 {
+		p.applySizeLimit(&bat, p.cfg.uploadSizeLimit)
+	}
+	return bat
+}
+
 // retriableError is an error returned by the server which may be retried at a later time.
 type retriableError struct{ err error }
 